@@ -499,6 +499,9 @@ func (fb *filterBackend) HeaderByNumber(ctx context.Context, block rpc.BlockNumb
 	if block == rpc.LatestBlockNumber {
 		return fb.bc.CurrentHeader(), nil
 	}
+	if block == rpc.FinalizedBlockNumber {
+		return fb.bc.CurrentFinalizedBlock().Header(), nil
+	}
 	return fb.bc.GetHeaderByNumber(uint64(block.Int64())), nil
 }
 
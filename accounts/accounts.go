@@ -39,6 +39,8 @@ const (
 	MimetypeDataWithValidator = "data/validator"
 	MimetypeTypedData         = "data/typed"
 	MimetypeClique            = "application/x-clique-header"
+	MimetypeTendermint        = "application/x-tendermint"
+	MimetypeTendermintVote    = "application/x-tendermint-vote"
 	MimetypeTextPlain         = "text/plain"
 )
 
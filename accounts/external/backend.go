@@ -34,6 +34,9 @@ import (
 	"github.com/lvbin2012/NeuralChain/signer/core"
 )
 
+// Scheme is the URL scheme of the accounts of external signers
+const Scheme = "extapi"
+
 type ExternalBackend struct {
 	signers []accounts.Wallet
 }
@@ -90,7 +93,7 @@ func NewExternalSigner(endpoint string) (*ExternalSigner, error) {
 
 func (api *ExternalSigner) URL() accounts.URL {
 	return accounts.URL{
-		Scheme: "extapi",
+		Scheme: Scheme,
 		Path:   api.endpoint,
 	}
 }
@@ -117,7 +120,7 @@ func (api *ExternalSigner) Accounts() []accounts.Account {
 	for _, addr := range res {
 		accnts = append(accnts, accounts.Account{
 			URL: accounts.URL{
-				Scheme: "extapi",
+				Scheme: Scheme,
 				Path:   api.endpoint,
 			},
 			Address: addr,
@@ -162,9 +165,12 @@ func (api *ExternalSigner) SignData(account accounts.Account, mimeType string, d
 		hexutil.Encode(data)); err != nil {
 		return nil, err
 	}
-	// If V is on 27/28-form, convert to to 0/1 for Clique
-	if mimeType == accounts.MimetypeClique && (res[64] == 27 || res[64] == 28) {
-		res[64] -= 27 // Transform V from 27/28 to 0/1 for Clique use
+	// If V is on 27/28-form, convert to to 0/1 for Clique and Tendermint
+	switch mimeType {
+	case accounts.MimetypeClique, accounts.MimetypeTendermint, accounts.MimetypeTendermintVote:
+		if res[64] == 27 || res[64] == 28 {
+			res[64] -= 27 // Transform V from 27/28 to 0/1 for Clique and Tendermint use
+		}
 	}
 	return res, nil
 }
@@ -194,6 +200,28 @@ func (api *ExternalSigner) SignTx(account accounts.Account, tx *types.Transactio
 		To:       &to,
 		From:     common.NewMixedcaseAddress(account.Address),
 	}
+	txType, msg, err := tx.DecodeExtraData()
+	if err != nil {
+		return nil, err
+	}
+	switch txType {
+	case types.NormalTxType:
+	case types.TransferOwnershipTxType:
+		newOwner := msg.(types.TransferOwnershipMsg).NewOwner
+		args.NewOwner = &newOwner
+	case types.AcceptOwnershipTxType:
+		args.AcceptOwnership = true
+	case types.SetProviderBudgetTxType, types.SetSenderAllowanceTxType:
+		limit := msg.(types.GasLimitMsg)
+		gasLimit := &neutapi.GasLimitArgs{Address: limit.Address, Limit: hexutil.Uint64(limit.Limit)}
+		if txType == types.SetProviderBudgetTxType {
+			args.ProviderBudget = gasLimit
+		} else {
+			args.SenderAllowance = gasLimit
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d for external signer", txType)
+	}
 
 	if err := api.client.Call(&res, "account_signTransaction", args); err != nil {
 		return nil, err
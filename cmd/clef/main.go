@@ -40,6 +40,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/cmd/utils"
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/console"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
@@ -429,7 +430,12 @@ func signer(c *cli.Context) error {
 	log.Info("Starting signer", "chainid", chainId, "keystore", ksLoc,
 		"light-kdf", lightKdf, "advanced", advanced)
 	am := core.StartClefAccountManager(ksLoc, nousb, lightKdf, scpath)
-	apiImpl := core.NewSignerAPI(am, chainId, nousb, ui, db, advanced, pwStorage)
+	// The last Tendermint votes are recorded to refuse double signing across restarts
+	votes, err := privval.NewGuard(filepath.Join(configDir, "tendermint_votes.json"))
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	apiImpl := core.NewSignerAPI(am, chainId, nousb, ui, db, advanced, pwStorage, votes)
 
 	// Establish the bidirectional communication, by creating a new UI backend and registering
 	// it with the UI.
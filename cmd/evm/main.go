@@ -99,6 +99,10 @@ var (
 		Name:  "sender",
 		Usage: "The transaction origin",
 	}
+	GasPayerFlag = cli.StringFlag{
+		Name:  "gaspayer",
+		Usage: "The account paying the gas, a provider sponsoring the call unless it is the sender (default = sender)",
+	}
 	ReceiverFlag = cli.StringFlag{
 		Name:  "receiver",
 		Usage: "The transaction receiver (execution context)",
@@ -136,6 +140,7 @@ func init() {
 		GenesisFlag,
 		MachineFlag,
 		SenderFlag,
+		GasPayerFlag,
 		ReceiverFlag,
 		DisableMemoryFlag,
 		DisableStackFlag,
@@ -115,6 +115,13 @@ func runCmd(ctx *cli.Context) error {
 	}
 	statedb.CreateAccount(sender)
 
+	gasPayer := sender
+	if ctx.GlobalString(GasPayerFlag.Name) != "" {
+		if addr, err := common.NeutAddressStringToAddressCheck(ctx.GlobalString(GasPayerFlag.Name)); err == nil {
+			gasPayer = addr
+		}
+	}
+
 	if ctx.GlobalString(ReceiverFlag.Name) != "" {
 		if addr, err := common.NeutAddressStringToAddressCheck(ctx.GlobalString(ReceiverFlag.Name));
 			err == nil {
@@ -168,6 +175,7 @@ func runCmd(ctx *cli.Context) error {
 	}
 	runtimeConfig := runtime.Config{
 		Origin:      sender,
+		GasPayer:    gasPayer,
 		State:       statedb,
 		GasLimit:    initialGas,
 		GasPrice:    utils.GlobalBig(ctx, PriceFlag.Name),
@@ -191,6 +191,7 @@ Use "neuralChain dump 0" to dump the genesis block.`,
 			utils.TendermintTimeoutCommitFlag,
 			utils.TendermintFaultyModeFlag,
 			utils.TendermintSCUseEVMCallerFlag,
+			utils.TendermintStorageLayoutFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 	}
@@ -91,6 +91,7 @@ var (
 		utils.TxPoolGlobalSlotsFlag,
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
+		utils.TxPoolProviderSlotsFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.ULCModeConfigFlag,
 		utils.OnlyAnnounceModeFlag,
@@ -150,6 +151,7 @@ var (
 		configFileFlag,
 		utils.TendermintBlockPeriodFlag,
 		utils.TendermintFaultyModeFlag,
+		utils.TendermintValidatorFlag,
 		utils.TendermintTimeoutProposeFlag,
 		utils.TendermintTimeoutProposeDeltaFlag,
 		utils.TendermintTimeoutPrevoteFlag,
@@ -158,6 +160,7 @@ var (
 		utils.TendermintTimeoutPrecommitDeltaFlag,
 		utils.TendermintTimeoutCommitFlag,
 		utils.TendermintSCUseEVMCallerFlag,
+		utils.TendermintStorageLayoutFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -423,8 +426,9 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		if err := stack.Service(&neuralChain); err != nil {
 			utils.Fatalf("NeuralChain service not running: %v", err)
 		}
-		// Set the gas price to the limits from the CLI and start mining
-		gasPrice := new(big.Int).Set(neuralChain.BlockChain().Config().GasPrice)
+		// Set the gas price to the minimum one of the pending block and start mining
+		blockchain := neuralChain.BlockChain()
+		gasPrice := new(big.Int).Set(blockchain.MinGasPrice(blockchain.CurrentHeader()))
 		neuralChain.TxPool().SetGasPrice(gasPrice)
 
 		threads := ctx.GlobalInt(utils.MinerLegacyThreadsFlag.Name)
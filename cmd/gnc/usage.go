@@ -131,6 +131,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolGlobalSlotsFlag,
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
+			utils.TxPoolProviderSlotsFlag,
 			utils.TxPoolLifetimeFlag,
 		},
 	},
@@ -259,6 +260,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TendermintTimeoutCommitFlag,
 			utils.TendermintFaultyModeFlag,
 			utils.TendermintSCUseEVMCallerFlag,
+			utils.TendermintStorageLayoutFlag,
+			utils.TendermintValidatorFlag,
 		},
 	},
 	{
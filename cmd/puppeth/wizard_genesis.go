@@ -36,6 +36,7 @@ import (
 	"golang.org/x/crypto/ed25519"
 
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
@@ -106,7 +107,7 @@ func (w *wizard) makeGenesis() {
 			copy(genesis.ExtraData[32+i*common.AddressLength:], signer[:])
 		}
 	case choice == "" || choice == "3":
-		fmt.Println("What is policy to select proposer (default 0 - roundrobin)")
+		fmt.Println("What is policy to select proposer (default 0 - roundrobin, 1 - sticky, 2 - proportional to stake)")
 		policy := uint64(w.readDefaultInt(0))
 		genesis.Config.Tendermint = &params.TendermintConfig{
 			ProposerPolicy: policy,
@@ -144,6 +145,23 @@ func (w *wizard) makeGenesis() {
 			log.Error("Failed to config staking SC", "error", err)
 			return
 		}
+		// the proportional to stake policy selects the proposers by the voting powers of the elected validators
+		if policy == uint64(tendermint.ProportionalToStake) {
+			if len(genesis.Config.Tendermint.FixedValidators) > 0 {
+				log.Error("The proportional to stake proposer policy requires validators elected by stake")
+				return
+			}
+			genesis.Config.Tendermint.WeightedVoting = true
+		}
+
+		fmt.Println()
+		fmt.Println("Do you want validators to vote on the chain parameters with a governance contract? (default = no)")
+		if w.readDefaultYesNo(false) {
+			if err := w.configGovernanceSC(genesis); err != nil {
+				log.Error("Failed to config governance SC", "error", err)
+				return
+			}
+		}
 
 		// RLP encode validator's address to bytes
 		valSetData, err := rlp.EncodeToBytes(validators)
@@ -25,6 +25,7 @@ import (
 
 const (
 	stakingSCName            = "NeuralChainStaking"
+	governanceSCName         = "NeuralChainGovernance"
 	simulatedGasLimit uint64 = 500000000
 	simulatedBalance         = simulatedGasLimit * params.GasPriceConfig
 )
@@ -86,10 +87,10 @@ func (w *wizard) configStakingSC(genesis *core.Genesis, validators []common.Addr
 	stakingSCParams = w.readStakingSCParams(genesis, validators)
 
 	fmt.Println()
-	fmt.Println("What is the address of staking smart contract? (avoid special address from 0x0000000000000000000000000000000000000001 to 0x0000000000000000000000000000000000000008)")
+	fmt.Println("What is the address of staking smart contract? (avoid special address from 0x0000000000000000000000000000000000000001 to 0x000000000000000000000000000000000000000a)")
 	for {
 		if expectedSCAddress = w.readAddress(); expectedSCAddress != nil {
-			if _, ok := vm.PrecompiledContractsOmaha[*expectedSCAddress]; !ok {
+			if !isPrecompiledAddress(*expectedSCAddress) {
 				break
 			}
 		}
@@ -105,6 +106,60 @@ func (w *wizard) configStakingSC(genesis *core.Genesis, validators []common.Addr
 	return nil
 }
 
+// configGovernanceSC deploys the governance smart contract at genesis, where the validators vote on the chain parameters
+func (w *wizard) configGovernanceSC(genesis *core.Genesis) error {
+	fmt.Println()
+	fmt.Println("Specify your governance smart contract path (default = ./consensus/governance_contracts/NeuralChainGovernance.sol)")
+	scPath := w.readDefaultString("./consensus/governance_contracts/NeuralChainGovernance.sol")
+
+	// the contract has no constructor, its runtime code is deployed as is
+	contracts, err := compiler.CompileSolidity("solc", scPath)
+	if err != nil {
+		return errors.Errorf("Failed to compile Solidity contract: %v", err)
+	}
+	ct := contracts[fmt.Sprintf("%s:%s", scPath, governanceSCName)]
+	if ct == nil || len(ct.RuntimeCode) == 0 {
+		return errors.Errorf("Not found any %s contract when compile SC", governanceSCName)
+	}
+
+	fmt.Println()
+	fmt.Println("What is the address of governance smart contract? (avoid special address from 0x0000000000000000000000000000000000000001 to 0x000000000000000000000000000000000000000a)")
+	var expectedSCAddress *common.Address
+	for {
+		if expectedSCAddress = w.readAddress(); expectedSCAddress != nil {
+			if isPrecompiledAddress(*expectedSCAddress) {
+				continue
+			}
+			if _, ok := genesis.Alloc[*expectedSCAddress]; !ok {
+				break
+			}
+		}
+	}
+
+	// the contract reads the validators from the validator set precompiled contract
+	if genesis.Config.ValidatorSetBlock == nil {
+		genesis.Config.ValidatorSetBlock = big.NewInt(0)
+	}
+	genesis.Config.Tendermint.GovernanceSCAddress = expectedSCAddress
+	genesis.Alloc[*expectedSCAddress] = core.GenesisAccount{
+		Balance: new(big.Int),
+		Code:    common.FromHex(ct.RuntimeCode),
+	}
+	return nil
+}
+
+// isPrecompiledAddress returns whether a pre-compiled contract, including the ones added by forks, is at the address
+func isPrecompiledAddress(addr common.Address) bool {
+	for _, precompiles := range []map[common.Address]vm.PrecompiledContract{
+		vm.PrecompiledContractsOmaha, vm.PrecompiledContractsValidatorSet, vm.PrecompiledContractsGasPayer,
+	} {
+		if _, ok := precompiles[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func createGenesisAccountWithStakingSC(genesis *core.Genesis, abiSC *abi.ABI, bytecodeSC string, validators []common.Address, stakingSCParams []interface{}) (core.GenesisAccount, error) {
 	//Deploy contract to simulated backend.
 	contractBackend, smlSCAddress, err := deployStakingSCToSimulatedBE(genesis, *abiSC, bytecodeSC, stakingSCParams)
@@ -0,0 +1,88 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of NeuralChain.
+//
+// NeuralChain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// NeuralChain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with NeuralChain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+)
+
+// auditEntry is the record of a relay request, the transaction was submitted if there is no error.
+type auditEntry struct {
+	Time     time.Time       `json:"time"`
+	Sender   common.Address  `json:"sender"`
+	Contract *common.Address `json:"contract"`
+	Hash     common.Hash     `json:"hash"`
+	Fee      *hexutil.Big    `json:"fee"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// auditLog is an append-only file of json encoded audit entries, one per line.
+type auditLog struct {
+	file *os.File
+	lock sync.Mutex
+}
+
+// openAuditLog opens the audit log file, creating it if needed, and returns the entries already recorded.
+func openAuditLog(path string) (*auditLog, []*auditEntry, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		entries []*auditEntry
+		scanner = bufio.NewScanner(file)
+	)
+	for scanner.Scan() {
+		entry := new(auditEntry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return &auditLog{file: file}, entries, nil
+}
+
+// write appends the entry to the log and flushes it to disk.
+func (l *auditLog) write(entry *auditEntry) error {
+	blob, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, err := l.file.Write(append(blob, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *auditLog) close() error {
+	return l.file.Close()
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of NeuralChain.
+//
+// NeuralChain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// NeuralChain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with NeuralChain. If not, see <http://www.gnu.org/licenses/>.
+
+// relay is a provider daemon co-signing and submitting the user transactions to sponsored enterprise contracts.
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/lvbin2012/NeuralChain/accounts/keystore"
+	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/neutclient"
+	"github.com/lvbin2012/NeuralChain/rpc"
+)
+
+var (
+	nodeFlag   = flag.String("node", "http://localhost:8545", "RPC endpoint of the node to submit the transactions to")
+	listenFlag = flag.String("listen", "localhost:8547", "Listener address of the relay JSON-RPC API")
+	corsFlag   = flag.String("cors", "", "Comma separated list of domains from which to accept cross origin requests")
+	vhostsFlag = flag.String("vhosts", "localhost", "Comma separated list of virtual hostnames from which to accept requests")
+
+	policyFlag = flag.String("policy", "", "Json file of the policy the relayed transactions are checked against")
+	auditFlag  = flag.String("audit", "relay-audit.log", "Audit log file of the relay requests")
+
+	accJSONFlag = flag.String("account.json", "", "Key json file of the provider co-signing the transactions")
+	accPassFlag = flag.String("account.pass", "", "Decryption password of the provider key")
+
+	logFlag = flag.Int("loglevel", 3, "Log level to use for the relay")
+)
+
+func main() {
+	// Parse the flags and set up the logger to print everything requested
+	flag.Parse()
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(*logFlag), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	policy, err := loadPolicy(*policyFlag)
+	if err != nil {
+		log.Crit("Failed to load the relay policy", "file", *policyFlag, "err", err)
+	}
+	audit, history, err := openAuditLog(*auditFlag)
+	if err != nil {
+		log.Crit("Failed to open the audit log", "file", *auditFlag, "err", err)
+	}
+	defer audit.close()
+
+	// Load up the provider key and decrypt it
+	blob, err := ioutil.ReadFile(*accPassFlag)
+	if err != nil {
+		log.Crit("Failed to read account password contents", "file", *accPassFlag, "err", err)
+	}
+	pass := strings.TrimSuffix(string(blob), "\n")
+
+	ks := keystore.NewKeyStore(filepath.Join(os.Getenv("HOME"), ".relay", "keys"), keystore.StandardScryptN, keystore.StandardScryptP)
+	if blob, err = ioutil.ReadFile(*accJSONFlag); err != nil {
+		log.Crit("Failed to read account key contents", "file", *accJSONFlag, "err", err)
+	}
+	acc, err := ks.Import(blob, pass, pass)
+	if err != nil {
+		log.Crit("Failed to import provider account", "err", err)
+	}
+	if err := ks.Unlock(acc, pass); err != nil {
+		log.Crit("Failed to unlock provider account", "err", err)
+	}
+
+	// Connect to the node and start serving the relay API
+	client, err := neutclient.Dial(*nodeFlag)
+	if err != nil {
+		log.Crit("Failed to connect to the node", "url", *nodeFlag, "err", err)
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Crit("Failed to retrieve the chain id", "err", err)
+	}
+	r := newRelay(policy, audit, history, client, ks, acc, chainID)
+
+	apis := []rpc.API{{
+		Namespace: "relay",
+		Version:   "1.0",
+		Service:   &PublicRelayAPI{r},
+		Public:    true,
+	}}
+	listener, _, err := rpc.StartHTTPEndpoint(*listenFlag, apis, []string{"relay"}, splitAndTrim(*corsFlag), splitAndTrim(*vhostsFlag), rpc.DefaultHTTPTimeouts)
+	if err != nil {
+		log.Crit("Failed to start the relay API", "err", err)
+	}
+	log.Info("Relay started", "provider", acc.Address, "url", "http://"+listener.Addr().String())
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+
+	listener.Close()
+	log.Info("Relay stopped")
+}
+
+// splitAndTrim splits the input separated by a comma and trims excessive white space from the substrings.
+func splitAndTrim(input string) []string {
+	var result []string
+	for _, r := range strings.Split(input, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			result = append(result, r)
+		}
+	}
+	return result
+}
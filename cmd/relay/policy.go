@@ -0,0 +1,218 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of NeuralChain.
+//
+// NeuralChain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// NeuralChain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with NeuralChain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/core/types"
+)
+
+var (
+	errContractCreation   = errors.New("contract creations are not relayed")
+	errUnsupportedTxType  = errors.New("only plain contract calls are relayed")
+	errContractNotAllowed = errors.New("contract not allowed by the relay policy")
+	errMethodNotAllowed   = errors.New("method not allowed by the relay policy")
+	errGasLimitExceeded   = errors.New("gas limit exceeds the relay policy")
+	errRateLimited        = errors.New("sender exceeded the relay rate limit")
+	errBudgetExceeded     = errors.New("relay budget exceeded")
+)
+
+// Policy is the set of rules a user transaction has to satisfy to be co-signed by the provider.
+type Policy struct {
+	Contracts []common.Address `json:"contracts"` // Enterprise contracts the provider sponsors
+	Methods   []hexutil.Bytes  `json:"methods"`   // 4-byte method selectors allowed to be called, all if empty
+	MaxGas    uint64           `json:"maxGas"`    // Maximum gas limit of a relayed transaction, unlimited if zero
+
+	RateLimit    int          `json:"rateLimit"`    // Maximum number of transactions of a sender per rate period, unlimited if zero
+	RatePeriod   uint64       `json:"ratePeriod"`   // Length of the rate period in seconds
+	Budget       *hexutil.Big `json:"budget"`       // Maximum fees in wei sponsored per budget period, unlimited if nil
+	BudgetPeriod uint64       `json:"budgetPeriod"` // Length of the budget period in seconds
+}
+
+// loadPolicy reads the json encoded policy from the file.
+func loadPolicy(file string) (*Policy, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	policy := new(Policy)
+	if err := json.Unmarshal(blob, policy); err != nil {
+		return nil, err
+	}
+	return policy, policy.validate()
+}
+
+func (p *Policy) validate() error {
+	if len(p.Contracts) == 0 {
+		return errors.New("no contract to relay")
+	}
+	for _, method := range p.Methods {
+		if len(method) != 4 {
+			return fmt.Errorf("invalid method selector %s", method)
+		}
+	}
+	if p.RateLimit > 0 && p.RatePeriod == 0 {
+		return errors.New("rate limit without a rate period")
+	}
+	if p.Budget != nil && p.BudgetPeriod == 0 {
+		return errors.New("budget without a budget period")
+	}
+	return nil
+}
+
+// check verifies the static rules of the policy, those not depending on the previously relayed transactions.
+func (p *Policy) check(tx *types.Transaction) error {
+	if tx.To() == nil {
+		return errContractCreation
+	}
+	if txType, _, err := tx.DecodeExtraData(); err != nil || txType != types.NormalTxType {
+		return errUnsupportedTxType
+	}
+	allowed := false
+	for _, contract := range p.Contracts {
+		if contract == *tx.To() {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errContractNotAllowed
+	}
+	if len(p.Methods) > 0 {
+		data := tx.Data()
+		if len(data) < 4 {
+			return errMethodNotAllowed
+		}
+		allowed = false
+		for _, method := range p.Methods {
+			if string(method) == string(data[:4]) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errMethodNotAllowed
+		}
+	}
+	if p.MaxGas > 0 && tx.Gas() > p.MaxGas {
+		return errGasLimitExceeded
+	}
+	return nil
+}
+
+// usage tracks the transactions relayed in the current periods of the policy, it is rebuilt
+// from the audit log when the relay is restarted.
+type usage struct {
+	policy *Policy
+
+	relayed map[common.Address][]time.Time // Times of the transactions relayed for each sender
+	spent   *big.Int                       // Fees sponsored in the current budget period
+	start   time.Time                      // Start of the current budget period
+
+	lock sync.Mutex
+}
+
+func newUsage(policy *Policy) *usage {
+	return &usage{
+		policy:  policy,
+		relayed: make(map[common.Address][]time.Time),
+		spent:   new(big.Int),
+	}
+}
+
+// reserve checks that relaying a transaction of the sender with the fee at the time fits the rate
+// limit and the budget of the policy, and accounts for it if so.
+func (u *usage) reserve(sender common.Address, fee *big.Int, now time.Time) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.expire(sender, now)
+	if u.policy.RateLimit > 0 && len(u.relayed[sender]) >= u.policy.RateLimit {
+		return errRateLimited
+	}
+	if u.policy.Budget != nil && new(big.Int).Add(u.spent, fee).Cmp(u.policy.Budget.ToInt()) > 0 {
+		return errBudgetExceeded
+	}
+	u.add(sender, fee, now)
+	return nil
+}
+
+// record accounts for a transaction relayed at the time without checking the policy.
+func (u *usage) record(sender common.Address, fee *big.Int, at time.Time) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.expire(sender, at)
+	u.add(sender, fee, at)
+}
+
+// release gives back the reservation of a transaction which could not be submitted.
+func (u *usage) release(sender common.Address, fee *big.Int, at time.Time) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.policy.Budget != nil && !at.Before(u.start) {
+		u.spent.Sub(u.spent, fee)
+	}
+	if times := u.relayed[sender]; len(times) > 0 && times[len(times)-1].Equal(at) {
+		u.relayed[sender] = times[:len(times)-1]
+	}
+}
+
+// expire drops the transactions of the sender out of the rate period and starts a new budget period if
+// the current one is over.
+func (u *usage) expire(sender common.Address, now time.Time) {
+	if u.policy.RateLimit > 0 {
+		var (
+			period = time.Duration(u.policy.RatePeriod) * time.Second
+			times  []time.Time
+		)
+		for _, t := range u.relayed[sender] {
+			if now.Sub(t) < period {
+				times = append(times, t)
+			}
+		}
+		if len(times) == 0 {
+			delete(u.relayed, sender)
+		} else {
+			u.relayed[sender] = times
+		}
+	}
+	if u.policy.Budget != nil {
+		if period := time.Duration(u.policy.BudgetPeriod) * time.Second; now.Sub(u.start) >= period {
+			u.start, u.spent = now.Truncate(period), new(big.Int)
+		}
+	}
+}
+
+func (u *usage) add(sender common.Address, fee *big.Int, at time.Time) {
+	if u.policy.RateLimit > 0 {
+		u.relayed[sender] = append(u.relayed[sender], at)
+	}
+	if u.policy.Budget != nil {
+		u.spent.Add(u.spent, fee)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of NeuralChain.
+//
+// NeuralChain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// NeuralChain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with NeuralChain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/accounts"
+	"github.com/lvbin2012/NeuralChain/accounts/keystore"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// backend is the connection to the node the co-signed transactions are submitted to.
+type backend interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// relay co-signs the user transactions to the sponsored contracts as their provider and submits them.
+type relay struct {
+	policy  *Policy
+	usage   *usage
+	audit   *auditLog
+	backend backend
+
+	keystore *keystore.KeyStore // Keystore containing the unlocked provider account
+	account  accounts.Account   // Provider account paying the gas of the relayed transactions
+	chainID  *big.Int
+	signer   types.Signer
+
+	now func() time.Time // Clock of the relay, replaced in tests
+}
+
+func newRelay(policy *Policy, audit *auditLog, history []*auditEntry, backend backend, ks *keystore.KeyStore, account accounts.Account, chainID *big.Int) *relay {
+	r := &relay{
+		policy:   policy,
+		usage:    newUsage(policy),
+		audit:    audit,
+		backend:  backend,
+		keystore: ks,
+		account:  account,
+		chainID:  chainID,
+		signer:   types.NewOmahaSigner(chainID),
+		now:      time.Now,
+	}
+	// Account for the transactions submitted before the restart
+	for _, entry := range history {
+		if entry.Error == "" {
+			r.usage.record(entry.Sender, entry.Fee.ToInt(), entry.Time)
+		}
+	}
+	return r
+}
+
+// submit checks the user signed transaction against the policy, co-signs it and submits it to the node.
+// Every request is recorded in the audit log, whether it is relayed or rejected.
+func (r *relay) submit(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	entry := &auditEntry{
+		Time:     r.now(),
+		Contract: tx.To(),
+		Hash:     tx.Hash(),
+		Fee:      (*hexutil.Big)(tx.TransactionFee()),
+	}
+	hash, err := r.relay(ctx, tx, entry)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := r.audit.write(entry); err != nil {
+		log.Error("Failed to write audit log", "hash", entry.Hash, "err", err)
+	}
+	return hash, err
+}
+
+func (r *relay) relay(ctx context.Context, tx *types.Transaction, entry *auditEntry) (common.Hash, error) {
+	sender, err := types.Sender(r.signer, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	entry.Sender = sender
+
+	if err := r.policy.check(tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := r.usage.reserve(sender, tx.TransactionFee(), entry.Time); err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := r.keystore.ProviderSignTx(r.account, tx, r.chainID)
+	if err == nil {
+		entry.Hash = signed.Hash()
+		err = r.backend.SendTransaction(ctx, signed)
+	}
+	if err != nil {
+		r.usage.release(sender, tx.TransactionFee(), entry.Time)
+		return common.Hash{}, err
+	}
+	log.Info("Relayed sponsored transaction", "sender", sender, "contract", tx.To(), "hash", entry.Hash)
+	return entry.Hash, nil
+}
+
+// PublicRelayAPI is the JSON-RPC API of the relay, exposed in the relay namespace.
+type PublicRelayAPI struct {
+	r *relay
+}
+
+// SendRawTransaction co-signs the rlp encoded transaction signed by the user and submits it.
+// It returns the hash of the co-signed transaction.
+func (api *PublicRelayAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return api.r.submit(ctx, tx)
+}
+
+// Provider returns the address of the provider co-signing the relayed transactions.
+func (api *PublicRelayAPI) Provider() common.Address {
+	return api.r.account.Address
+}
+
+// Policy returns the policy the relayed transactions are checked against.
+func (api *PublicRelayAPI) Policy() *Policy {
+	return api.r.policy
+}
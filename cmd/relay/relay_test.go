@@ -0,0 +1,188 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of NeuralChain.
+//
+// NeuralChain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// NeuralChain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with NeuralChain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/accounts/keystore"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+var (
+	testContract = common.Address{1}
+	testMethod   = hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb}
+	testChainID  = big.NewInt(1)
+)
+
+type testBackend struct {
+	sent []*types.Transaction
+	err  error
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func newTestTx(t *testing.T, nonce uint64, to common.Address, data []byte) *types.Transaction {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+	tx := types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(1), data)
+	tx, err = types.SignTx(tx, types.NewOmahaSigner(testChainID), key)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{
+		Contracts: []common.Address{testContract},
+		Methods:   []hexutil.Bytes{testMethod},
+		MaxGas:    100000,
+	}
+	require.NoError(t, policy.validate())
+
+	data := append(common.CopyBytes(testMethod), 1, 2, 3)
+	require.NoError(t, policy.check(newTestTx(t, 0, testContract, data)))
+	require.Equal(t, errContractNotAllowed, policy.check(newTestTx(t, 0, common.Address{2}, data)))
+	require.Equal(t, errMethodNotAllowed, policy.check(newTestTx(t, 0, testContract, []byte{1, 2, 3, 4})))
+	require.Equal(t, errMethodNotAllowed, policy.check(newTestTx(t, 0, testContract, nil)))
+
+	policy.MaxGas = 50000
+	require.Equal(t, errGasLimitExceeded, policy.check(newTestTx(t, 0, testContract, data)))
+
+	tx, err := types.NewModifyProvidersTransaction(0, testContract, 100000, big.NewInt(1), common.Address{3}, true)
+	require.NoError(t, err)
+	require.Equal(t, errUnsupportedTxType, policy.check(tx))
+
+	require.Error(t, (&Policy{}).validate())
+	require.Error(t, (&Policy{Contracts: policy.Contracts, Methods: []hexutil.Bytes{{1, 2}}}).validate())
+	require.Error(t, (&Policy{Contracts: policy.Contracts, RateLimit: 1}).validate())
+	require.Error(t, (&Policy{Contracts: policy.Contracts, Budget: (*hexutil.Big)(big.NewInt(1))}).validate())
+}
+
+func TestUsage(t *testing.T) {
+	var (
+		u = newUsage(&Policy{
+			RateLimit:    2,
+			RatePeriod:   60,
+			Budget:       (*hexutil.Big)(big.NewInt(100)),
+			BudgetPeriod: 3600,
+		})
+		alice = common.Address{1}
+		bob   = common.Address{2}
+		now   = time.Unix(36000, 0)
+		fee   = big.NewInt(30)
+	)
+	require.NoError(t, u.reserve(alice, fee, now))
+	require.NoError(t, u.reserve(alice, fee, now.Add(time.Second)))
+	require.Equal(t, errRateLimited, u.reserve(alice, fee, now.Add(2*time.Second)))
+
+	// the rate limit is per sender, the budget is shared
+	require.NoError(t, u.reserve(bob, fee, now.Add(2*time.Second)))
+	require.Equal(t, errBudgetExceeded, u.reserve(bob, fee, now.Add(3*time.Second)))
+	u.release(bob, fee, now.Add(2*time.Second))
+	require.NoError(t, u.reserve(bob, fee, now.Add(3*time.Second)))
+
+	// the rate period slides, the budget period is reset
+	require.Equal(t, errBudgetExceeded, u.reserve(alice, fee, now.Add(time.Minute)))
+	require.NoError(t, u.reserve(alice, fee, now.Add(time.Hour)))
+	require.Equal(t, big.NewInt(30), u.spent)
+}
+
+func TestRelay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relay-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keys"), keystore.LightScryptN, keystore.LightScryptP)
+	provider, err := ks.NewAccount("")
+	require.NoError(t, err)
+	require.NoError(t, ks.Unlock(provider, ""))
+
+	var (
+		path   = filepath.Join(dir, "audit.log")
+		policy = &Policy{
+			Contracts:  []common.Address{testContract},
+			RateLimit:  1,
+			RatePeriod: 60,
+		}
+		backend = new(testBackend)
+		now     = time.Unix(36000, 0)
+	)
+	audit, history, err := openAuditLog(path)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	r := newRelay(policy, audit, history, backend, ks, provider, testChainID)
+	r.now = func() time.Time { return now }
+
+	// a failed submission is not accounted for
+	backend.err = errors.New("nonce too low")
+	_, err = r.submit(context.Background(), newTestTx(t, 0, testContract, nil))
+	require.Equal(t, backend.err, err)
+
+	backend.err = nil
+	tx := newTestTx(t, 0, testContract, nil)
+	hash, err := r.submit(context.Background(), tx)
+	require.NoError(t, err)
+	require.Len(t, backend.sent, 1)
+	require.Equal(t, hash, backend.sent[0].Hash())
+
+	signer := types.NewOmahaSigner(testChainID)
+	sender, err := types.Sender(signer, backend.sent[0])
+	require.NoError(t, err)
+	signedProvider, err := types.Provider(signer, backend.sent[0])
+	require.NoError(t, err)
+	require.Equal(t, provider.Address, *signedProvider)
+
+	_, err = r.submit(context.Background(), newTestTx(t, 1, testContract, nil))
+	require.Equal(t, errRateLimited, err)
+	_, err = r.submit(context.Background(), newTestTx(t, 1, common.Address{2}, nil))
+	require.Equal(t, errContractNotAllowed, err)
+	require.NoError(t, audit.close())
+
+	// the usage is rebuilt from the audit log after a restart
+	audit, history, err = openAuditLog(path)
+	require.NoError(t, err)
+	defer audit.close()
+	require.Len(t, history, 4)
+	require.Equal(t, sender, history[1].Sender)
+	require.Equal(t, hash, history[1].Hash)
+	require.Empty(t, history[1].Error)
+	require.Equal(t, errRateLimited.Error(), history[2].Error)
+
+	r = newRelay(policy, audit, history, backend, ks, provider, testChainID)
+	r.now = func() time.Time { return now.Add(time.Second) }
+	_, err = r.submit(context.Background(), newTestTx(t, 1, testContract, nil))
+	require.Equal(t, errRateLimited, err)
+}
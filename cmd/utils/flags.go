@@ -42,6 +42,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	tdmintBackend "github.com/lvbin2012/NeuralChain/consensus/tendermint/backend"
 	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/vm"
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/dashboard"
@@ -330,6 +331,11 @@ var (
 		Usage: "Maximum number of non-executable transaction slots for all accounts",
 		Value: neut.DefaultConfig.TxPool.GlobalQueue,
 	}
+	TxPoolProviderSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.providerslots",
+		Usage: "Maximum number of executable and non-executable transaction slots sponsored by a provider",
+		Value: neut.DefaultConfig.TxPool.ProviderSlots,
+	}
 	TxPoolLifetimeFlag = cli.DurationFlag{
 		Name:  "txpool.lifetime",
 		Usage: "Maximum amount of time non-executable transaction are queued",
@@ -705,6 +711,16 @@ var (
 		Name:  "tendermint.use-evm-caller",
 		Usage: "The flag allowance reading data from stateDB or EVM",
 	}
+	TendermintStorageLayoutFlag = cli.StringFlag{
+		Name:  "tendermint.storagelayout",
+		Usage: "Storage layout output of solc for the staking contract, read by the StateDB caller instead of the default layout",
+		Value: "",
+	}
+	TendermintValidatorFlag = cli.StringFlag{
+		Name:  "tendermint.validator",
+		Usage: "Account of the keystore or of the external signer signing as validator (default = node key)",
+		Value: "",
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = cli.BoolFlag{
@@ -1280,6 +1296,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalQueueFlag.Name) {
 		cfg.GlobalQueue = ctx.GlobalUint64(TxPoolGlobalQueueFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolProviderSlotsFlag.Name) {
+		cfg.ProviderSlots = ctx.GlobalUint64(TxPoolProviderSlotsFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1363,6 +1382,13 @@ func setTendermint(ctx *cli.Context, cfg *tendermint.Config) {
 	if ctx.GlobalIsSet(TendermintSCUseEVMCallerFlag.Name) {
 		cfg.UseEVMCaller = true
 	}
+	if ctx.GlobalIsSet(TendermintStorageLayoutFlag.Name) {
+		indexConfigs, err := staking.LoadIndexConfigsFile(ctx.GlobalString(TendermintStorageLayoutFlag.Name), staking.DefaultContractName)
+		if err != nil {
+			Fatalf("Invalid tendermint storage layout: %v", err)
+		}
+		cfg.IndexStateVariables = indexConfigs
+	}
 
 	if ctx.GlobalIsSet(TendermintBlockPeriodFlag.Name) {
 		cfg.BlockPeriod = ctx.GlobalUint64(TendermintBlockPeriodFlag.Name)
@@ -1391,10 +1417,24 @@ func setTendermint(ctx *cli.Context, cfg *tendermint.Config) {
 	if ctx.GlobalIsSet(TendermintTimeoutCommitFlag.Name) {
 		cfg.TimeoutCommit = ctx.GlobalDuration(TendermintTimeoutCommitFlag.Name)
 	}
+	if ctx.GlobalIsSet(TendermintValidatorFlag.Name) {
+		validator, err := common.NeutAddressStringToAddressCheck(ctx.GlobalString(TendermintValidatorFlag.Name))
+		if err != nil {
+			Fatalf("Invalid tendermint validator: %v", err)
+		}
+		cfg.Validator = validator
+	}
 
 	if ctx.IsSet(TendermintSCUseEVMCallerFlag.Name) {
 		cfg.UseEVMCaller = true
 	}
+	if ctx.IsSet(TendermintStorageLayoutFlag.Name) {
+		indexConfigs, err := staking.LoadIndexConfigsFile(ctx.String(TendermintStorageLayoutFlag.Name), staking.DefaultContractName)
+		if err != nil {
+			Fatalf("Invalid tendermint storage layout: %v", err)
+		}
+		cfg.IndexStateVariables = indexConfigs
+	}
 
 	if ctx.IsSet(TendermintBlockPeriodFlag.Name) {
 		cfg.BlockPeriod = ctx.Uint64(TendermintBlockPeriodFlag.Name)
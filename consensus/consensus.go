@@ -23,6 +23,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/core/state"
 	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/neutdb"
 	"github.com/lvbin2012/NeuralChain/p2p"
 	"github.com/lvbin2012/NeuralChain/params"
 	"github.com/lvbin2012/NeuralChain/rpc"
@@ -169,6 +170,72 @@ type Tendermint interface {
 	Address() common.Address
 }
 
+// FinalityDepth is the number of blocks a block must be buried under to be considered final by the
+// consensus engines without deterministic finality. It is conservative, deeper reorgs are not expected.
+const FinalityDepth = 64
+
+// Finality should be implemented if the consensus decides itself when a block can no longer be reverted
+type Finality interface {
+	// FinalizedNumber returns the number of the latest final block given the head of the chain
+	FinalizedNumber(head *types.Header) uint64
+}
+
+// FinalizedNumber returns the number of the latest final block given the head of the chain.
+// The engines implementing Finality decide it themselves, for the others a block is final
+// once it is buried under FinalityDepth blocks.
+func FinalizedNumber(engine Engine, head *types.Header) uint64 {
+	if finality, ok := engine.(Finality); ok {
+		return finality.FinalizedNumber(head)
+	}
+	if number := head.Number.Uint64(); number > FinalityDepth {
+		return number - FinalityDepth
+	}
+	return 0
+}
+
+// Membership should be implemented if the consensus elects the validators sealing the blocks
+type Membership interface {
+	// EpochValidators returns the validators of the epoch of the header and their voting powers, as recorded in its
+	// epoch checkpoint header. The voting powers are empty if the votes of the validators are not weighted.
+	EpochValidators(chain ChainReader, header *types.Header) ([]common.Address, []*big.Int, error)
+
+	// ParentCommittedSeals returns the number of committed seals on the parent block of the header
+	ParentCommittedSeals(chain ChainReader, header *types.Header) (uint64, error)
+}
+
+// Epochs should be implemented if the consensus hands off the validator sets at epoch checkpoints
+type Epochs interface {
+	// LatestCheckpoint returns the latest epoch checkpoint header among the header and its ancestors
+	LatestCheckpoint(chain ChainReader, header *types.Header) (*types.Header, error)
+
+	// EpochLength returns the number of blocks of the epoch following the checkpoint header
+	EpochLength(checkpoint *types.Header) uint64
+}
+
+// Pricing should be implemented if the consensus governs the minimum gas price of the blocks
+type Pricing interface {
+	// MinGasPrice returns the minimum gas price of the block following the parent header, nil if it is not governed
+	MinGasPrice(chain ChainReader, parent *types.Header) *big.Int
+}
+
+// MinGasPrice returns the minimum gas price of the block following the parent header. The engines implementing
+// Pricing may govern it, otherwise it is the gas price of the chain config.
+func MinGasPrice(engine Engine, chain ChainReader, parent *types.Header) *big.Int {
+	if pricing, ok := engine.(Pricing); ok {
+		if price := pricing.MinGasPrice(chain, parent); price != nil {
+			return price
+		}
+	}
+	return chain.Config().GasPrice
+}
+
+// BlockDataWriter should be implemented if the consensus persists data about the blocks it finalizes.
+// As blocks are also finalized to verify them, the data must only be written with the blocks written to the chain.
+type BlockDataWriter interface {
+	// WriteBlockData writes the data of the consensus about the block written to the chain
+	WriteBlockData(db neutdb.KeyValueWriter, block *types.Block)
+}
+
 // Handler should be implemented is the consensus needs to handle and send peer's message
 type Handler interface {
 	// HandleNewChainHead handles a new head block comes
@@ -0,0 +1,140 @@
+pragma solidity 0.5.11;
+
+/**
+ * @title NeuralChainGovernance
+ * @dev The validators vote on the changes of the chain parameters. A change is approved once the current validators
+ * holding more than 2/3 of the voting power voted for it, each validator has one vote unless the votes are weighted
+ * by stake. A proposal can only be voted for during VOTING_PERIOD blocks, and approving it does not override a value
+ * approved by a newer proposal. The consensus engine reads the approved values at each epoch checkpoint and applies
+ * them from the next epoch.
+ *
+ * The consensus engine reads the storage of this contract directly (see core/state/staking/governance.go),
+ * so the order of the state variables must not change.
+ * The validators are given by the validator set precompiled contract, which requires the ValidatorSet fork.
+ */
+contract NeuralChainGovernance {
+    // the ids of the governed parameters, they must match the ones of the consensus engine
+    uint256 public constant BLOCK_REWARD = 0;      // in wei
+    uint256 public constant TIMEOUT_PROPOSE = 1;   // in milliseconds
+    uint256 public constant TIMEOUT_PREVOTE = 2;   // in milliseconds
+    uint256 public constant TIMEOUT_PRECOMMIT = 3; // in milliseconds
+    uint256 public constant TIMEOUT_COMMIT = 4;    // in milliseconds
+    uint256 public constant EPOCH = 5;             // in blocks
+    uint256 public constant GAS_PRICE = 6;         // in wei
+    uint256 public constant PARAMS_COUNT = 7;
+
+    // the number of blocks during which a proposal can be voted for
+    uint256 public constant VOTING_PERIOD = 100000;
+
+    // VALIDATOR_SET returns the validators of the current epoch, the proposer, the parent committed seals and the
+    // voting powers of the validators, which are empty unless the votes are weighted by stake
+    address constant VALIDATOR_SET = address(0x09);
+
+    struct Proposal {
+        uint256 param;
+        uint256 value;
+        uint256 deadline;
+        bool approved;
+        mapping(address => bool) voted;
+    }
+
+    uint256 public proposalCount;                        // slot 0
+    mapping(uint256 => Proposal) public proposals;       // slot 1
+    mapping(uint256 => uint256) public approvedValues;   // slot 2
+    mapping(uint256 => bool) public isApproved;          // slot 3
+    mapping(uint256 => uint256) public approvedProposal; // slot 4, the last proposal applied to each parameter
+
+    event Proposed(uint256 indexed id, uint256 indexed param, uint256 value, address proposer);
+    event Voted(uint256 indexed id, address voter);
+    event Approved(uint256 indexed id, uint256 indexed param, uint256 value);
+
+    modifier onlyValidator() {
+        require(isValidator(msg.sender), "only validators");
+        _;
+    }
+
+    /**
+     * @dev proposes to change a parameter, the proposer votes for it
+     */
+    function propose(uint256 _param, uint256 _value) external onlyValidator returns (uint256 id) {
+        require(_param < PARAMS_COUNT, "unknown parameter");
+        require(_param == BLOCK_REWARD || _value > 0, "zero value");
+        id = proposalCount;
+        proposalCount++;
+        Proposal storage proposal = proposals[id];
+        proposal.param = _param;
+        proposal.value = _value;
+        proposal.deadline = block.number + VOTING_PERIOD;
+        emit Proposed(id, _param, _value, msg.sender);
+        _vote(id);
+    }
+
+    /**
+     * @dev votes for a proposal, it is approved once the current validators holding more than 2/3 of the voting
+     * power voted for it
+     */
+    function vote(uint256 _id) external onlyValidator {
+        require(_id < proposalCount, "unknown proposal");
+        _vote(_id);
+    }
+
+    /**
+     * @dev returns the validators of the current epoch and their voting powers, empty if the votes are not weighted
+     */
+    function getValidators() public view returns (address[] memory validators, uint256[] memory powers) {
+        (bool success, bytes memory output) = VALIDATOR_SET.staticcall("");
+        require(success, "validator set unavailable");
+        (validators, , , powers) = abi.decode(output, (address[], address, uint256, uint256[]));
+        require(powers.length == 0 || powers.length == validators.length, "invalid voting powers");
+    }
+
+    function isValidator(address _addr) public view returns (bool) {
+        (address[] memory validators, ) = getValidators();
+        for (uint256 i = 0; i < validators.length; i++) {
+            if (validators[i] == _addr) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    function hasVoted(uint256 _id, address _voter) external view returns (bool) {
+        return proposals[_id].voted[_voter];
+    }
+
+    function _vote(uint256 _id) internal {
+        Proposal storage proposal = proposals[_id];
+        require(block.number <= proposal.deadline, "proposal expired");
+        require(!proposal.approved, "already approved");
+        require(!proposal.voted[msg.sender], "already voted");
+        proposal.voted[msg.sender] = true;
+        emit Voted(_id, msg.sender);
+
+        // only the votes of the current validators count, the validators change between epochs
+        (address[] memory validators, uint256[] memory powers) = getValidators();
+        uint256 votes = 0;
+        uint256 total = 0;
+        for (uint256 i = 0; i < validators.length; i++) {
+            uint256 power = 1;
+            if (powers.length > 0) {
+                power = powers[i];
+            }
+            total += power;
+            if (proposal.voted[validators[i]]) {
+                votes += power;
+            }
+        }
+        if (votes * 3 <= total * 2) {
+            return;
+        }
+        proposal.approved = true;
+        // a proposal approved late does not override the value of a newer proposal
+        if (isApproved[proposal.param] && approvedProposal[proposal.param] > _id) {
+            return;
+        }
+        approvedValues[proposal.param] = proposal.value;
+        isApproved[proposal.param] = true;
+        approvedProposal[proposal.param] = _id;
+        emit Approved(_id, proposal.param, proposal.value);
+    }
+}
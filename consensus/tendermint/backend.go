@@ -5,8 +5,10 @@ import (
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/event"
+	"github.com/lvbin2012/NeuralChain/params"
 )
 
 // Backend provides application specific functions for Tendermint core
@@ -21,6 +23,10 @@ type Backend interface {
 	// Sign signs input data with the backend's private key
 	Sign([]byte) ([]byte, error)
 
+	// SignVote signs the data of a proposal or vote with the backend's validator key, which may refuse to sign
+	// one conflicting with what it signed before
+	SignVote(req *privval.VoteRequest) ([]byte, error)
+
 	// Gossip sends a message to all validators (exclude self)
 	// these message are send via p2p network interface.
 	Gossip(valSet ValidatorSet, blockNumber *big.Int, round int64, msgType uint64, payload []byte) error
@@ -37,6 +43,12 @@ type Backend interface {
 	// we should only use this method when core is started.
 	Validators(blockNumber *big.Int) ValidatorSet
 
+	// GovernedParams returns the parameters approved by the governance contract for the epoch of the block number
+	GovernedParams(blockNumber *big.Int) []*types.GovernedParam
+
+	// ChainConfig returns the config of the chain, whose forks change the consensus rules
+	ChainConfig() *params.ChainConfig
+
 	// CurrentHeadBlock get the current block of from the canonical chain.
 	CurrentHeadBlock() *types.Block
 
@@ -57,4 +69,8 @@ type Backend interface {
 	// VerifyProposalBlock verify post-processor state of proposal block (txs, Root, receipt).
 	// If success, the result will be send to the pending tasks of miner
 	VerifyProposalBlock(block *types.Block) error
+
+	// AddEvidence verifies the evidence of a misbehaving validator and keeps it to be included in the next proposed blocks.
+	// It returns false if the evidence is already known.
+	AddEvidence(evidence *types.DuplicateVoteEvidence) (bool, error)
 }
@@ -1,12 +1,67 @@
 package backend
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
 	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	tendermintCore "github.com/lvbin2012/NeuralChain/consensus/tendermint/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/types"
 )
 
+// maxRewardEpochsPerQuery is the maximum number of epochs which the rewards of an address are looked up in
+const maxRewardEpochsPerQuery = 128
+
+var (
+	// errEpochRewardNotFound is returned if the reward of an epoch is not known, e.g. the epoch is not over yet
+	errEpochRewardNotFound = errors.New("epoch reward not found")
+	// errInvalidEpochRange is returned if the range of epochs to look up the rewards in is empty or too large
+	errInvalidEpochRange = errors.New("invalid epoch range")
+)
+
+// RPCEpochReward is the distribution of the rewards credited at the last block of an epoch
+type RPCEpochReward struct {
+	Epoch       uint64                `json:"epoch"`
+	BlockNumber hexutil.Uint64        `json:"blockNumber"`
+	Validators  []*RPCValidatorReward `json:"validators"`
+}
+
+// RPCValidatorReward is the reward a validator earned in an epoch and how it was shared
+type RPCValidatorReward struct {
+	Validator   common.Address    `json:"validator"`
+	BlockReward *hexutil.Big      `json:"blockReward"`
+	TxFee       *hexutil.Big      `json:"txFee"`
+	Owner       common.Address    `json:"owner"`
+	OwnerReward *hexutil.Big      `json:"ownerReward"`
+	Voters      []*RPCVoterReward `json:"voters"`
+	Jailed      bool              `json:"jailed"`
+}
+
+// RPCVoterReward is the share of a validator's reward paid to a voter
+type RPCVoterReward struct {
+	Voter  common.Address `json:"voter"`
+	Reward *hexutil.Big   `json:"reward"`
+}
+
+// RPCAddressReward is the reward an address received in an epoch as owner or voter of validators
+type RPCAddressReward struct {
+	Epoch       uint64            `json:"epoch"`
+	BlockNumber hexutil.Uint64    `json:"blockNumber"`
+	Total       *hexutil.Big      `json:"total"`
+	Shares      []*RPCRewardShare `json:"shares"`
+}
+
+// RPCRewardShare is a share of a validator's reward received by an address
+type RPCRewardShare struct {
+	Validator common.Address `json:"validator"`
+	Role      string         `json:"role"` // "owner" or "voter"
+	Amount    *hexutil.Big   `json:"amount"`
+}
+
 // TendermintAPI is a user facing RPC API to dump tendermint state
 type TendermintAPI struct {
 	chain consensus.ChainReader
@@ -30,3 +85,151 @@ func (api *TendermintAPI) GetValidators(number *uint64) []common.Address {
 	}
 	return validators
 }
+
+// GetConsensusState returns the current height, round and step of the consensus, the locked and valid block,
+// the bit-arrays of the prevotes and precommits received in each round, and the catch-up and rebroadcast state
+func (api *TendermintAPI) GetConsensusState() (*tendermintCore.ConsensusState, error) {
+	return api.be.ConsensusState()
+}
+
+// GetProposer returns the proposer of the given round of the block's number, the current block is used if number is nil
+func (api *TendermintAPI) GetProposer(number *uint64, round int64) (common.Address, error) {
+	var (
+		blockNumber *big.Int
+	)
+	if number == nil {
+		blockNumber = api.chain.CurrentHeader().Number
+	} else {
+		blockNumber = new(big.Int).SetUint64(*number)
+	}
+	if round < 0 {
+		return common.Address{}, tendermint.ErrInvalidRound
+	}
+	valSet := api.be.ValidatorsByChainReader(blockNumber, api.chain)
+	if valSet == nil || valSet.Size() == 0 {
+		return common.Address{}, tendermint.ErrEmptyValSet
+	}
+	// the validator set may be shared, so the proposer is calculated on a copy
+	valSet = valSet.Copy()
+	valSet.CalcProposer(valSet.GetProposer().Address(), round)
+	return valSet.GetProposer().Address(), nil
+}
+
+// GetEpochReward returns the distribution of the rewards of the given epoch.
+// The epoch n consists of the blocks following the (n-1)th epoch checkpoint up to the nth one, and its rewards are credited at its last block
+func (api *TendermintAPI) GetEpochReward(epoch uint64) (*RPCEpochReward, error) {
+	reward, err := api.epochReward(epoch)
+	if err != nil {
+		return nil, err
+	}
+	result := &RPCEpochReward{
+		Epoch:       epoch,
+		BlockNumber: hexutil.Uint64(reward.BlockNumber),
+		Validators:  make([]*RPCValidatorReward, 0, len(reward.Validators)),
+	}
+	for _, validatorReward := range reward.Validators {
+		voters := make([]*RPCVoterReward, 0, len(validatorReward.Voters))
+		for _, voterReward := range validatorReward.Voters {
+			voters = append(voters, &RPCVoterReward{
+				Voter:  voterReward.Voter,
+				Reward: (*hexutil.Big)(voterReward.Reward),
+			})
+		}
+		result.Validators = append(result.Validators, &RPCValidatorReward{
+			Validator:   validatorReward.Validator,
+			BlockReward: (*hexutil.Big)(validatorReward.BlockReward),
+			TxFee:       (*hexutil.Big)(validatorReward.TxFee),
+			Owner:       validatorReward.Owner,
+			OwnerReward: (*hexutil.Big)(validatorReward.OwnerReward),
+			Voters:      voters,
+			Jailed:      validatorReward.Jailed,
+		})
+	}
+	return result, nil
+}
+
+// GetRewardsByAddress returns the rewards the address received as owner or voter of validators in the epochs
+// from fromEpoch to toEpoch. The epochs without rewards for the address are skipped.
+// toEpoch defaults to the last finished epoch, and fromEpoch to the first of the maxRewardEpochsPerQuery epochs before it.
+func (api *TendermintAPI) GetRewardsByAddress(address common.Address, fromEpoch *uint64, toEpoch *uint64) ([]*RPCAddressReward, error) {
+	var (
+		to   = uint64(len(api.be.epochs.CanonicalCheckpoints(api.chain, api.chain.CurrentHeader().Number.Uint64())) - 1)
+		from uint64
+	)
+	if toEpoch != nil {
+		to = *toEpoch
+	}
+	if to+1 > maxRewardEpochsPerQuery {
+		from = to + 1 - maxRewardEpochsPerQuery
+	}
+	if fromEpoch != nil {
+		from = *fromEpoch
+	}
+	if from == 0 {
+		from = 1
+	}
+	if from > to || to-from >= maxRewardEpochsPerQuery {
+		return nil, errInvalidEpochRange
+	}
+
+	var results []*RPCAddressReward
+	for epoch := from; epoch <= to; epoch++ {
+		reward, err := api.epochReward(epoch)
+		if err == errEpochRewardNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result := &RPCAddressReward{
+			Epoch:       epoch,
+			BlockNumber: hexutil.Uint64(reward.BlockNumber),
+		}
+		total := new(big.Int)
+		addShare := func(validator common.Address, role string, amount *big.Int) {
+			result.Shares = append(result.Shares, &RPCRewardShare{
+				Validator: validator,
+				Role:      role,
+				Amount:    (*hexutil.Big)(amount),
+			})
+			total.Add(total, amount)
+		}
+		for _, validatorReward := range reward.Validators {
+			if validatorReward.Owner == address {
+				addShare(validatorReward.Validator, "owner", validatorReward.OwnerReward)
+			}
+			for _, voterReward := range validatorReward.Voters {
+				if voterReward.Voter == address {
+					addShare(validatorReward.Validator, "voter", voterReward.Reward)
+				}
+			}
+		}
+		if len(result.Shares) == 0 {
+			continue
+		}
+		result.Total = (*hexutil.Big)(total)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// epochReward reads the reward distribution of the epoch from the database
+func (api *TendermintAPI) epochReward(epoch uint64) (*types.EpochReward, error) {
+	if api.be.db == nil || epoch == 0 {
+		return nil, errEpochRewardNotFound
+	}
+	// the nth epoch ends at the nth checkpoint following the genesis
+	checkpoints := api.be.epochs.CanonicalCheckpoints(api.chain, api.chain.CurrentHeader().Number.Uint64())
+	if epoch >= uint64(len(checkpoints)) {
+		return nil, errEpochRewardNotFound
+	}
+	header := api.chain.GetHeaderByNumber(checkpoints[epoch])
+	if header == nil {
+		return nil, errEpochRewardNotFound
+	}
+	reward := rawdb.ReadEpochReward(api.be.db, header.Number.Uint64(), header.Root)
+	if reward == nil {
+		return nil, errEpochRewardNotFound
+	}
+	return reward, nil
+}
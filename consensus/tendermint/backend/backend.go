@@ -17,11 +17,13 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/backend/fixed_valset_info"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/backend/staking"
 	tendermintCore "github.com/lvbin2012/NeuralChain/consensus/tendermint/core"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core/types"
-	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/event"
 	"github.com/lvbin2012/NeuralChain/neutdb"
 	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/params"
 )
 
 const (
@@ -34,6 +36,7 @@ const (
 	broadcastSleepTimeIncreament = time.Millisecond * 100
 	inMemoryValset               = 10
 	inMemoryProposer             = 100
+	inMemoryRewards              = 16
 )
 
 var (
@@ -44,16 +47,45 @@ var (
 //Option return an optional function for backend's initial behaviour
 type Option func(b *Backend) error
 
+// WithWAL returns an option to persist the consensus state of core to the write-ahead log at the given path.
+// The WAL is replayed when core starts, so a restarted validator resumes at the same height, round, step and lock.
+func WithWAL(path string) Option {
+	return func(b *Backend) error {
+		wal, err := tendermintCore.OpenFileWAL(path)
+		if err != nil {
+			return err
+		}
+		b.wal = wal
+		return nil
+	}
+}
+
+// WithDB returns an option to set the database which the backend reads the epoch rewards from
+func WithDB(db neutdb.Database) Option {
+	return func(b *Backend) error {
+		b.db = db
+		return nil
+	}
+}
+
+// WithSigner returns an option to sign with a validator key separated from the node key
+func WithSigner(signer privval.Signer) Option {
+	return func(b *Backend) error {
+		b.signer = signer
+		return nil
+	}
+}
+
 // New creates an backend for Istanbul core engine.
 // The p2p communication, i.e, broadcaster is set separately by calling backend.SetBroadcaster
 func New(config *tendermint.Config, privateKey *ecdsa.PrivateKey, opts ...Option) consensus.Tendermint {
 	valSetCache, _ := lru.NewARC(inMemoryValset)
 	proposerCache, _ := lru.NewARC(inMemoryProposer)
+	rewardsCache, _ := lru.NewARC(inMemoryRewards)
 	be := &Backend{
 		config:                     config,
 		tendermintEventMux:         new(event.TypeMux),
-		privateKey:                 privateKey,
-		address:                    crypto.PubkeyToAddress(privateKey.PublicKey),
+		signer:                     privval.NewKeySigner(privateKey),
 		commitChs:                  newCommitChannels(),
 		mutex:                      &sync.RWMutex{},
 		storingMsgs:                queue.NewFIFO(),
@@ -62,24 +94,32 @@ func New(config *tendermint.Config, privateKey *ecdsa.PrivateKey, opts ...Option
 		controlChan:                make(chan struct{}),
 		computedValSetCache:        valSetCache,
 		blockProposerCache:         proposerCache,
+		finalizedRewards:           rewardsCache,
+		evidences:                  newEvidencePool(),
+		epochs:                     utils.NewEpochSchedule(config.Epoch),
 	}
 
 	if config.FixedValidators != nil && len(config.FixedValidators) > 0 {
 		be.valSetInfo = fixed_valset_info.NewFixedValidatorSetInfo(config.FixedValidators)
 	} else {
-		be.valSetInfo = staking.NewStakingValidatorInfo(config.Epoch, config.ProposerPolicy)
+		be.valSetInfo = staking.NewStakingValidatorInfo(be.epochs, config.ProposerPolicy)
 		if config.StakingSCAddress == nil {
 			panic("nil staking address")
 		}
 		be.stakingContractAddr = *config.StakingSCAddress
 	}
-	be.core = tendermintCore.New(be, config)
-
 	for _, opt := range opts {
 		if err := opt(be); err != nil {
 			log.Error("error at initialization of backend", err)
 		}
 	}
+	be.address = be.signer.Address()
+
+	var coreOpts []tendermintCore.Option
+	if be.wal != nil {
+		coreOpts = append(coreOpts, tendermintCore.WithWAL(be.wal))
+	}
+	be.core = tendermintCore.New(be, config, coreOpts...)
 
 	go be.dequeueMsgLoop()
 	return be
@@ -94,7 +134,7 @@ func (sb *Backend) SetBroadcaster(broadcaster consensus.Broadcaster) {
 type Backend struct {
 	config             *tendermint.Config
 	tendermintEventMux *event.TypeMux
-	privateKey         *ecdsa.PrivateKey
+	signer             privval.Signer // signer signs with the validator key, the node key by default
 	core               tendermintCore.Engine
 	db                 neutdb.Database
 	broadcaster        consensus.Broadcaster
@@ -123,6 +163,14 @@ type Backend struct {
 	computedValSetCache *lru.ARCCache  // computedValSetCache stores the valset is computed from stateDB
 
 	blockProposerCache *lru.ARCCache // blockProposerCache stores the address of proposal block
+
+	finalizedRewards *lru.ARCCache // finalizedRewards stores the epoch rewards of the finalized blocks by state root until they are written
+
+	epochs *utils.EpochSchedule // epochs finds the epoch checkpoint headers
+
+	wal tendermintCore.WAL // wal is the write-ahead log of core's consensus state
+
+	evidences *evidencePool // evidences stores the evidences of double signing waiting to be included in a block
 }
 
 // EventMux implements tendermint.Backend.EventMux
@@ -132,8 +180,12 @@ func (sb *Backend) EventMux() *event.TypeMux {
 
 // Sign implements tendermint.Backend.Sign
 func (sb *Backend) Sign(data []byte) ([]byte, error) {
-	hashData := crypto.Keccak256(data)
-	return crypto.Sign(hashData, sb.privateKey)
+	return sb.signer.Sign(data)
+}
+
+// SignVote implements tendermint.Backend.SignVote
+func (sb *Backend) SignVote(req *privval.VoteRequest) ([]byte, error) {
+	return sb.signer.SignVote(req)
 }
 
 // Address implements tendermint.Backend.Address
@@ -324,6 +376,17 @@ func (sb *Backend) Validators(blockNumber *big.Int) tendermint.ValidatorSet {
 	return valSet
 }
 
+// ConsensusState returns a snapshot of the consensus state of core, or ErrStoppedEngine if core is not running
+func (sb *Backend) ConsensusState() (*tendermintCore.ConsensusState, error) {
+	sb.mutex.RLock()
+	started := sb.coreStarted
+	sb.mutex.RUnlock()
+	if !started {
+		return nil, tendermint.ErrStoppedEngine
+	}
+	return sb.core.ConsensusState(), nil
+}
+
 // FindExistingPeers check validator peers exist or not by address
 func (sb *Backend) FindExistingPeers(valSet tendermint.ValidatorSet) map[common.Address]consensus.Peer {
 	targets := make(map[common.Address]bool)
@@ -348,6 +411,11 @@ func (sb *Backend) Cancel(block *types.Block) {
 	sb.commitChs.closeAndRemoveCommitChannel(block.Number().String())
 }
 
+// ChainConfig implements tendermint.Backend.ChainConfig
+func (sb *Backend) ChainConfig() *params.ChainConfig {
+	return sb.chain.Config()
+}
+
 func (sb *Backend) CurrentHeadBlock() *types.Block {
 	return sb.currentBlock()
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/validator"
 	neuralChainCore "github.com/lvbin2012/NeuralChain/core"
@@ -28,7 +29,7 @@ func TestSign(t *testing.T) {
 	privateKey, err := tests_utils.GeneratePrivateKey()
 	require.NoError(t, err)
 	b := &Backend{
-		privateKey: privateKey,
+		signer: privval.NewKeySigner(privateKey),
 	}
 	data := []byte("Here is a string....")
 	sig, err := b.Sign(data)
@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+)
+
+// addParentCommittedSealsToHeader writes the committed seals of parent to the header if the downtime tracking is enabled
+// from the Liveness fork on, so the liveness of the parent's validators is recorded from the same seals by every node.
+func (sb *Backend) addParentCommittedSealsToHeader(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header) error {
+	if !chainReader.Config().IsLiveness(header.Number) || chainReader.Config().Tendermint.DowntimeWindow == 0 ||
+		parent.Number.Sign() == 0 {
+		return nil
+	}
+	extra, err := types.ExtractTendermintExtra(parent)
+	if err != nil {
+		return err
+	}
+	return utils.WriteParentCommittedSeals(header, extra.CommittedSeal)
+}
+
+// parentSigners returns the parent's validators which signed the parent committed seals of the header.
+// It returns an error if the seals are not signed by a quorum of the parent's validators.
+func (sb *Backend) parentSigners(header *types.Header, valSet tendermint.ValidatorSet) (map[common.Address]bool, error) {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		signers      = make(map[common.Address]bool)
+		signersPower = new(big.Int)
+		proposalSeal = utils.PrepareCommittedSeal(header.ParentHash)
+	)
+	for _, seal := range extra.ParentCommittedSeal {
+		addr, err := utils.GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return nil, tendermint.ErrInvalidParentCommittedSeals
+		}
+		_, val := valSet.GetByAddress(addr)
+		if val == nil || signers[addr] {
+			return nil, tendermint.ErrInvalidParentCommittedSeals
+		}
+		signers[addr] = true
+		signersPower.Add(signersPower, val.VotingPower())
+	}
+	if signersPower.Cmp(valSet.QuorumVotingPower()) < 0 {
+		return nil, tendermint.ErrInvalidParentCommittedSeals
+	}
+	return signers, nil
+}
+
+// applyDowntime records which of the parent's validators missed to commit the parent block and jails the ones which
+// missed more than the allowed share of the downtime window, if any share is set. Jailed validators which sent an unjail
+// transaction in txs are released. The downtime is only tracked from the Liveness fork on.
+func (sb *Backend) applyDowntime(chainReader consensus.FullChainReader, stateDB *state.StateDB, header *types.Header,
+	txs []*types.Transaction) error {
+	var (
+		tendermintConfig = chainReader.Config().Tendermint
+		window           = tendermintConfig.DowntimeWindow
+		parentNumber     = new(big.Int).Sub(header.Number, common.Big1)
+	)
+	if window == 0 || !chainReader.Config().IsLiveness(header.Number) {
+		return nil
+	}
+
+	if parentNumber.Sign() > 0 {
+		valSet := sb.ValidatorsByChainReader(parentNumber, chainReader)
+		if valSet == nil {
+			return tendermint.ErrEmptyValSet
+		}
+		signers, err := sb.parentSigners(header, valSet)
+		if err != nil {
+			return err
+		}
+		for _, val := range valSet.List() {
+			missed := staking.RecordLiveness(stateDB, val.Address(), parentNumber.Uint64(), !signers[val.Address()], window)
+			if tendermintConfig.DowntimeJailPercentage == 0 || staking.IsJailed(stateDB, val.Address()) {
+				continue
+			}
+			if missed*100 > window*tendermintConfig.DowntimeJailPercentage {
+				staking.Jail(stateDB, val.Address())
+				log.Warn("jailed validator for downtime", "validator", val.Address(), "number", header.Number.Uint64(),
+					"missed", missed, "window", window)
+			}
+		}
+	}
+
+	signer := types.MakeSigner(chainReader.Config(), header.Number)
+	for _, tx := range txs {
+		if tx.To() == nil || *tx.To() != staking.ValidatorStatusAddress {
+			continue
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil || !staking.IsJailed(stateDB, sender) {
+			continue
+		}
+		staking.Unjail(stateDB, sender)
+		log.Info("unjailed validator", "validator", sender, "number", header.Number.Uint64())
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/backend/fixed_valset_info"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// TestApplyDowntime checks that a validator missing more than the allowed share of the downtime window is jailed
+// until it sends an unjail transaction, from the Liveness fork on
+func TestApplyDowntime(t *testing.T) {
+	var (
+		keys       = make([]*ecdsa.PrivateKey, 4)
+		validators = make([]common.Address, 4)
+	)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys[i], validators[i] = key, crypto.PubkeyToAddress(key.PublicKey)
+	}
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	var (
+		be = &Backend{
+			config:     tendermint.DefaultConfig,
+			valSetInfo: fixed_valset_info.NewFixedValidatorSetInfo(validators),
+		}
+		reader = &governanceChainReader{
+			config: &params.ChainConfig{ChainID: big.NewInt(1), LivenessBlock: big.NewInt(3), Tendermint: &params.TendermintConfig{
+				DowntimeWindow:         4,
+				DowntimeJailPercentage: 50,
+			}},
+			stateDB: stateDB,
+		}
+		offline = validators[3]
+		parent  = tests_utils.MakeGenesisHeader(validators)
+	)
+
+	// nextHeader returns the child of parent, committed by the validators except the offline one
+	nextHeader := func(t *testing.T) *types.Header {
+		var seals [][]byte
+		for _, key := range keys[:3] {
+			seal, err := crypto.Sign(crypto.Keccak256(utils.PrepareCommittedSeal(parent.Hash())), key)
+			require.NoError(t, err)
+			seals = append(seals, seal)
+		}
+		require.NoError(t, utils.WriteCommittedSeals(parent, seals))
+		header := tests_utils.MakeBlockWithoutSeal(parent).Header()
+		require.NoError(t, be.addParentCommittedSealsToHeader(reader, header, parent))
+		parent = header
+		return header
+	}
+
+	// the blocks before the fork record no seal and track nothing
+	nextHeader(t)
+	header := nextHeader(t)
+	extra, err := types.ExtractTendermintExtra(header)
+	require.NoError(t, err)
+	assert.Empty(t, extra.ParentCommittedSeal)
+	require.NoError(t, be.applyDowntime(reader, stateDB, header, nil))
+	assert.Equal(t, uint64(0), staking.MissedBlocks(stateDB, offline))
+
+	// the offline validator is jailed once it missed more than half of the window
+	for number := uint64(3); number <= 5; number++ {
+		header = nextHeader(t)
+		require.Equal(t, number, header.Number.Uint64())
+		require.NoError(t, be.applyDowntime(reader, stateDB, header, nil))
+		assert.Equal(t, number-2, staking.MissedBlocks(stateDB, offline))
+		assert.Equal(t, number == 5, staking.IsJailed(stateDB, offline))
+	}
+	for _, validator := range validators[:3] {
+		assert.Equal(t, uint64(0), staking.MissedBlocks(stateDB, validator))
+		assert.False(t, staking.IsJailed(stateDB, validator))
+	}
+
+	// the seals must be signed by a quorum of the parent's validators
+	header = nextHeader(t)
+	extra, err = types.ExtractTendermintExtra(header)
+	require.NoError(t, err)
+	require.Len(t, extra.ParentCommittedSeal, 3)
+	require.NoError(t, utils.WriteParentCommittedSeals(header, extra.ParentCommittedSeal[:2]))
+	assert.Equal(t, tendermint.ErrInvalidParentCommittedSeals, be.applyDowntime(reader, stateDB.Copy(), header, nil))
+
+	// only the jailed validator itself can unjail it
+	signer := types.MakeSigner(reader.config, header.Number)
+	unjail := func(key *ecdsa.PrivateKey) *types.Transaction {
+		tx, err := types.SignTx(staking.NewUnjailTransaction(0, 21000, big.NewInt(params.GasPriceConfig)), signer, key)
+		require.NoError(t, err)
+		return tx
+	}
+	header = nextHeader(t)
+	require.NoError(t, be.applyDowntime(reader, stateDB, header, []*types.Transaction{unjail(keys[0])}))
+	assert.True(t, staking.IsJailed(stateDB, offline))
+	header = nextHeader(t)
+	require.NoError(t, be.applyDowntime(reader, stateDB, header, []*types.Transaction{unjail(keys[3])}))
+	assert.False(t, staking.IsJailed(stateDB, offline))
+}
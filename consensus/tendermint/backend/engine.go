@@ -3,6 +3,7 @@ package backend
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 	"time"
@@ -11,7 +12,6 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
-	"github.com/lvbin2012/NeuralChain/consensus/tendermint/validator"
 	"github.com/lvbin2012/NeuralChain/core/state"
 	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
@@ -168,6 +168,11 @@ func (sb *Backend) Start(chain consensus.FullChainReader, currentBlock func() *t
 	}
 	sb.mutex.Unlock()
 
+	if err := sb.checkStakingCallers(chain, currentBlock().Header()); err != nil {
+		log.Error("refusing to start tendermint core", "error", err)
+		return err
+	}
+
 	//clear Previous start loop
 	select {
 	case sb.controlChan <- struct{}{}:
@@ -228,8 +233,12 @@ func (sb *Backend) VerifyProposalHeader(header *types.Header) error {
 	if sb.chain == nil {
 		return errors.New("no chain reader ")
 	}
+	isCheckpoint, err := sb.epochs.IsCheckpoint(sb.chain, header, nil)
+	if err != nil {
+		return err
+	}
 	// verify valSet in header is match with valSet from stateDB
-	if header.Number.Uint64()%sb.config.Epoch == 0 {
+	if isCheckpoint {
 		parent := sb.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
 		if parent == nil {
 			return tendermint.ErrUnknownParent
@@ -247,10 +256,41 @@ func (sb *Backend) VerifyProposalHeader(header *types.Header) error {
 		if !reflect.DeepEqual(validators, valSetInHeader) {
 			return tendermint.ErrMismatchValSet
 		}
+		if err := sb.verifyValidatorPowers(header, parent, validators); err != nil {
+			return err
+		}
 	}
 	return sb.verifyHeader(sb.chain, header, nil)
 }
 
+// verifyValidatorPowers checks that the voting powers of the validator set in the header are their stakes
+// at the state of the parent if the voting is weighted, and that there are none otherwise.
+func (sb *Backend) verifyValidatorPowers(header *types.Header, parent *types.Header, validators []common.Address) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return tendermint.ErrInvalidExtraDataFormat
+	}
+	if !sb.config.WeightedVoting || len(sb.config.FixedValidators) > 0 {
+		if len(extra.ValidatorPowers) > 0 {
+			return tendermint.ErrMismatchValSet
+		}
+		return nil
+	}
+	votingPowers, err := sb.getValidatorPowers(sb.chain, parent, validators)
+	if err != nil {
+		return err
+	}
+	if len(votingPowers) != len(extra.ValidatorPowers) {
+		return tendermint.ErrMismatchValSet
+	}
+	for i, power := range votingPowers {
+		if extra.ValidatorPowers[i] == nil || power.Cmp(extra.ValidatorPowers[i]) != 0 {
+			return tendermint.ErrMismatchValSet
+		}
+	}
+	return nil
+}
+
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
@@ -266,9 +306,18 @@ func (sb *Backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	}
 
 	// Ensure that the extra data format is satisfied
-	if _, err := types.ExtractTendermintExtra(header); err != nil {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
 		return tendermint.ErrInvalidExtraDataFormat
 	}
+	// Ensure that the evidences of double signing are only included from the Evidence fork on
+	if len(extra.Evidences) > 0 && !chain.Config().IsEvidence(header.Number) {
+		return tendermint.ErrUnexpectedEvidences
+	}
+	// Ensure that the parent committed seals are only recorded from the Liveness fork on
+	if len(extra.ParentCommittedSeal) > 0 && !chain.Config().IsLiveness(header.Number) {
+		return tendermint.ErrUnexpectedParentCommittedSeals
+	}
 
 	// Ensure that the mix digest is zero as we don't have fork protection currently
 	if header.MixDigest != types.TendermintDigest {
@@ -278,6 +327,26 @@ func (sb *Backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	if header.Difficulty == nil || header.Difficulty.Cmp(defaultDifficulty) != 0 {
 		return tendermint.ErrInvalidDifficulty
 	}
+	// Ensure that a checkpoint header hands off a validator set to the next epoch, so a syncing node verifying
+	// the headers one by one knows the validators sealing the next epoch without the state of the chain.
+	// The other headers must not, since the checkpoints are told apart by their validator set.
+	if header.Number.Sign() > 0 {
+		isCheckpoint, err := sb.epochs.IsCheckpoint(chain, header, parents)
+		if err != nil {
+			return err
+		}
+		if isCheckpoint {
+			if _, err := utils.GetValSetAddresses(header); err != nil {
+				return err
+			}
+		} else if utils.IsCheckpointHeader(header) {
+			return tendermint.ErrUnexpectedValSet
+		}
+		// Ensure that the validator set is covered by the block hash from the ValidatorSetHash fork on
+		if err := utils.VerifyValSetHash(header, chain.Config().IsValidatorSetHash(header.Number)); err != nil {
+			return err
+		}
+	}
 
 	return sb.verifyCascadingFields(chain, header, parents)
 }
@@ -319,55 +388,18 @@ func (sb *Backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	return sb.verifyCommittedSeals(header, valSet)
 }
 
-// getValSetFromChain returns the valset deprived from ChainReader and parents Headers
+// getValSetFromChain returns the validator set sealing the header, the one handed off by the checkpoint of its epoch
+// found among the ChainReader and the parents headers
 func (sb *Backend) getValSetFromChain(chain consensus.ChainReader, header *types.Header, parents []*types.Header) (tendermint.ValidatorSet, error) {
-	var (
-		blockNumber   = header.Number.Uint64()
-		checkpoint    = utils.GetCheckpointNumber(sb.config.Epoch, header.Number.Uint64())
-		hash          = header.ParentHash
-		number        = header.Number.Uint64() - 1
-		currentHeader = header
-	)
 	// if type of validator set is fixed, then use valsetInfo to get it
 	if len(sb.config.FixedValidators) > 0 {
-		return sb.valSetInfo.GetValSet(chain, big.NewInt(int64(blockNumber)))
+		return sb.valSetInfo.GetValSet(chain, header.Number)
 	}
-	// check if chain contains transition block, get it from historical data
-	if chain.CurrentHeader().Number.Uint64() >= checkpoint {
-		return sb.valSetInfo.GetValSet(chain, big.NewInt(int64(blockNumber)))
-	}
-	// check if parent headers contains transition block
-	for {
-		if len(parents) != 0 {
-			currentHeader = parents[len(parents)-1]
-			if currentHeader.Hash() != hash || currentHeader.Number.Uint64() != number {
-				return nil, consensus.ErrUnknownAncestor
-			}
-			parents = parents[:len(parents)-1]
-		} else {
-			currentHeader = chain.GetHeader(hash, number)
-			if currentHeader == nil {
-				return nil, consensus.ErrUnknownAncestor
-			}
-			if number%sb.config.Epoch != 0 {
-				currentHeaderOnChain := chain.GetHeaderByNumber(number)
-				if currentHeaderOnChain.Hash() == hash {
-					number = (number / sb.config.Epoch) * sb.config.Epoch
-					currentHeader = chain.GetHeaderByNumber(number)
-				}
-			}
-		}
-		if number%sb.config.Epoch == 0 {
-			validators, err := utils.GetValSetAddresses(currentHeader)
-			if err != nil {
-				return nil, err
-			}
-			return validator.NewSet(validators, sb.config.ProposerPolicy, int64(blockNumber)), nil
-		}
-		number, hash = number-1, currentHeader.ParentHash
+	checkpoint, err := sb.epochs.Checkpoint(chain, header, parents)
+	if err != nil {
+		return nil, err
 	}
-	// if the parents does not contain the transition block, return an error
-	return nil, tendermint.ErrUnknownBlock
+	return utils.GetValSet(checkpoint, sb.config.ProposerPolicy, header.Number.Int64())
 }
 
 // VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers
@@ -458,6 +490,18 @@ func (sb *Backend) Prepare(chain consensus.FullChainReader, header *types.Header
 		log.Error("failed to add val set to header", "err", err)
 	}
 
+	if err := sb.addEvidencesToHeader(chain, header, parent); err != nil {
+		log.Error("failed to add evidences to header", "err", err)
+	}
+
+	if err := sb.addParentCommittedSealsToHeader(chain, header, parent); err != nil {
+		log.Error("failed to add parent committed seals to header", "err", err)
+	}
+
+	if err := sb.addGovernedParamsToHeader(chain, header, parent); err != nil {
+		log.Error("failed to add governed params to header", "err", err)
+	}
+
 	return nil
 }
 
@@ -467,14 +511,31 @@ func (sb *Backend) Prepare(chain consensus.FullChainReader, header *types.Header
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) Finalize(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header) error {
+	// Check the parameters approved by the governance contract recorded at the checkpoint
+	if err := sb.verifyGovernedParams(chain, header); err != nil {
+		log.Error("failed to verifyGovernedParams", "err", err)
+		return err
+	}
+	// Slash the validators which signed conflicting votes
+	if err := sb.applyEvidences(chain, state, header); err != nil {
+		log.Error("failed to applyEvidences", "err", err)
+		return err
+	}
+	// Jail the validators which missed too many blocks
+	if err := sb.applyDowntime(chain, state, header, txs); err != nil {
+		log.Error("failed to applyDowntime", "err", err)
+		return err
+	}
 	// Accumulate any block rewards and commit the final state root
-	if err := sb.accumulateRewards(chain, state, header); err != nil {
+	epochReward, err := sb.accumulateRewards(chain, state, header)
+	if err != nil {
 		log.Error("failed to accumulateRewards", "err", err)
 		return err
 	}
 
 	// Since there is a change in stateDB, its trie must be update
 	header.Root = state.IntermediateRoot(true)
+	sb.finalizeEpochReward(header, epochReward)
 	return nil
 }
 
@@ -485,14 +546,31 @@ func (sb *Backend) Finalize(chain consensus.FullChainReader, header *types.Heade
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *Backend) FinalizeAndAssemble(chain consensus.FullChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	// Check the parameters approved by the governance contract recorded at the checkpoint
+	if err := sb.verifyGovernedParams(chain, header); err != nil {
+		log.Error("failed to verifyGovernedParams", "err", err)
+		return nil, err
+	}
+	// Slash the validators which signed conflicting votes
+	if err := sb.applyEvidences(chain, state, header); err != nil {
+		log.Error("failed to applyEvidences", "err", err)
+		return nil, err
+	}
+	// Jail the validators which missed too many blocks
+	if err := sb.applyDowntime(chain, state, header, txs); err != nil {
+		log.Error("failed to applyDowntime", "err", err)
+		return nil, err
+	}
 	// Accumulate any block rewards and commit the final state root
-	if err := sb.accumulateRewards(chain, state, header); err != nil {
+	epochReward, err := sb.accumulateRewards(chain, state, header)
+	if err != nil {
 		log.Error("failed to accumulateRewards", "err", err)
 		return nil, err
 	}
 
 	// No block rewards, so the state remains as is and uncles are dropped
 	header.Root = state.IntermediateRoot(true)
+	sb.finalizeEpochReward(header, epochReward)
 	header.UncleHash = types.CalcUncleHash(nil)
 
 	// Assemble and return the final block for sealing
@@ -509,6 +587,69 @@ func (sb *Backend) CalcDifficulty(chain consensus.ChainReader, time uint64, pare
 	return defaultDifficulty
 }
 
+// FinalizedNumber returns the number of the head, the blocks are only imported once they are
+// committed by more than 2/3 of the validators so they can never be reverted.
+func (sb *Backend) FinalizedNumber(head *types.Header) uint64 {
+	return head.Number.Uint64()
+}
+
+// EpochValidators returns the validators of the epoch of the header, which are the fixed validators or the ones
+// recorded in the epoch checkpoint header with their voting powers.
+func (sb *Backend) EpochValidators(chain consensus.ChainReader, header *types.Header) ([]common.Address, []*big.Int, error) {
+	if len(sb.config.FixedValidators) > 0 {
+		return append([]common.Address(nil), sb.config.FixedValidators...), nil, nil
+	}
+	checkpoint := sb.checkpointHeader(chain, header)
+	if checkpoint == nil {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	validators, err := utils.GetValSetAddresses(checkpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	extra, err := types.ExtractTendermintExtra(checkpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return validators, extra.ValidatorPowers, nil
+}
+
+// ParentCommittedSeals returns the number of committed seals on the parent block of the header,
+// the genesis block has no committed seals.
+func (sb *Backend) ParentCommittedSeals(chain consensus.ChainReader, header *types.Header) (uint64, error) {
+	if header.Number.Cmp(common.Big1) <= 0 {
+		return 0, nil
+	}
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
+	}
+	extra, err := types.ExtractTendermintExtra(parent)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(extra.CommittedSeal)), nil
+}
+
+// checkpointHeader returns the epoch checkpoint header among the ancestors of the header
+func (sb *Backend) checkpointHeader(chain consensus.ChainReader, header *types.Header) *types.Header {
+	checkpoint, err := sb.epochs.Checkpoint(chain, header, nil)
+	if err != nil {
+		return nil
+	}
+	return checkpoint
+}
+
+// LatestCheckpoint implements consensus.Epochs.LatestCheckpoint
+func (sb *Backend) LatestCheckpoint(chain consensus.ChainReader, header *types.Header) (*types.Header, error) {
+	return sb.epochs.LatestCheckpoint(chain, header, nil)
+}
+
+// EpochLength implements consensus.Epochs.EpochLength
+func (sb *Backend) EpochLength(checkpoint *types.Header) uint64 {
+	return sb.epochs.EpochLength(checkpoint)
+}
+
 // APIs will expose some RPC API methods
 func (sb *Backend) APIs(chain consensus.ChainReader) []rpc.API {
 	return []rpc.API{{
@@ -523,6 +664,9 @@ func (sb *Backend) APIs(chain consensus.ChainReader) []rpc.API {
 func (sb *Backend) Close() error {
 	close(sb.closingBackgroundThreadsCh)
 
+	if sb.wal != nil {
+		return sb.wal.Close()
+	}
 	return nil
 }
 
@@ -549,42 +693,11 @@ func (sb *Backend) verifyProposalSeal(header *types.Header, valSet tendermint.Va
 
 // verifyCommittedSeals checks whether every committed seal is signed by one of the parent's validators
 func (sb *Backend) verifyCommittedSeals(header *types.Header, valSet tendermint.ValidatorSet) error {
-	extra, err := types.ExtractTendermintExtra(header)
-	if err != nil {
-		return err
-	}
-	// The length of Committed seals should be larger than 0
-	if len(extra.CommittedSeal) == 0 {
-		return tendermint.ErrEmptyCommittedSeals
-	}
-
-	vals := valSet.Copy()
-	// Check whether the committed seals are generated by parent's validators
-	validSeal := 0
-	proposalSeal := utils.PrepareCommittedSeal(header.Hash())
-	// 1. Get committed seals from current header
-	for _, seal := range extra.CommittedSeal {
-		// 2. Get the original address by seal and parent block hash
-		addr, err := utils.GetSignatureAddress(proposalSeal, seal)
-		if err != nil {
-			log.Error("not a valid address", "err", err)
-			return tendermint.ErrInvalidSignature
-		}
-		// Every validator can have only one seal. If more than one seals are signed by a
-		// validator, the validator cannot be found and errInvalidCommittedSeals is returned.
-		if vals.RemoveValidator(addr) {
-			validSeal++
-		} else {
-			return tendermint.ErrInvalidCommittedSeals
-		}
-	}
-
-	// The length of validSeal should be larger or equal than min majority (num validator - maximum faulty)
-	if validSeal < valSet.MinMajority() {
-		return tendermint.ErrInvalidCommittedSeals
+	err := utils.VerifyCommittedSeals(header, valSet)
+	if err == tendermint.ErrInvalidSignature {
+		log.Error("not a valid address", "err", err)
 	}
-
-	return nil
+	return err
 }
 
 // blockProposer extracts the NeuralChain account address from a signed header.
@@ -611,12 +724,12 @@ func (sb *Backend) blockProposer(header *types.Header) (common.Address, error) {
 
 // addValSetToHeader Add validator set back to the tendermint extra.
 func (sb *Backend) addValSetToHeader(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header) error {
-	var (
-		blockNumber = header.Number.Uint64()
-		epoch       = sb.config.Epoch
-	)
-
-	if blockNumber%epoch != 0 {
+	blockNumber := header.Number.Uint64()
+	isCheckpoint, err := sb.epochs.IsCheckpoint(chainReader, header, nil)
+	if err != nil {
+		return err
+	}
+	if !isCheckpoint {
 		// ignore if this block is not the end of epoch
 		return nil
 	}
@@ -627,7 +740,52 @@ func (sb *Backend) addValSetToHeader(chainReader consensus.FullChainReader, head
 	}
 
 	log.Info("sets the val-set back to extra-data", "number", blockNumber)
-	return utils.WriteValSet(header, validators)
+	if err := utils.WriteValSet(header, validators); err != nil {
+		return err
+	}
+	if sb.config.WeightedVoting && len(sb.config.FixedValidators) == 0 {
+		votingPowers, err := sb.getValidatorPowers(chainReader, parent, validators)
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteValSetPowers(header, votingPowers); err != nil {
+			return err
+		}
+	}
+	if !chainReader.Config().IsValidatorSetHash(header.Number) {
+		return nil
+	}
+	return utils.WriteValSetHash(header)
+}
+
+// getValidatorPowers returns the voting power of the validators, which is their total stake at the state of header.
+func (sb *Backend) getValidatorPowers(chainReader consensus.FullChainReader, header *types.Header, validators []common.Address) ([]*big.Int, error) {
+	stateDB, err := chainReader.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	stakingCaller := sb.getStakingCaller(chainReader, stateDB, header)
+	validatorsData, err := stakingCaller.GetValidatorsData(sb.stakingContractAddr, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		votingPowers = make([]*big.Int, len(validators))
+		total        = new(big.Int)
+	)
+	for i, addr := range validators {
+		votingPowers[i] = new(big.Int)
+		if data, ok := validatorsData[addr]; ok && data.TotalStake != nil {
+			votingPowers[i].Set(data.TotalStake)
+		}
+		total.Add(total, votingPowers[i])
+	}
+	// a set without any voting power can not reach a quorum
+	if total.Sign() == 0 {
+		return nil, tendermint.ErrZeroVotingPower
+	}
+	return votingPowers, nil
 }
 
 func (sb *Backend) getNextValidatorSet(chainReader consensus.FullChainReader, header *types.Header) ([]common.Address, error) {
@@ -647,6 +805,7 @@ func (sb *Backend) getNextValidatorSet(chainReader consensus.FullChainReader, he
 	if err != nil {
 		return nil, err
 	}
+	validators = filterExcluded(stateDB, validators)
 	sb.computedValSetCache.Add(header.Number.Uint64(), validators)
 	log.Info("found new val set", "number", header.Number.Uint64(), "elapsed", common.PrettyDuration(time.Since(start)),
 		"valset", common.PrettyAddresses(validators))
@@ -667,6 +826,33 @@ func (sb *Backend) getStakingCaller(chainReader consensus.FullChainReader, state
 	}
 }
 
+// checkStakingCallers compares the results of the stateDB and the EVM staking callers at the given header.
+// They disagree if the index configuration of the state variables does not match the storage layout of the staking
+// contract, in which case the stateDB caller would elect wrong validators.
+func (sb *Backend) checkStakingCallers(chainReader consensus.FullChainReader, header *types.Header) error {
+	if sb.config.UseEVMCaller || sb.config.IndexStateVariables == nil || sb.stakingContractAddr == (common.Address{}) {
+		return nil
+	}
+	stateDB, err := chainReader.StateAt(header.Root)
+	if err != nil {
+		return err
+	}
+	if len(stateDB.GetCode(sb.stakingContractAddr)) == 0 {
+		return nil
+	}
+	evmCaller := staking.NewEVMStakingCaller(stateDB,
+		staking.NewChainContextWrapper(sb, chainReader.GetHeader),
+		header,
+		chainReader.Config(),
+		vm.Config{})
+	stateDBCaller := staking.NewStateDbStakingCaller(stateDB, sb.config.IndexStateVariables)
+	if err := staking.CompareCallers(evmCaller, stateDBCaller, sb.stakingContractAddr); err != nil {
+		return fmt.Errorf("index configuration does not match the staking contract at block %d: %v", header.Number.Uint64(), err)
+	}
+	log.Info("checked the index configuration of the staking contract", "number", header.Number.Uint64())
+	return nil
+}
+
 func (sb *Backend) prepareExtra(header *types.Header) []byte {
 	var (
 		tdm     *types.TendermintExtra
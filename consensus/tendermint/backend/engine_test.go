@@ -15,6 +15,9 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/crypto/secp256k1"
@@ -75,6 +78,113 @@ func TestAuthor(t *testing.T) {
 	assert.Equal(t, be.Address(), signer)
 }
 
+// TestFinalizedNumber checks that the head is final as the blocks are only imported once they are committed
+func TestFinalizedNumber(t *testing.T) {
+	var be consensus.Engine = &Backend{}
+	for _, number := range []int64{0, 1, consensus.FinalityDepth + 1} {
+		head := &types.Header{Number: big.NewInt(number)}
+		assert.Equal(t, uint64(number), consensus.FinalizedNumber(be, head))
+	}
+}
+
+// TestEpochValidators checks that the validators of a block are read from the checkpoint header of its epoch
+func TestEpochValidators(t *testing.T) {
+	var (
+		genesisValidators = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+		nextValidators    = []common.Address{common.HexToAddress("0x03")}
+		nextPowers        = []*big.Int{big.NewInt(5)}
+		genesisHeader     = tests_utils.MakeGenesisHeader(genesisValidators)
+		headers           = []*types.Header{genesisHeader}
+	)
+	for i := 1; i <= 4; i++ {
+		header := tests_utils.MakeBlockWithoutSeal(headers[i-1]).Header()
+		if i == 2 {
+			require.NoError(t, utils.WriteValSet(header, nextValidators))
+			require.NoError(t, utils.WriteValSetPowers(header, nextPowers))
+		}
+		headers = append(headers, header)
+	}
+	// the pending block is not in the chain yet
+	chain := tests_utils.NewHeadersMockChainReader(headers[:4])
+
+	cfg := *tendermint.DefaultConfig
+	cfg.Epoch = 2
+	cfg.FixedValidators = nil
+	be := &Backend{config: &cfg, epochs: utils.NewEpochSchedule(cfg.Epoch)}
+	for number, want := range [][]common.Address{genesisValidators, genesisValidators, genesisValidators, nextValidators, nextValidators} {
+		validators, powers, err := be.EpochValidators(chain, headers[number])
+		assert.NoError(t, err)
+		assert.Equal(t, want, validators, "block %d", number)
+		if number > 2 {
+			assert.Equal(t, nextPowers, powers, "block %d", number)
+		} else {
+			assert.Empty(t, powers, "block %d", number)
+		}
+	}
+
+	// the ancestors of a side block must be known
+	side := tests_utils.MakeBlockWithoutSeal(tests_utils.MakeBlockWithoutSeal(headers[4]).Header()).Header()
+	_, _, err := be.EpochValidators(chain, side)
+	assert.Equal(t, consensus.ErrUnknownAncestor, err)
+
+	// the fixed validators do not change and are not weighted
+	cfg.FixedValidators = nextValidators
+	validators, powers, err := be.EpochValidators(chain, headers[1])
+	assert.NoError(t, err)
+	assert.Equal(t, nextValidators, validators)
+	assert.Empty(t, powers)
+}
+
+// TestParentCommittedSeals checks that the committed seals on the parent are counted from the parent header
+func TestParentCommittedSeals(t *testing.T) {
+	var (
+		be            = &Backend{}
+		genesisHeader = tests_utils.MakeGenesisHeader([]common.Address{common.HexToAddress("0x01")})
+		parent        = tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+	)
+	require.NoError(t, utils.WriteCommittedSeals(parent, [][]byte{
+		make([]byte, types.TendermintExtraSeal), make([]byte, types.TendermintExtraSeal),
+	}))
+	header := tests_utils.MakeBlockWithoutSeal(parent).Header()
+	chain := tests_utils.NewHeadersMockChainReader([]*types.Header{genesisHeader, parent})
+
+	seals, err := be.ParentCommittedSeals(chain, parent)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), seals)
+
+	// the seals recorded in the header for the downtime tracking are not counted
+	require.NoError(t, utils.WriteParentCommittedSeals(header, [][]byte{make([]byte, types.TendermintExtraSeal)}))
+	seals, err = be.ParentCommittedSeals(chain, header)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), seals)
+
+	// the parent must be known
+	_, err = be.ParentCommittedSeals(chain, tests_utils.MakeBlockWithoutSeal(header).Header())
+	assert.Equal(t, consensus.ErrUnknownAncestor, err)
+}
+
+// TestVerifyValidatorPowers checks that a validator set without weighted voting hands off no voting powers
+func TestVerifyValidatorPowers(t *testing.T) {
+	var (
+		cfg        = *tendermint.DefaultConfig
+		be         = &Backend{config: &cfg}
+		validators = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+		parent     = tests_utils.MakeGenesisHeader(validators)
+		header     = tests_utils.MakeBlockWithoutSeal(parent).Header()
+	)
+	cfg.WeightedVoting = false
+	require.NoError(t, utils.WriteValSet(header, validators))
+	assert.NoError(t, be.verifyValidatorPowers(header, parent, validators))
+
+	require.NoError(t, utils.WriteValSetPowers(header, []*big.Int{big.NewInt(10), big.NewInt(1)}))
+	assert.Equal(t, tendermint.ErrMismatchValSet, be.verifyValidatorPowers(header, parent, validators))
+
+	// the fixed validators are not weighted either
+	cfg.WeightedVoting = true
+	cfg.FixedValidators = validators
+	assert.Equal(t, tendermint.ErrMismatchValSet, be.verifyValidatorPowers(header, parent, validators))
+}
+
 // TestPrepare
 func TestPrepare(t *testing.T) {
 	var (
@@ -276,3 +386,20 @@ func TestBackend_VerifyHeaders(t *testing.T) {
 		require.NoError(t, re)
 	}
 }
+
+// TestFilterExcluded checks that the jailed and tombstoned validators are excluded from the next validator set unless none is left
+func TestFilterExcluded(t *testing.T) {
+	var (
+		a = common.HexToAddress("0x01")
+		b = common.HexToAddress("0x02")
+		c = common.HexToAddress("0x03")
+	)
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	staking.Jail(stateDB, a)
+	staking.Tombstone(stateDB, b)
+	assert.Equal(t, []common.Address{c}, filterExcluded(stateDB, []common.Address{a, b, c}))
+
+	// an empty validator set could not seal any block
+	assert.Equal(t, []common.Address{a, b}, filterExcluded(stateDB, []common.Address{a, b}))
+}
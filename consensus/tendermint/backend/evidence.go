@@ -0,0 +1,227 @@
+package backend
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	tendermintCore "github.com/lvbin2012/NeuralChain/consensus/tendermint/core"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+)
+
+const (
+	maxEvidencesPerBlock = 16   // maximum number of evidences a proposer includes in a block
+	maxPendingEvidences  = 1024 // maximum number of evidences waiting to be included in a block
+)
+
+var (
+	// ErrEvidenceTooOld is returned when the conflicting votes of an evidence are older than an epoch
+	ErrEvidenceTooOld = errors.New("evidence is too old")
+	// ErrEvidenceFromFuture is returned when the conflicting votes of an evidence are not older than the block
+	ErrEvidenceFromFuture = errors.New("evidence is from future block")
+	// ErrOffenceAlreadySlashed is returned when the offender of an evidence has already been slashed for double
+	// signing at the block of the votes
+	ErrOffenceAlreadySlashed = errors.New("offence has already been slashed")
+	// ErrEvidenceOffenderNotValidator is returned when the offender of an evidence was not a validator at the block of the votes
+	ErrEvidenceOffenderNotValidator = errors.New("offender of evidence is not a validator")
+	// ErrEvidencePoolFull is returned when there are too many evidences waiting to be included in a block
+	ErrEvidencePoolFull = errors.New("evidence pool is full")
+	// ErrEvidenceNotActive is returned when an evidence is added before the Evidence fork
+	ErrEvidenceNotActive = errors.New("evidences are not active before the evidence fork")
+)
+
+// offence is the double signing of a validator at a block. The validator is slashed once for it,
+// however many pairs of conflicting votes it signed at that block.
+type offence struct {
+	offender common.Address
+	number   uint64
+}
+
+// evidencePool stores the verified evidences which have not been included in a block yet, one per offence
+type evidencePool struct {
+	mu      sync.Mutex
+	pending map[offence]*types.DuplicateVoteEvidence
+}
+
+func newEvidencePool() *evidencePool {
+	return &evidencePool{
+		pending: make(map[offence]*types.DuplicateVoteEvidence),
+	}
+}
+
+// AddEvidence implements tendermint.Backend.AddEvidence
+func (sb *Backend) AddEvidence(evidence *types.DuplicateVoteEvidence) (bool, error) {
+	next := new(big.Int).Add(sb.chain.CurrentHeader().Number, common.Big1)
+	if !sb.chain.Config().IsEvidence(next) {
+		return false, ErrEvidenceNotActive
+	}
+	offender, number, err := tendermintCore.VerifyDuplicateVoteEvidence(evidence)
+	if err != nil {
+		return false, err
+	}
+	key := offence{offender: offender, number: number.Uint64()}
+	sb.evidences.mu.Lock()
+	_, known := sb.evidences.pending[key]
+	full := len(sb.evidences.pending) >= maxPendingEvidences
+	sb.evidences.mu.Unlock()
+	if known {
+		return false, nil
+	}
+	if full {
+		return false, ErrEvidencePoolFull
+	}
+
+	valSet := sb.Validators(number)
+	if valSet == nil {
+		return false, ErrEvidenceOffenderNotValidator
+	}
+	if _, v := valSet.GetByAddress(offender); v == nil {
+		return false, ErrEvidenceOffenderNotValidator
+	}
+
+	sb.evidences.mu.Lock()
+	defer sb.evidences.mu.Unlock()
+	if _, known := sb.evidences.pending[key]; known {
+		return false, nil
+	}
+	sb.evidences.pending[key] = evidence
+	log.Warn("added evidence of double signing", "offender", offender, "number", number, "hash", evidence.Hash())
+	return true, nil
+}
+
+// addEvidencesToHeader writes the pending evidences which are still applicable on top of parent to the header.
+// Evidences whose offence was slashed or expired are dropped from the pool.
+func (sb *Backend) addEvidencesToHeader(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header) error {
+	if !chainReader.Config().IsEvidence(header.Number) {
+		return nil
+	}
+	sb.evidences.mu.Lock()
+	defer sb.evidences.mu.Unlock()
+	if len(sb.evidences.pending) == 0 {
+		return nil
+	}
+	stateDB, err := chainReader.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+
+	var evidences []*types.DuplicateVoteEvidence
+	for key, evidence := range sb.evidences.pending {
+		if _, _, err := sb.verifyEvidence(chainReader, stateDB, header, evidence); err != nil {
+			if err != ErrEvidenceFromFuture {
+				log.Debug("dropped evidence", "hash", evidence.Hash(), "err", err)
+				delete(sb.evidences.pending, key)
+			}
+			continue
+		}
+		if len(evidences) < maxEvidencesPerBlock {
+			evidences = append(evidences, evidence)
+		}
+	}
+	if len(evidences) == 0 {
+		return nil
+	}
+	log.Info("sets evidences to extra-data", "number", header.Number.Uint64(), "count", len(evidences))
+	return utils.WriteEvidences(header, evidences)
+}
+
+// verifyEvidence checks the evidence can be applied in the block of header and returns the offender and the
+// block number of the conflicting votes. The votes must be of an older block within an epoch, signed by a validator
+// of that block, and the offender must not have been slashed for that block yet.
+func (sb *Backend) verifyEvidence(chainReader consensus.ChainReader, stateDB *state.StateDB, header *types.Header,
+	evidence *types.DuplicateVoteEvidence) (common.Address, *big.Int, error) {
+	offender, number, err := tendermintCore.VerifyDuplicateVoteEvidence(evidence)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if number.Cmp(header.Number) >= 0 {
+		return common.Address{}, nil, ErrEvidenceFromFuture
+	}
+	checkpoint, err := sb.epochs.Checkpoint(chainReader, header, nil)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if new(big.Int).Sub(header.Number, number).Uint64() > sb.epochs.EpochLength(checkpoint) {
+		return common.Address{}, nil, ErrEvidenceTooOld
+	}
+	if staking.IsOffenceSlashed(stateDB, offender, number) {
+		return common.Address{}, nil, ErrOffenceAlreadySlashed
+	}
+	valSet := sb.ValidatorsByChainReader(number, chainReader)
+	if valSet == nil {
+		return common.Address{}, nil, ErrEvidenceOffenderNotValidator
+	}
+	if _, v := valSet.GetByAddress(offender); v == nil {
+		return common.Address{}, nil, ErrEvidenceOffenderNotValidator
+	}
+	return offender, number, nil
+}
+
+// applyEvidences slashes and tombstones the offenders of the evidences included in the header.
+// It returns an error if any evidence is invalid or its offence is slashed twice, so the block is rejected.
+func (sb *Backend) applyEvidences(chainReader consensus.FullChainReader, stateDB *state.StateDB, header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(extra.Evidences) == 0 {
+		return nil
+	}
+	if !chainReader.Config().IsEvidence(header.Number) {
+		return tendermint.ErrUnexpectedEvidences
+	}
+	if len(extra.Evidences) > maxEvidencesPerBlock {
+		return tendermint.ErrTooManyEvidences
+	}
+
+	var (
+		tendermintConfig = chainReader.Config().Tendermint
+		useStaking       = tendermintConfig.FixedValidators == nil && sb.config.IndexStateVariables != nil
+	)
+	for _, evidence := range extra.Evidences {
+		offender, number, err := sb.verifyEvidence(chainReader, stateDB, header, evidence)
+		if err != nil {
+			return errors.Wrapf(err, "invalid evidence %s", evidence.Hash().Hex())
+		}
+		slashed := new(big.Int)
+		if useStaking {
+			slashed = staking.Slash(stateDB, sb.config.IndexStateVariables, sb.stakingContractAddr, offender,
+				tendermintConfig.DoubleSignSlashPercentage)
+		}
+		staking.Tombstone(stateDB, offender)
+		staking.MarkOffenceSlashed(stateDB, offender, number)
+		log.Warn("slashed validator for double signing", "offender", offender, "offence", number,
+			"number", header.Number.Uint64(), "slashed", slashed)
+	}
+	return nil
+}
+
+// filterExcluded removes the tombstoned and jailed validators from the validator list.
+// The list is kept if every validator is excluded, an empty validator set could never seal a block again.
+func filterExcluded(stateDB *state.StateDB, validators []common.Address) []common.Address {
+	filtered := make([]common.Address, 0, len(validators))
+	for _, validator := range validators {
+		if staking.IsTombstoned(stateDB, validator) {
+			log.Info("skipped tombstoned validator", "validator", validator)
+			continue
+		}
+		if staking.IsJailed(stateDB, validator) {
+			log.Info("skipped jailed validator", "validator", validator)
+			continue
+		}
+		filtered = append(filtered, validator)
+	}
+	if len(filtered) == 0 && len(validators) > 0 {
+		log.Warn("all the validators are excluded, keeping them", "validators", len(validators))
+		return validators
+	}
+	return filtered
+}
@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/backend/fixed_valset_info"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/wire"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// signVote returns the signed prevote of the key for the block hash at the number and round, encoded as gossiped by core
+func signVote(t *testing.T, key *ecdsa.PrivateKey, number uint64, round int64, blockHash common.Hash) []byte {
+	msgData, err := rlp.EncodeToBytes(&wire.Vote{BlockHash: &blockHash, BlockNumber: new(big.Int).SetUint64(number), Round: round})
+	require.NoError(t, err)
+	msg := &wire.Message{Code: wire.MsgPrevote, Msg: msgData, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	payload, err := msg.PayLoadWithoutSignature()
+	require.NoError(t, err)
+	msg.Signature, err = crypto.Sign(crypto.Keccak256(payload), key)
+	require.NoError(t, err)
+	data, err := rlp.EncodeToBytes(msg)
+	require.NoError(t, err)
+	return data
+}
+
+// newEvidence returns the evidence of the key voting for two blocks at the number and round
+func newEvidence(t *testing.T, key *ecdsa.PrivateKey, number uint64, round int64) *types.DuplicateVoteEvidence {
+	return types.NewDuplicateVoteEvidence(
+		signVote(t, key, number, round, common.HexToHash("0xa")),
+		signVote(t, key, number, round, common.HexToHash("0xb")),
+	)
+}
+
+// newEvidenceBackend returns a backend with fixed validators and epochs of 2 blocks, on top of the chain of the
+// genesis, 3 blocks and the header of block 4 which is not in the chain yet
+func newEvidenceBackend(t *testing.T, validators []common.Address, evidenceBlock *big.Int) (*Backend, *governanceChainReader, *types.Header) {
+	cfg := *tendermint.DefaultConfig
+	cfg.Epoch = 2
+	be := &Backend{
+		config:     &cfg,
+		epochs:     utils.NewEpochSchedule(2),
+		evidences:  newEvidencePool(),
+		valSetInfo: fixed_valset_info.NewFixedValidatorSetInfo(validators),
+	}
+
+	headers := []*types.Header{tests_utils.MakeGenesisHeader(validators)}
+	for i := 1; i <= 3; i++ {
+		header := tests_utils.MakeBlockWithoutSeal(headers[i-1]).Header()
+		if i == 2 {
+			require.NoError(t, utils.WriteValSet(header, validators))
+		}
+		headers = append(headers, header)
+	}
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	reader := &governanceChainReader{
+		ChainReader: tests_utils.NewHeadersMockChainReader(headers),
+		config:      &params.ChainConfig{EvidenceBlock: evidenceBlock, Tendermint: &params.TendermintConfig{Epoch: 2}},
+		stateDB:     stateDB,
+	}
+	be.chain = reader
+	return be, reader, tests_utils.MakeBlockWithoutSeal(headers[3]).Header()
+}
+
+// TestAddEvidence checks that the verified evidences are pooled once per offence from the Evidence fork on
+func TestAddEvidence(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	outsider, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	validators := []common.Address{crypto.PubkeyToAddress(key.PublicKey), common.HexToAddress("0x01")}
+
+	// the evidences are refused before the fork
+	be, _, _ := newEvidenceBackend(t, validators, big.NewInt(5))
+	_, err = be.AddEvidence(newEvidence(t, key, 2, 0))
+	assert.Equal(t, ErrEvidenceNotActive, err)
+
+	be, _, _ = newEvidenceBackend(t, validators, big.NewInt(4))
+	added, err := be.AddEvidence(newEvidence(t, key, 2, 0))
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = be.AddEvidence(newEvidence(t, key, 2, 0))
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	// another pair of conflicting votes at the same height is the same offence
+	added, err = be.AddEvidence(newEvidence(t, key, 2, 1))
+	assert.NoError(t, err)
+	assert.False(t, added)
+	added, err = be.AddEvidence(newEvidence(t, key, 3, 0))
+	assert.NoError(t, err)
+	assert.True(t, added)
+	assert.Len(t, be.evidences.pending, 2)
+
+	// the offender must be a validator
+	_, err = be.AddEvidence(newEvidence(t, outsider, 2, 0))
+	assert.Equal(t, ErrEvidenceOffenderNotValidator, err)
+
+	// the votes must conflict
+	_, err = be.AddEvidence(types.NewDuplicateVoteEvidence(
+		signVote(t, key, 2, 0, common.HexToHash("0xa")), signVote(t, key, 2, 0, common.HexToHash("0xa"))))
+	assert.Error(t, err)
+}
+
+// TestVerifyEvidence checks that an evidence applies to the blocks of the following epoch until its offence is slashed
+func TestVerifyEvidence(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	outsider, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	offender := crypto.PubkeyToAddress(key.PublicKey)
+	be, reader, header := newEvidenceBackend(t, []common.Address{offender, common.HexToAddress("0x01")}, common.Big0)
+
+	address, number, err := be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, key, 2, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, offender, address)
+	assert.Equal(t, big.NewInt(2), number)
+
+	_, _, err = be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, key, 4, 0))
+	assert.Equal(t, ErrEvidenceFromFuture, err)
+	_, _, err = be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, key, 1, 0))
+	assert.Equal(t, ErrEvidenceTooOld, err)
+	_, _, err = be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, outsider, 2, 0))
+	assert.Equal(t, ErrEvidenceOffenderNotValidator, err)
+
+	staking.MarkOffenceSlashed(reader.stateDB, offender, big.NewInt(2))
+	_, _, err = be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, key, 2, 1))
+	assert.Equal(t, ErrOffenceAlreadySlashed, err)
+	_, _, err = be.verifyEvidence(reader, reader.stateDB, header, newEvidence(t, key, 3, 0))
+	assert.NoError(t, err)
+}
+
+// TestApplyEvidences checks that the offenders of the evidences of a block are tombstoned and slashed once per offence
+func TestApplyEvidences(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	offender := crypto.PubkeyToAddress(key.PublicKey)
+	validators := []common.Address{offender, common.HexToAddress("0x01")}
+
+	// the evidences of a block before the fork are rejected
+	be, reader, header := newEvidenceBackend(t, validators, big.NewInt(5))
+	require.NoError(t, utils.WriteEvidences(header, []*types.DuplicateVoteEvidence{newEvidence(t, key, 2, 0)}))
+	assert.Equal(t, tendermint.ErrUnexpectedEvidences, be.applyEvidences(reader, reader.stateDB, header))
+	assert.False(t, staking.IsTombstoned(reader.stateDB, offender))
+
+	// the proposer only includes the evidences from the fork on
+	be, reader, header = newEvidenceBackend(t, validators, big.NewInt(4))
+	_, err = be.AddEvidence(newEvidence(t, key, 2, 0))
+	require.NoError(t, err)
+	reader.config.EvidenceBlock = big.NewInt(5)
+	require.NoError(t, be.addEvidencesToHeader(reader, header, reader.CurrentHeader()))
+	extra, err := types.ExtractTendermintExtra(header)
+	require.NoError(t, err)
+	assert.Empty(t, extra.Evidences)
+	reader.config.EvidenceBlock = big.NewInt(4)
+	require.NoError(t, be.addEvidencesToHeader(reader, header, reader.CurrentHeader()))
+	extra, err = types.ExtractTendermintExtra(header)
+	require.NoError(t, err)
+	require.Len(t, extra.Evidences, 1)
+
+	// the block slashes the offence
+	stateDB := reader.stateDB.Copy()
+	require.NoError(t, be.applyEvidences(reader, stateDB, header))
+	assert.True(t, staking.IsTombstoned(stateDB, offender))
+	assert.True(t, staking.IsOffenceSlashed(stateDB, offender, big.NewInt(2)))
+	assert.Equal(t, []common.Address{validators[1]}, filterExcluded(stateDB, validators))
+
+	// the offence can not be slashed again, whichever conflicting votes are included
+	require.NoError(t, utils.WriteEvidences(header, []*types.DuplicateVoteEvidence{newEvidence(t, key, 2, 1)}))
+	assert.Equal(t, ErrOffenceAlreadySlashed, errors.Cause(be.applyEvidences(reader, stateDB, header)))
+
+	// nor twice by a single block
+	require.NoError(t, utils.WriteEvidences(header, []*types.DuplicateVoteEvidence{
+		newEvidence(t, key, 2, 0), newEvidence(t, key, 2, 1),
+	}))
+	assert.Equal(t, ErrOffenceAlreadySlashed, errors.Cause(be.applyEvidences(reader, reader.stateDB.Copy(), header)))
+}
@@ -0,0 +1,119 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// GovernedParams implements tendermint.Backend.GovernedParams, the parameters are recorded in the checkpoint header of the epoch
+func (sb *Backend) GovernedParams(blockNumber *big.Int) []*types.GovernedParam {
+	if sb.chain == nil {
+		return nil
+	}
+	checkpoint, err := sb.epochs.CheckpointByNumber(sb.chain, blockNumber.Uint64())
+	if err != nil {
+		return nil
+	}
+	extra, err := types.ExtractTendermintExtra(checkpoint)
+	if err != nil {
+		return nil
+	}
+	return extra.GovernedParams
+}
+
+// getGovernedParams returns the parameters approved by the governance contract at the state of the header
+func (sb *Backend) getGovernedParams(chainReader consensus.FullChainReader, header *types.Header) ([]*types.GovernedParam, error) {
+	scAddress := chainReader.Config().Tendermint.GovernanceSCAddress
+	if scAddress == nil {
+		return nil, nil
+	}
+	stateDB, err := chainReader.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	return staking.NewStateDbGovernanceCaller(stateDB).GetApprovedParams(*scAddress)
+}
+
+// addGovernedParamsToHeader writes the parameters approved at the state of the parent to a checkpoint header,
+// they apply to the blocks of the next epoch.
+func (sb *Backend) addGovernedParamsToHeader(chainReader consensus.FullChainReader, header *types.Header, parent *types.Header) error {
+	isCheckpoint, err := sb.epochs.IsCheckpoint(chainReader, header, nil)
+	if err != nil || !isCheckpoint {
+		return err
+	}
+	governedParams, err := sb.getGovernedParams(chainReader, parent)
+	if err != nil || len(governedParams) == 0 {
+		return err
+	}
+	return utils.WriteGovernedParams(header, governedParams)
+}
+
+// verifyGovernedParams checks that a checkpoint header records the parameters approved at the state of its parent,
+// and that the other headers record none.
+func (sb *Backend) verifyGovernedParams(chainReader consensus.FullChainReader, header *types.Header) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	isCheckpoint, err := sb.epochs.IsCheckpoint(chainReader, header, nil)
+	if err != nil {
+		return err
+	}
+	var expected []*types.GovernedParam
+	if isCheckpoint {
+		parent := chainReader.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if parent == nil {
+			return consensus.ErrUnknownAncestor
+		}
+		if expected, err = sb.getGovernedParams(chainReader, parent); err != nil {
+			return err
+		}
+	}
+	if len(extra.GovernedParams) != len(expected) {
+		return tendermint.ErrInvalidGovernedParams
+	}
+	for i, governed := range extra.GovernedParams {
+		if governed.Param != expected[i].Param || governed.Value.Cmp(expected[i].Value) != 0 {
+			return tendermint.ErrInvalidGovernedParams
+		}
+	}
+	return nil
+}
+
+// blockReward returns the reward of the blocks of the epoch starting after the checkpoint header,
+// the one approved by the governance contract or the configured one.
+func blockReward(config *params.TendermintConfig, checkpoint *types.Header) *big.Int {
+	if checkpoint != nil {
+		if extra, err := types.ExtractTendermintExtra(checkpoint); err == nil {
+			if reward := staking.GovernedValue(extra.GovernedParams, staking.GovernedBlockReward); reward != nil {
+				return reward
+			}
+		}
+	}
+	return config.BlockReward
+}
+
+// MinGasPrice implements consensus.Pricing.MinGasPrice, the gas price approved by the governance contract applies
+// to the blocks of the epoch following the checkpoint header recording it.
+func (sb *Backend) MinGasPrice(chain consensus.ChainReader, parent *types.Header) *big.Int {
+	checkpoint, err := sb.epochs.LatestCheckpoint(chain, parent, nil)
+	if err != nil {
+		log.Error("Failed to find the epoch checkpoint for the gas price", "number", parent.Number, "err", err)
+		return nil
+	}
+	extra, err := types.ExtractTendermintExtra(checkpoint)
+	if err != nil {
+		return nil
+	}
+	if price := staking.GovernedValue(extra.GovernedParams, staking.GovernedGasPrice); price != nil && price.Sign() > 0 {
+		return price
+	}
+	return nil
+}
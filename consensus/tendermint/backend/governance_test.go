@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// governanceChainReader serves the headers and a single state for every root
+type governanceChainReader struct {
+	consensus.ChainReader
+	config  *params.ChainConfig
+	stateDB *state.StateDB
+}
+
+func (c *governanceChainReader) Config() *params.ChainConfig {
+	return c.config
+}
+
+func (c *governanceChainReader) StateAt(common.Hash) (*state.StateDB, error) {
+	return c.stateDB, nil
+}
+
+// TestGovernedParams checks that the parameters approved at the state of the parent are recorded in the checkpoint headers only
+func TestGovernedParams(t *testing.T) {
+	var (
+		governanceSCAddress = common.HexToAddress("0x1234")
+		cfg                 = *tendermint.DefaultConfig
+		be                  = &Backend{config: &cfg, epochs: utils.NewEpochSchedule(2)}
+		chainConfig         = &params.ChainConfig{Tendermint: &params.TendermintConfig{
+			Epoch:               2,
+			BlockReward:         big.NewInt(10),
+			GovernanceSCAddress: &governanceSCAddress,
+		}}
+	)
+	cfg.Epoch = 2
+
+	// approve the block reward and the commit timeout in the storage of the governance contract
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	for param, value := range map[uint64]int64{staking.GovernedBlockReward: 5, staking.GovernedTimeoutCommit: 2000} {
+		key := common.BigToHash(new(big.Int).SetUint64(param))
+		stateDB.SetState(governanceSCAddress, crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(2)).Bytes()), common.BigToHash(big.NewInt(value)))
+		stateDB.SetState(governanceSCAddress, crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(3)).Bytes()), common.BigToHash(big.NewInt(1)))
+	}
+
+	genesis := tests_utils.MakeGenesisHeader([]common.Address{common.HexToAddress("0x01")})
+	parent := tests_utils.MakeBlockWithoutSeal(genesis).Header()
+	checkpoint := tests_utils.MakeBlockWithoutSeal(parent).Header()
+	require.NoError(t, utils.WriteValSet(checkpoint, []common.Address{common.HexToAddress("0x01")}))
+	reader := &governanceChainReader{
+		ChainReader: tests_utils.NewHeadersMockChainReader([]*types.Header{genesis, parent}),
+		config:      chainConfig,
+		stateDB:     stateDB,
+	}
+	require.NoError(t, be.addGovernedParamsToHeader(reader, checkpoint, parent))
+	extra, err := types.ExtractTendermintExtra(checkpoint)
+	require.NoError(t, err)
+	require.Len(t, extra.GovernedParams, 2)
+	assert.NoError(t, be.verifyGovernedParams(reader, checkpoint))
+	assert.Equal(t, big.NewInt(5), blockReward(chainConfig.Tendermint, checkpoint))
+	assert.Equal(t, big.NewInt(10), blockReward(chainConfig.Tendermint, nil))
+
+	// the values must be the approved ones
+	require.NoError(t, utils.WriteGovernedParams(checkpoint, []*types.GovernedParam{
+		{Param: staking.GovernedBlockReward, Value: big.NewInt(6)},
+		{Param: staking.GovernedTimeoutCommit, Value: big.NewInt(2000)},
+	}))
+	assert.Equal(t, tendermint.ErrInvalidGovernedParams, be.verifyGovernedParams(reader, checkpoint))
+	require.NoError(t, utils.WriteGovernedParams(checkpoint, nil))
+	assert.Equal(t, tendermint.ErrInvalidGovernedParams, be.verifyGovernedParams(reader, checkpoint))
+
+	// the other headers record none
+	require.NoError(t, utils.WriteGovernedParams(checkpoint, extra.GovernedParams))
+	reader.ChainReader = tests_utils.NewHeadersMockChainReader([]*types.Header{genesis, parent, checkpoint})
+	header := tests_utils.MakeBlockWithoutSeal(checkpoint).Header()
+	require.NoError(t, be.addGovernedParamsToHeader(reader, header, checkpoint))
+	assert.NoError(t, be.verifyGovernedParams(reader, header))
+	require.NoError(t, utils.WriteGovernedParams(header, extra.GovernedParams))
+	assert.Equal(t, tendermint.ErrInvalidGovernedParams, be.verifyGovernedParams(reader, header))
+
+	// the parameters apply from the next epoch
+	be.chain = reader
+	assert.Len(t, be.GovernedParams(checkpoint.Number), 0)
+	assert.Equal(t, extra.GovernedParams, be.GovernedParams(header.Number))
+
+	// the approved timeouts apply on the config
+	config := be.config.WithGovernedParams(extra.GovernedParams)
+	assert.Equal(t, 2*time.Second, config.TimeoutCommit)
+	assert.Equal(t, be.config.TimeoutPropose, config.TimeoutPropose)
+	assert.True(t, be.config == be.config.WithGovernedParams(nil))
+}
+
+// TestMinGasPrice checks that the gas price approved at a checkpoint applies to the blocks of the next epoch
+func TestMinGasPrice(t *testing.T) {
+	var (
+		be          = &Backend{config: tendermint.DefaultConfig, epochs: utils.NewEpochSchedule(2)}
+		chainConfig = &params.ChainConfig{GasPrice: big.NewInt(params.GasPriceConfig), Tendermint: &params.TendermintConfig{Epoch: 2}}
+		genesis     = tests_utils.MakeGenesisHeader([]common.Address{common.HexToAddress("0x01")})
+		parent      = tests_utils.MakeBlockWithoutSeal(genesis).Header()
+		checkpoint  = tests_utils.MakeBlockWithoutSeal(parent).Header()
+	)
+	require.NoError(t, utils.WriteValSet(checkpoint, []common.Address{common.HexToAddress("0x01")}))
+	require.NoError(t, utils.WriteGovernedParams(checkpoint, []*types.GovernedParam{
+		{Param: staking.GovernedGasPrice, Value: big.NewInt(5)},
+	}))
+	header := tests_utils.MakeBlockWithoutSeal(checkpoint).Header()
+	reader := &governanceChainReader{
+		ChainReader: tests_utils.NewHeadersMockChainReader([]*types.Header{genesis, parent, checkpoint, header}),
+		config:      chainConfig,
+	}
+
+	assert.Equal(t, chainConfig.GasPrice, consensus.MinGasPrice(be, reader, parent))
+	assert.Equal(t, big.NewInt(5), consensus.MinGasPrice(be, reader, checkpoint))
+	assert.Equal(t, big.NewInt(5), consensus.MinGasPrice(be, reader, header))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	tendermintCore "github.com/lvbin2012/NeuralChain/consensus/tendermint/core"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
@@ -103,6 +104,10 @@ func (m *mockCore) Stop() error {
 	return nil
 }
 
+func (m *mockCore) ConsensusState() *tendermintCore.ConsensusState {
+	return nil
+}
+
 func (m *mockCore) SetBlockForProposal(block *types.Block) {
 	panic("implement me")
 }
@@ -1,71 +1,135 @@
 package backend
 
 import (
+	"bytes"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/state"
 	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/neutdb"
 )
 
 // AccumulateRewards credits the coinbase of the given block with the proposing
-// reward.
-func (sb *Backend) accumulateRewards(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) error {
+// reward. At the end of an epoch, it returns the distribution of the epoch's rewards.
+func (sb *Backend) accumulateRewards(chainReader consensus.FullChainReader, state *state.StateDB, header *types.Header) (*types.EpochReward, error) {
 	// If fixed validators (test) then return
 	if chainReader.Config().Tendermint.FixedValidators != nil {
-		reward := new(big.Int).Set(chainReader.Config().Tendermint.BlockReward)
+		reward := new(big.Int).Set(blockReward(chainReader.Config().Tendermint, sb.checkpointHeader(chainReader, header)))
 		state.AddBalance(header.Coinbase, reward)
-		return nil
+		return nil, nil
 	}
 	var (
 		currentBlock = header.Number.Uint64()
-		epoch        = chainReader.Config().Tendermint.Epoch
 		start        = time.Now()
 	)
 
 	if currentBlock == 0 {
-		return tendermint.ErrFinalizeZeroBlock
+		return nil, tendermint.ErrFinalizeZeroBlock
 	}
 
-	if currentBlock%epoch != 0 {
-		return nil
+	isCheckpoint, err := sb.epochs.IsCheckpoint(chainReader, header, nil)
+	if err != nil || !isCheckpoint {
+		return nil, err
 	}
 
-	validatorsRewards := calculateTotalValidatorsRewards(chainReader, epoch, header)
-	transitionHeader := chainReader.GetHeaderByNumber(currentBlock - epoch)
+	// the blocks of the epoch are rewarded with the block reward approved at its checkpoint, the transition header
+	transitionHeader, err := sb.epochs.Checkpoint(chainReader, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	epoch := currentBlock - transitionHeader.Number.Uint64()
+	validatorsRewards, validatorsTxFees := sb.calculateTotalValidatorsRewards(chainReader, epoch, header,
+		blockReward(chainReader.Config().Tendermint, transitionHeader))
 	validatorAdds, err := utils.GetValSetAddresses(transitionHeader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stateDB, err := chainReader.StateAt(transitionHeader.Root)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stakingCaller := sb.getStakingCaller(chainReader, stateDB, header)
 	validatorsData, err := stakingCaller.GetValidatorsData(*sb.config.StakingSCAddress, validatorAdds)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// the voters of the validators jailed for downtime lose their reward of the epoch
+	jailed := make(map[common.Address]bool)
+	for _, addr := range validatorAdds {
+		if staking.IsJailed(state, addr) {
+			jailed[addr] = true
+		}
 	}
 
-	finalReward := calculateReward(validatorsData, validatorsRewards)
+	finalReward, validatorRewards := calculateReward(validatorsData, validatorsRewards, jailed)
 	for addr, value := range finalReward {
 		state.AddBalance(addr, value)
 	}
+	for _, validatorReward := range validatorRewards {
+		validatorReward.TxFee = new(big.Int).Set(validatorsTxFees[validatorReward.Validator])
+		validatorReward.BlockReward = new(big.Int).Sub(validatorsRewards[validatorReward.Validator], validatorReward.TxFee)
+	}
 	log.Debug("accumulateRewards", "number", currentBlock, "elapsed", common.PrettyDuration(time.Since(start)))
-	return nil
+	return &types.EpochReward{
+		BlockNumber: currentBlock,
+		Validators:  validatorRewards,
+	}, nil
+}
+
+// finalizeEpochReward keeps the reward distribution of the epoch ending at the header, whose state root must be final,
+// until the block is written to the chain. The blocks finalized only to be verified are never written.
+func (sb *Backend) finalizeEpochReward(header *types.Header, reward *types.EpochReward) {
+	if reward == nil {
+		return
+	}
+	sb.finalizedRewards.Add(header.Root, reward)
+}
+
+// WriteBlockData implements consensus.BlockDataWriter, it persists the reward distribution of the epoch ending at the block
+func (sb *Backend) WriteBlockData(db neutdb.KeyValueWriter, block *types.Block) {
+	reward, ok := sb.finalizedRewards.Get(block.Root())
+	if !ok {
+		return
+	}
+	rawdb.WriteEpochReward(db, block.NumberU64(), block.Root(), reward.(*types.EpochReward))
+}
+
+// blockGasPrice returns the gas price shared between the validators for the gas used by the block.
+// Before the fee market, it is the minimum gas price of the block. Since, it is the base fee of the block,
+// the tips are paid to the proposer by the transactions.
+func (sb *Backend) blockGasPrice(chainReader consensus.ChainReader, header *types.Header) *big.Int {
+	config := chainReader.Config()
+	parent := chainReader.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		log.Error("Missing parent header to compute the block gas price", "number", header.Number, "parent", header.ParentHash)
+		return config.GasPrice
+	}
+	minGasPrice := consensus.MinGasPrice(sb, chainReader, parent)
+	if !config.IsFeeMarket(header.Number) {
+		return minGasPrice
+	}
+	return core.CalcBaseFee(config, minGasPrice, parent)
 }
 
 // calculateTotalValidatorsRewards gets reward from chainReader and current header (from finalize)
 // reward includes block rewards and tx fee from block number currentBlock - epoch +1
-func calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch uint64, header *types.Header) map[common.Address]*big.Int {
+// It returns the total rewards and the tx fees they include by validator
+func (sb *Backend) calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch uint64, header *types.Header,
+	blockReward *big.Int) (map[common.Address]*big.Int, map[common.Address]*big.Int) {
 	var currentBlock = header.Number.Uint64()
 	validatorsRewards := make(map[common.Address]*big.Int)
+	validatorsTxFees := make(map[common.Address]*big.Int)
 	for i := currentBlock - epoch + 1; i <= currentBlock; i++ {
 		var currentHeader *types.Header
 		if i != currentBlock {
@@ -73,20 +137,28 @@ func calculateTotalValidatorsRewards(chainReader consensus.ChainReader, epoch ui
 		} else {
 			currentHeader = header
 		}
-		txFee := new(big.Int).Mul(big.NewInt(int64(currentHeader.GasUsed)), chainReader.Config().GasPrice)
-		reward := new(big.Int).Add(chainReader.Config().Tendermint.BlockReward, txFee)
+		txFee := new(big.Int).Mul(new(big.Int).SetUint64(currentHeader.GasUsed), sb.blockGasPrice(chainReader, currentHeader))
+		reward := new(big.Int).Add(blockReward, txFee)
 		if current, ok := validatorsRewards[currentHeader.Coinbase]; ok {
 			validatorsRewards[currentHeader.Coinbase] = new(big.Int).Add(current, reward)
 		} else {
 			validatorsRewards[currentHeader.Coinbase] = reward
 		}
+		if current, ok := validatorsTxFees[currentHeader.Coinbase]; ok {
+			validatorsTxFees[currentHeader.Coinbase] = new(big.Int).Add(current, txFee)
+		} else {
+			validatorsTxFees[currentHeader.Coinbase] = txFee
+		}
 	}
-	return validatorsRewards
+	return validatorsRewards, validatorsTxFees
 }
 
 // calculateReward divides rewards into 50% to owner and 50% among voters
 // rewards for voters is proportional to voters'stake
-func calculateReward(validatorsData map[common.Address]staking.CandidateData, validatorsReward map[common.Address]*big.Int) map[common.Address]*big.Int {
+// voters of jailed validators get nothing, and their share is not paid
+// It returns the reward of each address and how the reward of each validator was shared, sorted by validator's address
+func calculateReward(validatorsData map[common.Address]staking.CandidateData, validatorsReward map[common.Address]*big.Int,
+	jailed map[common.Address]bool) (map[common.Address]*big.Int, []*types.ValidatorReward) {
 	finalReward := make(map[common.Address]*big.Int)
 	addReward := func(addr common.Address, value *big.Int) {
 		if current, ok := finalReward[addr]; ok {
@@ -95,24 +167,44 @@ func calculateReward(validatorsData map[common.Address]staking.CandidateData, va
 			finalReward[addr] = new(big.Int).Set(value)
 		}
 	}
+	var validatorRewards []*types.ValidatorReward
 	for addr, validatorData := range validatorsData {
 		totalReward, ok := validatorsReward[addr]
 		if !ok {
 			continue
 		}
+		record := &types.ValidatorReward{
+			Validator:   addr,
+			BlockReward: new(big.Int).Set(totalReward),
+			TxFee:       new(big.Int),
+			Owner:       validatorData.Owner,
+			Jailed:      jailed[addr],
+		}
+		validatorRewards = append(validatorRewards, record)
 		// remainingReward to ensure the total reward for the voters and owner is equals to the wei validator earns
 		remainingReward := new(big.Int).Set(totalReward)
 		totalVoterReward := new(big.Int).Mul(totalReward, big.NewInt(voterRewardPercentage))
 		totalVoterReward = new(big.Int).Div(totalVoterReward, big.NewInt(100))
+		if jailed[addr] {
+			record.OwnerReward = remainingReward.Sub(remainingReward, totalVoterReward)
+			addReward(validatorData.Owner, record.OwnerReward)
+			continue
+		}
 		for voter, voterStake := range validatorData.VoterStakes {
 			voterReward := new(big.Int).Mul(totalVoterReward, voterStake)
 			voterReward = new(big.Int).Div(voterReward, validatorData.TotalStake)
 			addReward(voter, voterReward)
 			remainingReward.Sub(remainingReward, voterReward)
+			record.Voters = append(record.Voters, &types.VoterReward{Voter: voter, Reward: voterReward})
 		}
-		validatorReward := new(big.Int).Mul(totalReward, big.NewInt(validatorRewardPercentage))
-		validatorReward = new(big.Int).Div(validatorReward, big.NewInt(100))
+		sort.Slice(record.Voters, func(i, j int) bool {
+			return bytes.Compare(record.Voters[i].Voter.Bytes(), record.Voters[j].Voter.Bytes()) < 0
+		})
+		record.OwnerReward = remainingReward
 		addReward(validatorData.Owner, remainingReward)
 	}
-	return finalReward
+	sort.Slice(validatorRewards, func(i, j int) bool {
+		return bytes.Compare(validatorRewards[i].Validator.Bytes(), validatorRewards[j].Validator.Bytes()) < 0
+	})
+	return finalReward, validatorRewards
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lvbin2012/NeuralChain/accounts/abi"
@@ -16,13 +17,15 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/log"
 	"github.com/lvbin2012/NeuralChain/params"
 	"github.com/lvbin2012/NeuralChain/rlp"
 )
 
-//TestBackend_RewardNoTx this is integration test between core.BlockChain and tendermint.Backend
+// TestBackend_RewardNoTx this is integration test between core.BlockChain and tendermint.Backend
 // this test check the reward of validator without any transactions and voters
 func TestBackend_RewardNoTx(t *testing.T) {
 	log.Root().SetHandler(log.LvlFilterHandler(log.LvlTrace, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
@@ -38,7 +41,7 @@ func TestBackend_RewardNoTx(t *testing.T) {
 	})
 }
 
-//TestBackend_RewardNoTx_WithVoter this is integration test between core.BlockChain and tendermint.Backend
+// TestBackend_RewardNoTx_WithVoter this is integration test between core.BlockChain and tendermint.Backend
 // this test check the reward of validators and voters without any transactions
 func TestBackend_RewardNoTx_WithVoter(t *testing.T) {
 	var (
@@ -138,7 +141,13 @@ func testFinalize(t *testing.T, generate func(int, *core.BlockGen), n int, asser
 	genesis := chain.Genesis()
 	require.NotNil(t, genesis)
 	pks, addrs := getValidatorAccounts()
-	blocks, receipts := core.GenerateChain(chain.Config(), genesis, be, db, n, generate)
+	emptyExtra, err := tests_utils.PrepareExtra(&types.Header{})
+	require.NoError(t, err)
+	blocks, receipts := core.GenerateChain(chain.Config(), genesis, be, db, n, func(i int, gen *core.BlockGen) {
+		// the engine finalizes the blocks from their tendermint extra
+		gen.SetExtra(emptyExtra)
+		generate(i, gen)
+	})
 	fmt.Println("blocks", len(blocks), "receipts", len(receipts))
 	parent := genesis.Header().Hash()
 	// sealing
@@ -163,3 +172,64 @@ func testFinalize(t *testing.T, generate func(int, *core.BlockGen), n int, asser
 		assertFn(chain)
 	}
 }
+
+func TestCalculateReward_Jailed(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x1")
+		jailedVal = common.HexToAddress("0x2")
+		owner     = common.HexToAddress("0x3")
+		voter     = common.HexToAddress("0x4")
+	)
+	validatorsData := map[common.Address]staking.CandidateData{
+		validator: {
+			Owner:       owner,
+			VoterStakes: map[common.Address]*big.Int{voter: big.NewInt(10)},
+			TotalStake:  big.NewInt(10),
+		},
+		jailedVal: {
+			Owner:       owner,
+			VoterStakes: map[common.Address]*big.Int{voter: big.NewInt(10)},
+			TotalStake:  big.NewInt(10),
+		},
+	}
+	validatorsReward := map[common.Address]*big.Int{
+		validator: big.NewInt(100),
+		jailedVal: big.NewInt(1000),
+	}
+
+	finalReward, validatorRewards := calculateReward(validatorsData, validatorsReward, map[common.Address]bool{jailedVal: true})
+	// the voter is only paid for the validator which is not jailed
+	require.Equal(t, big.NewInt(50), finalReward[voter])
+	require.Equal(t, big.NewInt(550), finalReward[owner])
+
+	// the distribution is recorded by validator
+	require.Len(t, validatorRewards, 2)
+	require.Equal(t, validator, validatorRewards[0].Validator)
+	require.False(t, validatorRewards[0].Jailed)
+	require.Equal(t, big.NewInt(50), validatorRewards[0].OwnerReward)
+	require.Equal(t, []*types.VoterReward{{Voter: voter, Reward: big.NewInt(50)}}, validatorRewards[0].Voters)
+	require.Equal(t, jailedVal, validatorRewards[1].Validator)
+	require.True(t, validatorRewards[1].Jailed)
+	require.Equal(t, big.NewInt(500), validatorRewards[1].OwnerReward)
+	require.Empty(t, validatorRewards[1].Voters)
+}
+
+func TestWriteBlockData(t *testing.T) {
+	finalizedRewards, err := lru.NewARC(inMemoryRewards)
+	require.NoError(t, err)
+	var (
+		be     = &Backend{finalizedRewards: finalizedRewards}
+		db     = rawdb.NewMemoryDatabase()
+		header = &types.Header{Number: big.NewInt(4), Root: common.Hash{0x1}}
+		reward = &types.EpochReward{BlockNumber: 4}
+	)
+	be.finalizeEpochReward(header, reward)
+	require.Nil(t, rawdb.ReadEpochReward(db, 4, header.Root))
+
+	// a block with another state root was not finalized
+	be.WriteBlockData(db, types.NewBlockWithHeader(&types.Header{Number: big.NewInt(4), Root: common.Hash{0x2}}))
+	require.Nil(t, rawdb.ReadEpochReward(db, 4, common.Hash{0x2}))
+
+	be.WriteBlockData(db, types.NewBlockWithHeader(header))
+	require.Equal(t, reward.BlockNumber, rawdb.ReadEpochReward(db, 4, header.Root).BlockNumber)
+}
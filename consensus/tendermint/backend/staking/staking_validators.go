@@ -13,14 +13,14 @@ import (
 
 // StakingValidator is implementation of ValidatorSetInfo
 type StakingValidator struct {
-	Epoch          uint64
+	Epochs         *utils.EpochSchedule
 	ProposerPolicy tendermint.ProposerPolicy
 }
 
 // NewStakingValidatorInfo returns new StakingValidator
-func NewStakingValidatorInfo(epoch uint64, proposerPolicy tendermint.ProposerPolicy) *StakingValidator {
+func NewStakingValidatorInfo(epochs *utils.EpochSchedule, proposerPolicy tendermint.ProposerPolicy) *StakingValidator {
 	return &StakingValidator{
-		Epoch:          epoch,
+		Epochs:         epochs,
 		ProposerPolicy: proposerPolicy,
 	}
 }
@@ -28,22 +28,21 @@ func NewStakingValidatorInfo(epoch uint64, proposerPolicy tendermint.ProposerPol
 // GetValSet returns the validators available in the block if it already been created
 func (v *StakingValidator) GetValSet(chainReader consensus.ChainReader, number *big.Int) (tendermint.ValidatorSet, error) {
 	var (
-		// get the checkpoint of block-number
 		blockNumber = number.Int64()
-		checkPoint  = utils.GetCheckpointNumber(v.Epoch, number.Uint64())
 		valSet      = validator.NewSet([]common.Address{}, v.ProposerPolicy, blockNumber)
 	)
 
-	header := chainReader.GetHeaderByNumber(checkPoint)
-	if (header == nil || header.Hash() == common.Hash{}) {
+	// get the checkpoint of block-number
+	header, err := v.Epochs.CheckpointByNumber(chainReader, number.Uint64())
+	if err != nil {
 		return valSet, tendermint.ErrUnknownBlock
 	}
 
-	validatorAdds, err := utils.GetValSetAddresses(header)
+	headerValSet, err := utils.GetValSet(header, v.ProposerPolicy, blockNumber)
 	if err != nil {
-		log.Error("can't get the validators's address from extra-data", "number", blockNumber)
+		log.Error("can't get the validators from extra-data", "number", blockNumber)
 		return valSet, err
 	}
 
-	return validator.NewSet(validatorAdds, v.ProposerPolicy, blockNumber), nil
+	return headerValSet, nil
 }
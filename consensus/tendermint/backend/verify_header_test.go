@@ -10,9 +10,11 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/crypto/secp256k1"
+	"github.com/lvbin2012/NeuralChain/params"
 )
 
 func TestBackend_VerifyHeader(t *testing.T) {
@@ -58,6 +60,119 @@ func TestBackend_VerifyHeader(t *testing.T) {
 	assert.NotNil(t, engine.chain)
 	err = engine.VerifyHeader(engine.chain, block.Header(), false)
 	assert.NoError(t, err)
+	// with validators out of an epoch checkpoint
+	header = tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+	assert.NoError(t, utils.WriteValSet(header, validators))
+	tests_utils.AppendSeal(header, engine)
+	assert.Equal(t, tendermint.ErrUnexpectedValSet, engine.VerifyHeader(engine.chain, header, false))
+}
+
+// TestBackend_VerifyCheckpointHeader checks that a checkpoint header must hand off the validators of the next epoch
+func TestBackend_VerifyCheckpointHeader(t *testing.T) {
+	var (
+		nodePKString = "bb047e5940b6d83354d9432db7c449ac8fca2248008aaa7271369880f9f11cc1"
+		nodeAddr, _  = common.NeutAddressStringToAddressCheck("NW9sTi1q6M1bwFCEBt729awvLvFeDdv5DH")
+		validators   = []common.Address{
+			nodeAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	nodePK, err := crypto.HexToECDSA(nodePKString)
+	assert.NoError(t, err)
+
+	cfg := *tendermint.DefaultConfig
+	cfg.Epoch = 1
+	cfg.FixedValidators = validators
+	_, engine := mustStartTestChainAndBackend(nodePK, genesisHeader, &cfg)
+
+	// without validators for the next epoch
+	block := tests_utils.MustMakeBlockWithCommittedSeal(engine, genesisHeader)
+	assert.Equal(t, tendermint.ErrEmptyValSet, engine.VerifyHeader(engine.chain, block.Header(), false))
+
+	// with validators for the next epoch
+	header := tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+	assert.NoError(t, utils.WriteValSet(header, validators))
+	tests_utils.AppendSeal(header, engine)
+	committedSeal, err := engine.Sign(utils.PrepareCommittedSeal(header.Hash()))
+	assert.NoError(t, err)
+	tests_utils.AppendCommittedSeal(header, committedSeal)
+	assert.NoError(t, engine.VerifyHeader(engine.chain, header, false))
+}
+
+// TestBackend_VerifyValidatorSetHash checks that the checkpoint headers record the hash of their validator set from the fork on
+func TestBackend_VerifyValidatorSetHash(t *testing.T) {
+	var (
+		nodePKString = "bb047e5940b6d83354d9432db7c449ac8fca2248008aaa7271369880f9f11cc1"
+		nodeAddr, _  = common.NeutAddressStringToAddressCheck("NW9sTi1q6M1bwFCEBt729awvLvFeDdv5DH")
+		validators   = []common.Address{
+			nodeAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	nodePK, err := crypto.HexToECDSA(nodePKString)
+	assert.NoError(t, err)
+
+	cfg := *tendermint.DefaultConfig
+	cfg.Epoch = 1
+	cfg.FixedValidators = validators
+	chain, engine := mustStartTestChainAndBackend(nodePK, genesisHeader, &cfg)
+	makeCheckpoint := func(hashed bool) *types.Header {
+		header := tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+		assert.NoError(t, utils.WriteValSet(header, validators))
+		if hashed {
+			assert.NoError(t, utils.WriteValSetHash(header))
+		}
+		tests_utils.AppendSeal(header, engine)
+		committedSeal, err := engine.Sign(utils.PrepareCommittedSeal(header.Hash()))
+		assert.NoError(t, err)
+		tests_utils.AppendCommittedSeal(header, committedSeal)
+		return header
+	}
+
+	// before the fork
+	assert.Equal(t, tendermint.ErrInvalidValSetHash, engine.VerifyHeader(engine.chain, makeCheckpoint(true), false))
+
+	// from the fork on
+	config := *params.TendermintTestChainConfig
+	config.ValidatorSetHashBlock = common.Big1
+	chain.ChainConfig = &config
+	assert.Equal(t, tendermint.ErrInvalidValSetHash, engine.VerifyHeader(engine.chain, makeCheckpoint(false), false))
+	assert.NoError(t, engine.VerifyHeader(engine.chain, makeCheckpoint(true), false))
+
+	// the validator set of a verified checkpoint cannot be replaced
+	header := makeCheckpoint(true)
+	assert.NoError(t, utils.WriteValSet(header, []common.Address{{1}}))
+	assert.Equal(t, tendermint.ErrInvalidValSetHash, engine.VerifyHeader(engine.chain, header, false))
+}
+
+// TestBackend_VerifyEvidences checks that the headers include evidences from the Evidence fork on only
+func TestBackend_VerifyEvidences(t *testing.T) {
+	var (
+		nodePKString = "bb047e5940b6d83354d9432db7c449ac8fca2248008aaa7271369880f9f11cc1"
+		nodeAddr, _  = common.NeutAddressStringToAddressCheck("NW9sTi1q6M1bwFCEBt729awvLvFeDdv5DH")
+		validators   = []common.Address{
+			nodeAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	nodePK, err := crypto.HexToECDSA(nodePKString)
+	assert.NoError(t, err)
+
+	cfg := *tendermint.DefaultConfig
+	cfg.FixedValidators = validators
+	chain, engine := mustStartTestChainAndBackend(nodePK, genesisHeader, &cfg)
+	header := tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+	assert.NoError(t, utils.WriteEvidences(header, []*types.DuplicateVoteEvidence{{VoteA: []byte{1}, VoteB: []byte{2}}}))
+	tests_utils.AppendSeal(header, engine)
+	committedSeal, err := engine.Sign(utils.PrepareCommittedSeal(header.Hash()))
+	assert.NoError(t, err)
+	tests_utils.AppendCommittedSeal(header, committedSeal)
+
+	assert.Equal(t, tendermint.ErrUnexpectedEvidences, engine.VerifyHeader(engine.chain, header, false))
+	config := *params.TendermintTestChainConfig
+	config.EvidenceBlock = common.Big1
+	chain.ChainConfig = &config
+	assert.NoError(t, engine.VerifyHeader(engine.chain, header, false))
 }
 
 func mustStartTestChainAndBackend(nodePK *ecdsa.PrivateKey, genesisHeader *types.Header, cfg *tendermint.Config) (*tests_utils.MockChainReader, *Backend) {
@@ -90,3 +205,33 @@ func mustStartTestChainAndBackend(nodePK *ecdsa.PrivateKey, genesisHeader *types
 	}
 	return &chain, b
 }
+
+// TestBackend_VerifyParentCommittedSeals checks that the headers record the parent committed seals from the Liveness fork on only
+func TestBackend_VerifyParentCommittedSeals(t *testing.T) {
+	var (
+		nodePKString = "bb047e5940b6d83354d9432db7c449ac8fca2248008aaa7271369880f9f11cc1"
+		nodeAddr, _  = common.NeutAddressStringToAddressCheck("NW9sTi1q6M1bwFCEBt729awvLvFeDdv5DH")
+		validators   = []common.Address{
+			nodeAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	nodePK, err := crypto.HexToECDSA(nodePKString)
+	assert.NoError(t, err)
+
+	cfg := *tendermint.DefaultConfig
+	cfg.FixedValidators = validators
+	chain, engine := mustStartTestChainAndBackend(nodePK, genesisHeader, &cfg)
+	header := tests_utils.MakeBlockWithoutSeal(genesisHeader).Header()
+	assert.NoError(t, utils.WriteParentCommittedSeals(header, [][]byte{make([]byte, types.TendermintExtraSeal)}))
+	tests_utils.AppendSeal(header, engine)
+	committedSeal, err := engine.Sign(utils.PrepareCommittedSeal(header.Hash()))
+	assert.NoError(t, err)
+	tests_utils.AppendCommittedSeal(header, committedSeal)
+
+	assert.Equal(t, tendermint.ErrUnexpectedParentCommittedSeals, engine.VerifyHeader(engine.chain, header, false))
+	config := *params.TendermintTestChainConfig
+	config.LivenessBlock = common.Big1
+	chain.ChainConfig = &config
+	assert.NoError(t, engine.VerifyHeader(engine.chain, header, false))
+}
@@ -1,11 +1,13 @@
 package tendermint
 
 import (
+	"math"
 	"math/big"
 	"time"
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
 )
 
 type ProposerPolicy uint64
@@ -13,6 +15,8 @@ type ProposerPolicy uint64
 const (
 	RoundRobin ProposerPolicy = iota
 	Sticky
+	// ProportionalToStake selects the proposers in proportion to the validators' voting power
+	ProportionalToStake
 )
 
 //FaultyMode is the config mode to enable fauty node
@@ -46,6 +50,8 @@ type Config struct {
 	TimeoutCommit         time.Duration    //Duration waiting to start round with new height
 	FixedValidators       []common.Address // The fixed validators
 	BlockReward           *big.Int         //BlockReward for accumulating reward
+	WeightedVoting        bool             `toml:",omitempty"` // Weight the validators' votes by their stake
+	Validator             common.Address   `toml:",omitempty"` // The account signing the votes and the blocks, the node key if empty
 
 	FaultyMode uint64 `toml:",omitempty"` // The faulty node indicates the faulty node's behavior
 
@@ -70,6 +76,28 @@ var DefaultConfig = &Config{
 	IndexStateVariables:   staking.DefaultConfig,
 }
 
+// WithGovernedParams returns a copy of the config with the timeouts approved by the governance contract,
+// the config itself if none is approved. The values are in milliseconds, the ones overflowing a duration are ignored.
+func (cfg *Config) WithGovernedParams(params []*types.GovernedParam) *Config {
+	if len(params) == 0 {
+		return cfg
+	}
+	governed := *cfg
+	for param, timeout := range map[uint64]*time.Duration{
+		staking.GovernedTimeoutPropose:   &governed.TimeoutPropose,
+		staking.GovernedTimeoutPrevote:   &governed.TimeoutPrevote,
+		staking.GovernedTimeoutPrecommit: &governed.TimeoutPrecommit,
+		staking.GovernedTimeoutCommit:    &governed.TimeoutCommit,
+	} {
+		value := staking.GovernedValue(params, param)
+		if value == nil || !value.IsInt64() || value.Int64() > math.MaxInt64/int64(time.Millisecond) {
+			continue
+		}
+		*timeout = time.Duration(value.Int64()) * time.Millisecond
+	}
+	return &governed
+}
+
 //ProposeTimeout return the timeout for a specific round
 //The formula is timeout= TimeoutPropose + round*TimeoutProposeDelta
 func (cfg Config) ProposeTimeout(round int64) time.Duration {
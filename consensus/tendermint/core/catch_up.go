@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	"github.com/lvbin2012/NeuralChain/rlp"
 )
@@ -42,6 +43,13 @@ func (c *core) enterCatchup(tiBlock *big.Int, tiRound int64, tiStep RoundStepTyp
 		Step:        tiStep,
 		Retry:       tiRetry + 1,
 	})
+	c.catchUp = &CatchUpState{
+		BlockNumber: (*hexutil.Big)(new(big.Int).Set(tiBlock)),
+		Round:       tiRound,
+		Step:        tiStep.String(),
+		Retry:       tiRetry,
+		Time:        time.Now(),
+	}
 	//send catch up
 	c.sendCatchUpRequest(logger, tiBlock, tiRound, tiStep)
 }
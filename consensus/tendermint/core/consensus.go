@@ -59,7 +59,7 @@ func (c *core) enterNewRound(blockNumber *big.Int, round int64) {
 		state.SetProposalReceived(nil)
 	}
 	//Update to RoundStepNewRound
-	state.UpdateRoundStep(round, RoundStepNewRound)
+	c.updateRoundStep(round, RoundStepNewRound)
 	state.setPrecommitWaited(false)
 
 	c.enterPropose(blockNumber, round)
@@ -134,7 +134,7 @@ func (c *core) enterPropose(blockNumber *big.Int, round int64) {
 	c.proposeStart = time.Now()
 	defer func() {
 		// Done enterPropose:
-		state.UpdateRoundStep(round, RoundStepPropose)
+		c.updateRoundStep(round, RoundStepPropose)
 
 		// If we have the whole proposal + POL, then goto PrevoteTimeout now.
 		// else, we'll enterPrevote when the rest of the proposal is received (in AddProposalBlockPart),
@@ -245,7 +245,7 @@ func (c *core) enterPrevote(blockNumber *big.Int, round int64) {
 	})
 	//eventually we'll enterPrevote
 	defer func() {
-		state.UpdateRoundStep(round, RoundStepPrevote)
+		c.updateRoundStep(round, RoundStepPrevote)
 	}()
 	c.defaultDoPrevote(round)
 }
@@ -276,7 +276,7 @@ func (c *core) enterPrevoteWait(blockNumber *big.Int, round int64) {
 
 	defer func() {
 		// Done enterPrevoteWait:
-		state.UpdateRoundStep(round, RoundStepPrevoteWait)
+		c.updateRoundStep(round, RoundStepPrevoteWait)
 	}()
 
 	//We have to copy blockNumber out since it's pointer, and the use of ScheduleTimeout
@@ -316,7 +316,7 @@ func (c *core) enterPrecommitWait(blockNumber *big.Int, round int64) {
 
 	//after this we setPrecommitWaited to true to make sure that the wait happens only once each round
 	defer func() {
-		state.UpdateRoundStep(round, RoundStepPrecommitWait)
+		c.updateRoundStep(round, RoundStepPrecommitWait)
 		state.setPrecommitWaited(true)
 	}()
 	//We have to copy blockNumber out since it's pointer, and the use of ScheduleTimeout
@@ -362,7 +362,7 @@ func (c *core) enterPrecommit(blockNumber *big.Int, round int64) {
 
 	defer func() {
 		// Done enterPrecommit:
-		state.UpdateRoundStep(round, RoundStepPrecommit)
+		c.updateRoundStep(round, RoundStepPrecommit)
 	}()
 
 	var blockHash = common.Hash{}
@@ -439,7 +439,7 @@ func (c *core) enterCommit(blockNumber *big.Int, commitRound int64) {
 	defer func() {
 		// Done enterCommit:
 		// keep state.Round the same, commitRound points to the right Precommits set.
-		state.UpdateRoundStep(state.Round(), RoundStepCommit)
+		c.updateRoundStep(state.Round(), RoundStepCommit)
 		state.commitRound = commitRound
 		state.commitTime = time.Now()
 
@@ -531,12 +531,12 @@ func (c *core) finalizeCommit(blockNumber *big.Int) {
 //FinalizeBlock will fill extradata with signature and return the ready to store block
 func (c *core) FinalizeBlock(proposal *Proposal) (*types.Block, error) {
 	var (
-		state           = c.currentState
-		round           = state.commitRound
-		totalPrecommits = 0
-		commitSeals     = [][]byte{}
-		header          = proposal.Block.Header()
-		minMajority     = c.valSet.MinMajority()
+		state             = c.currentState
+		round             = state.commitRound
+		precommitsPower   = new(big.Int)
+		commitSeals       = [][]byte{}
+		header            = proposal.Block.Header()
+		quorumVotingPower = c.valSet.QuorumVotingPower()
 	)
 	precommits, ok := state.GetPrecommitsByRound(round)
 	if !ok {
@@ -547,24 +547,27 @@ func (c *core) FinalizeBlock(proposal *Proposal) (*types.Block, error) {
 	if !ok || votes == nil {
 		c.getLogger().Panicw("no votes for the committing block", "block_hash", header.Hash())
 	}
-	if votes.totalReceived < minMajority {
-		return nil, fmt.Errorf("not enough precommits received expect at least %d received %d", minMajority, totalPrecommits)
+	if votes.votingPower.Cmp(quorumVotingPower) < 0 {
+		return nil, fmt.Errorf("not enough precommits received expect voting power at least %d received %d", quorumVotingPower, votes.votingPower)
 	}
 
-	for _, vote := range votes.votes {
+	// from the Liveness fork on, all the precommits received for the block are kept, they record the liveness
+	// of the validators
+	keepAll := c.backend.ChainConfig().IsLiveness(header.Number)
+	for index, vote := range votes.votes {
 		if vote == nil {
 			continue
 		}
 		commitSeals = append(commitSeals, vote.Seal)
-		totalPrecommits++
-		//TODO: is it fair to always take the first 2F+1 seals?
-		if totalPrecommits >= minMajority {
+		precommitsPower.Add(precommitsPower, precommits.valSet.GetByIndex(int64(index)).VotingPower())
+		//TODO: is it fair to always take the first seals reaching the quorum?
+		if !keepAll && precommitsPower.Cmp(quorumVotingPower) >= 0 {
 			break
 		}
 	}
 
-	if totalPrecommits < minMajority {
-		return nil, fmt.Errorf("not enough precommits received expect at least %d received %d", minMajority, totalPrecommits)
+	if precommitsPower.Cmp(quorumVotingPower) < 0 {
+		return nil, fmt.Errorf("not enough precommits received expect voting power at least %d received %d", quorumVotingPower, precommitsPower)
 	}
 	//writeCommitSeals
 	if err := utils.WriteCommittedSeals(header, commitSeals); err != nil {
@@ -595,6 +598,8 @@ func (c *core) startNewRound() {
 		state.clearPreviousRoundData()
 		c.sentMsgStorage.truncateMsgStored(c.getLogger())
 		c.valSet = c.backend.Validators(state.BlockNumber())
+		c.updateConfig(state.BlockNumber())
+		c.resetWAL()
 	}
 
 	//TODO: the timeout must account for the stopped time that core wasn't
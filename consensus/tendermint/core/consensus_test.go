@@ -15,6 +15,8 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/event"
+	"github.com/lvbin2012/NeuralChain/params"
 	"github.com/lvbin2012/NeuralChain/rlp"
 )
 
@@ -39,7 +41,17 @@ func TestFinalizeBlock(t *testing.T) {
 		genesisHeader = tests_utils.MakeGenesisHeader(validators)
 	)
 	//create New test backend and newMockChain
-	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	chain := &tests_utils.MockChainReader{
+		GenesisHeader: genesisHeader,
+		MockBlockChain: &tests_utils.MockBlockChain{
+			Statedb:       tests_utils.MustCreateStateDB(t),
+			GasLimit:      1000000000,
+			ChainHeadFeed: new(event.Feed),
+		},
+		Address: nodeAddr,
+		Trigger: new(bool),
+	}
+	be := tests_utils.NewMockBackend(nodePrivateKey, chain, validators)
 
 	core := newTestCore(be, tendermint.DefaultConfig)
 	require.NoError(t, core.Start())
@@ -109,89 +121,98 @@ func TestFinalizeBlock(t *testing.T) {
 		},
 	}
 
-	for _, tc := range testCases {
-		validateVote := func(t *testing.T) {
-			newMsgSet := newMessageSet(core.valSet, msgPrecommit, &view)
-
-			//Create block 1
-			genesisHeader.Number = big.NewInt(1)
-			bl1 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
-			blHash1 := bl1.Hash()
-			committedSeal1, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash1))
-			require.NoError(t, err)
-
-			//Create block 2
-			genesisHeader.Number = big.NewInt(2)
-			bl2 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
-			blHash2 := bl2.Hash()
-			committedSeal2, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash2))
-			require.NoError(t, err)
-			require.NotEqual(t, bl1.Hash().Hex(), bl2.Hash().Hex(), "Block hash of 2 blocks must be different")
-
-			var block2ExpectCommittedSeals [][]byte //It stores what commit seals were appended to block 2
-			//Add vote from node 1,2,3,4
-			for i := 0; i < len(validators); i++ {
-				msg := message{
-					Code:    msgPrecommit,
-					Address: validators[i],
-				}
+	// from the Liveness fork on, all the precommits are kept rather than the first ones reaching the quorum
+	livenessConfig := *params.TendermintTestChainConfig
+	livenessConfig.LivenessBlock = common.Big0
+	for _, liveness := range []bool{false, true} {
+		chain.ChainConfig = nil
+		if liveness {
+			chain.ChainConfig = &livenessConfig
+		}
+		for _, tc := range testCases {
+			validateVote := func(t *testing.T) {
+				newMsgSet := newMessageSet(core.valSet, msgPrecommit, &view)
+
+				//Create block 1
+				genesisHeader.Number = big.NewInt(1)
+				bl1 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
+				blHash1 := bl1.Hash()
+				committedSeal1, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash1))
+				require.NoError(t, err)
+
+				//Create block 2
+				genesisHeader.Number = big.NewInt(2)
+				bl2 := tests_utils.MakeBlockWithoutSeal(genesisHeader)
+				blHash2 := bl2.Hash()
+				committedSeal2, err := core.backend.Sign(utils.PrepareCommittedSeal(blHash2))
+				require.NoError(t, err)
+				require.NotEqual(t, bl1.Hash().Hex(), bl2.Hash().Hex(), "Block hash of 2 blocks must be different")
+
+				var block2ExpectCommittedSeals [][]byte //It stores what commit seals were appended to block 2
+				//Add vote from node 1,2,3,4
+				for i := 0; i < len(validators); i++ {
+					msg := message{
+						Code:    msgPrecommit,
+						Address: validators[i],
+					}
 
-				assert.Equal(t, validators[i].String(), core.valSet.GetByIndex(int64(i)).String(), "The order voting must be the same")
-				switch tc.validatorVotes[i] {
-				case Block1:
-					ok, err := newMsgSet.AddVote(msg,
-						&Vote{
-							BlockHash:   &blHash1,
+					assert.Equal(t, validators[i].String(), core.valSet.GetByIndex(int64(i)).String(), "The order voting must be the same")
+					switch tc.validatorVotes[i] {
+					case Block1:
+						ok, err := newMsgSet.AddVote(msg,
+							&Vote{
+								BlockHash:   &blHash1,
+								BlockNumber: core.CurrentState().BlockNumber(),
+								Round:       voteRound,
+								Seal:        committedSeal1,
+							})
+						require.NoError(t, err)
+						assert.True(t, ok)
+					case Block2:
+						vote := &Vote{
+							BlockHash:   &blHash2,
 							BlockNumber: core.CurrentState().BlockNumber(),
 							Round:       voteRound,
-							Seal:        committedSeal1,
-						})
-					require.NoError(t, err)
-					assert.True(t, ok)
-				case Block2:
-					vote := &Vote{
-						BlockHash:   &blHash2,
-						BlockNumber: core.CurrentState().BlockNumber(),
-						Round:       voteRound,
-						Seal:        committedSeal2,
-					}
-					ok, err := newMsgSet.AddVote(msg, vote)
-					require.NoError(t, err)
-					assert.True(t, ok)
-
-					//Add committed seals will be added to block 2 to compare after finalizing
-					if len(block2ExpectCommittedSeals) < core.valSet.MinMajority() {
-						block2ExpectCommittedSeals = append(block2ExpectCommittedSeals, vote.Seal)
+							Seal:        committedSeal2,
+						}
+						ok, err := newMsgSet.AddVote(msg, vote)
+						require.NoError(t, err)
+						assert.True(t, ok)
+
+						//Add committed seals will be added to block 2 to compare after finalizing
+						if liveness || len(block2ExpectCommittedSeals) < core.valSet.MinMajority() {
+							block2ExpectCommittedSeals = append(block2ExpectCommittedSeals, vote.Seal)
+						}
+					default:
+						fmt.Println("Not support this case")
 					}
-				default:
-					fmt.Println("Not support this case")
 				}
-			}
 
-			//Check total received
-			assert.Equal(t, 4, newMsgSet.totalReceived)
-			core.currentState.PrecommitsReceived[voteRound] = newMsgSet
-			assert.Equal(t, tc.totalReceived, core.currentState.PrecommitsReceived[voteRound].voteByBlock[blHash2].totalReceived, "Total Precommits Received on block 2 must be same when getting vote by block hash")
-
-			//Check error after finalizing block
-			finalizedBlock, err := core.FinalizeBlock(&Proposal{
-				Block:    bl2,
-				Round:    0,
-				POLRound: 0,
-			})
-			tc.assertFn(finalizedBlock, err)
-
-			if err == nil {
-				//Check committed seals in header extra block after finalizing
-				expectExtra, err := rlp.EncodeToBytes(&types.TendermintExtra{
-					CommittedSeal: block2ExpectCommittedSeals,
+				//Check total received
+				assert.Equal(t, 4, newMsgSet.totalReceived)
+				core.currentState.PrecommitsReceived[voteRound] = newMsgSet
+				assert.Equal(t, tc.totalReceived, core.currentState.PrecommitsReceived[voteRound].voteByBlock[blHash2].totalReceived, "Total Precommits Received on block 2 must be same when getting vote by block hash")
+
+				//Check error after finalizing block
+				finalizedBlock, err := core.FinalizeBlock(&Proposal{
+					Block:    bl2,
+					Round:    0,
+					POLRound: 0,
 				})
-				require.Nil(t, err)
-				expectCommittedSeals := append(bytes.Repeat([]byte{0x00}, types.TendermintExtraVanity), expectExtra...)
-				assert.Equal(t, expectCommittedSeals, finalizedBlock.Header().Extra, "Make sure the committed seals is enough after finalizing")
+				tc.assertFn(finalizedBlock, err)
+
+				if err == nil {
+					//Check committed seals in header extra block after finalizing
+					expectExtra, err := rlp.EncodeToBytes(&types.TendermintExtra{
+						CommittedSeal: block2ExpectCommittedSeals,
+					})
+					require.Nil(t, err)
+					expectCommittedSeals := append(bytes.Repeat([]byte{0x00}, types.TendermintExtraVanity), expectExtra...)
+					assert.Equal(t, expectCommittedSeals, finalizedBlock.Header().Extra, "Make sure the committed seals is enough after finalizing")
+				}
 			}
-		}
 
-		t.Run(tc.name, validateVote)
+			t.Run(fmt.Sprintf("%s (liveness: %v)", tc.name, liveness), validateVote)
+		}
 	}
 }
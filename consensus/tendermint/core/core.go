@@ -10,6 +10,7 @@ import (
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/event"
@@ -26,6 +27,14 @@ func WithoutRebroadcast() Option {
 	}
 }
 
+//WithWAL return an option to set the write-ahead log which core persists its consensus state to
+func WithWAL(wal WAL) Option {
+	return func(c *core) error {
+		c.wal = wal
+		return nil
+	}
+}
+
 // New creates an Tendermint consensus core
 func New(backend tendermint.Backend, config *tendermint.Config, opts ...Option) Engine {
 	c := &core{
@@ -33,6 +42,7 @@ func New(backend tendermint.Backend, config *tendermint.Config, opts ...Option)
 		backend:         backend,
 		timeout:         NewTimeoutTicker(),
 		config:          config,
+		baseConfig:      config,
 		mu:              &sync.RWMutex{},
 		blockFinalize:   new(event.TypeMux),
 		futureMessages:  queue.NewPriorityQueue(0, true),
@@ -77,6 +87,8 @@ type core struct {
 	timeout TimeoutTicker
 	//config store the config of the chain
 	config *tendermint.Config
+	//baseConfig is the config of the node, config applies the timeouts approved by the governance contract on it for the current height
+	baseConfig *tendermint.Config
 	//mutex mark critical section of core which should not be accessed parallel
 	mu *sync.RWMutex
 
@@ -96,6 +108,13 @@ type core struct {
 	futureProposals map[int64]message
 
 	rebroadcast bool
+
+	// catchUp is the last catch-up request sent, which is only kept for introspection
+	catchUp *CatchUpState
+
+	// wal records step transitions and signed messages so that core can resume after a crash
+	// without signing a message which conflicts with its own earlier one
+	wal WAL
 }
 
 // Start implements core.Engine.Start
@@ -107,6 +126,11 @@ func (c *core) Start() error {
 	if c.currentState == nil {
 		c.currentState = c.getInitializedState()
 		c.valSet = c.backend.Validators(c.CurrentState().BlockNumber())
+		c.updateConfig(c.CurrentState().BlockNumber())
+		if err := c.replayWAL(); err != nil {
+			c.getLogger().Errorw("failed to replay consensus wal", "err", err)
+			return err
+		}
 	}
 	c.subscribeEvents()
 
@@ -134,12 +158,17 @@ func (c *core) Stop() error {
 
 //FinalizeMsg set address, signature and encode msg to bytes
 func (c *core) FinalizeMsg(msg *message) ([]byte, error) {
+	return c.finalizeMsg(msg, c.backend.Sign)
+}
+
+// finalizeMsg sets address and the signature of the sign function, then encodes msg to bytes
+func (c *core) finalizeMsg(msg *message, sign func([]byte) ([]byte, error)) ([]byte, error) {
 	msg.Address = c.backend.Address()
 	msgPayLoadWithoutSignature, err := msg.PayLoadWithoutSignature()
 	if err != nil {
 		return nil, err
 	}
-	signature, err := c.backend.Sign(msgPayLoadWithoutSignature)
+	signature, err := sign(msgPayLoadWithoutSignature)
 	if err != nil {
 		return nil, err
 	}
@@ -158,15 +187,30 @@ func (c *core) SendPropose(propose *Proposal) {
 		logger.Errorw("Failed to encode Proposal to bytes", "error", err)
 		return
 	}
-	payload, err := c.FinalizeMsg(&message{
+	// the proposal is signed as the one of the round, the validator key may refuse it if it already signed
+	// a conflicting proposal or a vote of the round
+	payload, err := c.finalizeMsg(&message{
 		Code: msgPropose,
 		Msg:  msgData,
+	}, func(data []byte) ([]byte, error) {
+		return c.backend.SignVote(&privval.VoteRequest{
+			Height:    propose.Block.NumberU64(),
+			Round:     uint64(propose.Round),
+			Step:      privval.StepPropose,
+			BlockHash: propose.Block.Hash(),
+			Data:      data,
+		})
 	})
 	if err != nil {
 		logger.Errorw("Failed to Finalize Proposal", "error", err)
 		return
 	}
 
+	// write ahead before send propose msg
+	if err := c.writeSignedMsgToWAL(RoundStepPropose, propose.Round, payload); err != nil {
+		logger.Errorw("Failed to write Proposal to wal", "error", err)
+		return
+	}
 	// store before send propose msg
 	c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPropose, propose.Round, payload)
 
@@ -196,14 +240,32 @@ func (c *core) SendVote(voteType uint64, block *types.Block, round int64) {
 		seal      []byte
 	)
 	if block != nil {
+		blockHash = block.Hash()
+	}
+	// the seal and the vote message are signed as the vote for the block hash at the round,
+	// the validator key may refuse them if it already signed a conflicting vote
+	voteStep := privval.StepPrevote
+	if voteType == msgPrecommit {
+		voteStep = privval.StepPrecommit
+	}
+	signVote := func(data []byte) ([]byte, error) {
+		return c.backend.SignVote(&privval.VoteRequest{
+			Height:    c.CurrentState().BlockNumber().Uint64(),
+			Round:     uint64(round),
+			Step:      voteStep,
+			BlockHash: blockHash,
+			Data:      data,
+		})
+	}
+	// only the precommits carry a committed seal
+	if block != nil && voteType == msgPrecommit {
 		var err error
 		commitHash := utils.PrepareCommittedSeal(block.Header().Hash())
-		seal, err = c.backend.Sign(commitHash)
+		seal, err = signVote(commitHash)
 		if err != nil {
 			logger.Errorw("failed to sign seal", err, "err")
 			return
 		}
-		blockHash = block.Hash()
 	}
 	vote := &Vote{
 		BlockHash:   &blockHash,
@@ -216,23 +278,26 @@ func (c *core) SendVote(voteType uint64, block *types.Block, round int64) {
 		logger.Errorw("Failed to encode Vote to bytes", "error", err)
 		return
 	}
-	payload, err := c.FinalizeMsg(&message{
+	payload, err := c.finalizeMsg(&message{
 		Code: voteType,
 		Msg:  msgData,
-	})
+	}, signVote)
 	if err != nil {
 		logger.Errorw("Failed to Finalize Vote", "error", err)
 		return
 	}
 
-	// store before send propose msg
-	switch voteType {
-	case msgPrevote:
-		c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPrevote, round, payload)
-	case msgPrecommit:
-		c.sentMsgStorage.storeSentMsg(c.getLogger(), RoundStepPrecommit, round, payload)
-	default:
+	step := RoundStepPrevote
+	if voteType == msgPrecommit {
+		step = RoundStepPrecommit
+	}
+	// write ahead before send vote msg
+	if err := c.writeSignedMsgToWAL(step, round, payload); err != nil {
+		logger.Errorw("Failed to write Vote to wal", "error", err)
+		return
 	}
+	// store before send vote msg
+	c.sentMsgStorage.storeSentMsg(c.getLogger(), step, round, payload)
 
 	if err := c.backend.Broadcast(c.valSet, c.currentState.CopyBlockNumber(), round, voteType, payload); err != nil {
 		logger.Errorw("Failed to Broadcast vote", "error", err)
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+var (
+	// ErrInvalidEvidence is returned when a duplicate vote evidence does not prove any misbehaviour
+	ErrInvalidEvidence = errors.New("invalid duplicate vote evidence")
+)
+
+// VerifyDuplicateVoteEvidence checks that both votes of the evidence are correctly signed by the same validator,
+// have the same type, block number and round but vote for different blocks.
+// It returns the address of the offender and the block number of the conflicting votes.
+func VerifyDuplicateVoteEvidence(evidence *types.DuplicateVoteEvidence) (common.Address, *big.Int, error) {
+	if evidence == nil {
+		return common.Address{}, nil, ErrInvalidEvidence
+	}
+	msgA, voteA, err := decodeSignedVote(evidence.VoteA)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	msgB, voteB, err := decodeSignedVote(evidence.VoteB)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if msgA.Code != msgB.Code {
+		return common.Address{}, nil, errors.Wrap(ErrInvalidEvidence, "votes are of different types")
+	}
+	if msgA.Address != msgB.Address {
+		return common.Address{}, nil, errors.Wrap(ErrInvalidEvidence, "votes are from different validators")
+	}
+	if voteA.BlockNumber.Cmp(voteB.BlockNumber) != 0 || voteA.Round != voteB.Round {
+		return common.Address{}, nil, errors.Wrap(ErrInvalidEvidence, "votes are of different views")
+	}
+	if *voteA.BlockHash == *voteB.BlockHash {
+		return common.Address{}, nil, errors.Wrap(ErrInvalidEvidence, "votes are for the same block")
+	}
+	return msgA.Address, new(big.Int).Set(voteA.BlockNumber), nil
+}
+
+// decodeSignedVote decodes a rlp encoded prevote/ precommit message and verifies its signature
+func decodeSignedVote(payload []byte) (*message, *Vote, error) {
+	var msg message
+	if err := rlp.DecodeBytes(payload, &msg); err != nil {
+		return nil, nil, err
+	}
+	if msg.Code != msgPrevote && msg.Code != msgPrecommit {
+		return nil, nil, errors.Wrapf(ErrInvalidEvidence, "unexpected msg code %d", msg.Code)
+	}
+	signer, err := msg.GetAddressFromSignature()
+	if err != nil {
+		return nil, nil, err
+	}
+	if signer != msg.Address {
+		return nil, nil, ErrSignerMessageMissMatch
+	}
+	var vote Vote
+	if err := rlp.DecodeBytes(msg.Msg, &vote); err != nil {
+		return nil, nil, err
+	}
+	if vote.BlockHash == nil || vote.BlockNumber == nil {
+		return nil, nil, errors.Wrap(ErrInvalidEvidence, "vote without block hash or block number")
+	}
+	return &msg, &vote, nil
+}
+
+// handleConflictingVotes builds the evidence from the vote already stored in msgSet and the conflicting msg
+// from the same validator, then hands it over to backend and gossips it to other validators
+func (c *core) handleConflictingVotes(msgSet *messageSet, msg message) {
+	if msgSet == nil {
+		return
+	}
+	existing, ok := msgSet.MessageByAddress(msg.Address)
+	if !ok {
+		return
+	}
+	logger := c.getLogger().With("offender", msg.Address)
+	voteA, err := rlp.EncodeToBytes(existing)
+	if err != nil {
+		logger.Errorw("Failed to encode existing vote", "error", err)
+		return
+	}
+	voteB, err := rlp.EncodeToBytes(&msg)
+	if err != nil {
+		logger.Errorw("Failed to encode conflicting vote", "error", err)
+		return
+	}
+	evidence := types.NewDuplicateVoteEvidence(voteA, voteB)
+	logger.Warnw("detected conflicting votes", "evidence", evidence.Hash().Hex())
+	if err := c.addAndGossipEvidence(evidence); err != nil {
+		logger.Errorw("Failed to add evidence", "error", err)
+	}
+}
+
+// handleEvidence handles an evidence gossiped by other validators
+func (c *core) handleEvidence(msg message) error {
+	var evidence types.DuplicateVoteEvidence
+	if err := rlp.DecodeBytes(msg.Msg, &evidence); err != nil {
+		return err
+	}
+	return c.addAndGossipEvidence(&evidence)
+}
+
+// addAndGossipEvidence adds the evidence to backend and gossips it if it was not known before
+func (c *core) addAndGossipEvidence(evidence *types.DuplicateVoteEvidence) error {
+	added, err := c.backend.AddEvidence(evidence)
+	if err != nil || !added {
+		return err
+	}
+	msgData, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		return err
+	}
+	payload, err := c.FinalizeMsg(&message{
+		Code: msgEvidence,
+		Msg:  msgData,
+	})
+	if err != nil {
+		return err
+	}
+	return c.backend.Gossip(c.valSet, c.CurrentState().CopyBlockNumber(), c.CurrentState().Round(), msgEvidence, payload)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+func createSignedVote(t *testing.T, privateKey *ecdsa.PrivateKey, code uint64, blockNumber *big.Int, round int64, blockHash common.Hash) message {
+	vote := Vote{
+		Round:       round,
+		BlockNumber: blockNumber,
+		BlockHash:   &blockHash,
+		Seal:        []byte{},
+	}
+	bs, err := rlp.EncodeToBytes(&vote)
+	require.NoError(t, err)
+	msg := message{
+		Address: crypto.PubkeyToAddress(privateKey.PublicKey),
+		Msg:     bs,
+		Code:    code,
+	}
+	sign(t, &msg, privateKey)
+	return msg
+}
+
+func encodeMsg(t *testing.T, msg message) []byte {
+	bs, err := rlp.EncodeToBytes(&msg)
+	require.NoError(t, err)
+	return bs
+}
+
+func TestVerifyDuplicateVoteEvidence(t *testing.T) {
+	var (
+		offenderKey = tests_utils.MakeNodeKey()
+		otherKey    = tests_utils.MakeNodeKey()
+		offender    = crypto.PubkeyToAddress(offenderKey.PublicKey)
+		number      = big.NewInt(10)
+		hashA       = common.HexToHash("0xa")
+		hashB       = common.HexToHash("0xb")
+		voteA       = encodeMsg(t, createSignedVote(t, offenderKey, msgPrevote, number, 1, hashA))
+	)
+	tamperedMsg := createSignedVote(t, offenderKey, msgPrevote, number, 1, hashB)
+	tamperedMsg.Address = crypto.PubkeyToAddress(otherKey.PublicKey)
+
+	for _, testCase := range []struct {
+		name      string
+		voteB     []byte
+		expectErr bool
+	}{
+		{"conflicting prevotes", encodeMsg(t, createSignedVote(t, offenderKey, msgPrevote, number, 1, hashB)), false},
+		{"same block", encodeMsg(t, createSignedVote(t, offenderKey, msgPrevote, number, 1, hashA)), true},
+		{"different round", encodeMsg(t, createSignedVote(t, offenderKey, msgPrevote, number, 2, hashB)), true},
+		{"different block number", encodeMsg(t, createSignedVote(t, offenderKey, msgPrevote, big.NewInt(11), 1, hashB)), true},
+		{"different type", encodeMsg(t, createSignedVote(t, offenderKey, msgPrecommit, number, 1, hashB)), true},
+		{"different signer", encodeMsg(t, createSignedVote(t, otherKey, msgPrevote, number, 1, hashB)), true},
+		{"signer mismatch", encodeMsg(t, tamperedMsg), true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			addr, blockNumber, err := VerifyDuplicateVoteEvidence(types.NewDuplicateVoteEvidence(voteA, testCase.voteB))
+			if testCase.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, offender, addr)
+			assert.Equal(t, number, blockNumber)
+		})
+	}
+}
+
+func TestCore_HandleConflictingVotes(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		offenderKey    = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		offender       = crypto.PubkeyToAddress(offenderKey.PublicKey)
+		validators     = []common.Address{
+			nodeAddr,
+			offender,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	require.NoError(t, core.Start())
+	defer core.Stop()
+	sentMsgSub := be.(*tests_utils.MockBackend).SendEventMux.Subscribe(tests_utils.SentMsgEvent{})
+	defer sentMsgSub.Unsubscribe()
+
+	// MockBackend posts sent messages synchronously, so they must be consumed concurrently
+	evidenceCh := make(chan types.DuplicateVoteEvidence, 1)
+	go func() {
+		for ev := range sentMsgSub.Chan() {
+			var msg message
+			if err := rlp.DecodeBytes(ev.Data.(tests_utils.SentMsgEvent).Payload, &msg); err != nil || msg.Code != msgEvidence {
+				continue
+			}
+			var evidence types.DuplicateVoteEvidence
+			if err := rlp.DecodeBytes(msg.Msg, &evidence); err == nil {
+				evidenceCh <- evidence
+			}
+		}
+	}()
+
+	prevoteA := createSignedVote(t, offenderKey, msgPrevote, big.NewInt(1), 0, common.HexToHash("0xa"))
+	prevoteB := createSignedVote(t, offenderKey, msgPrevote, big.NewInt(1), 0, common.HexToHash("0xb"))
+	require.NoError(t, core.handleMsg(prevoteA))
+	require.Equal(t, ErrConflictingVotes, core.handleMsg(prevoteB))
+
+	expected := types.NewDuplicateVoteEvidence(encodeMsg(t, prevoteA), encodeMsg(t, prevoteB))
+	select {
+	case evidence := <-evidenceCh:
+		assert.Equal(t, expected.Hash(), evidence.Hash())
+	case <-time.After(2 * time.Second):
+		t.Fatal("evidence is not gossiped")
+	}
+	// the evidence is already known by backend
+	added, err := be.AddEvidence(expected)
+	require.NoError(t, err)
+	assert.False(t, added)
+}
@@ -293,6 +293,10 @@ func (c *core) handlePrevote(msg message) error {
 	}
 	//log.Info("received prevote", "from", msg.Address, "round", vote.Round, "block_hash", vote.BlockHash.Hex())
 	added, err := state.addPrevote(msg, &vote, c.valSet)
+	if err == ErrConflictingVotes {
+		prevotes, _ := state.GetPrevotesByRound(vote.Round)
+		c.handleConflictingVotes(prevotes, msg)
+	}
 	if err != nil {
 		return err
 	}
@@ -381,6 +385,10 @@ func (c *core) handlePrecommit(msg message) error {
 	}
 	//log.Info("received precommit", "from", msg.Address, "round", vote.Round, "block_hash", vote.BlockHash.Hex())
 	added, err := state.addPrecommit(msg, &vote, c.valSet)
+	if err == ErrConflictingVotes {
+		precommits, _ := state.GetPrecommitsByRound(vote.Round)
+		c.handleConflictingVotes(precommits, msg)
+	}
 	if err != nil {
 		return err
 	}
@@ -526,6 +534,8 @@ func (c *core) handleMsgLocked(msg message) error {
 		return c.handleCatchupRequest(msg)
 	case msgCatchUpReply:
 		return c.handleCatchUpReply(msg)
+	case msgEvidence:
+		return c.handleEvidence(msg)
 	default:
 		return fmt.Errorf("unknown msg code %d", msg.Code)
 	}
@@ -26,6 +26,7 @@ func newTestCore(backend tendermint.Backend, config *tendermint.Config) *core {
 		backend:        backend,
 		timeout:        NewTimeoutTicker(),
 		config:         config,
+		baseConfig:     config,
 		mu:             &sync.RWMutex{},
 		blockFinalize:  new(event.TypeMux),
 		futureMessages: queue.NewPriorityQueue(0, true),
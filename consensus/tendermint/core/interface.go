@@ -7,4 +7,6 @@ package core
 type Engine interface {
 	Start() error
 	Stop() error
+	// ConsensusState returns a snapshot of the consensus state, it returns nil if core has never been started
+	ConsensusState() *ConsensusState
 }
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+)
+
+// ConsensusState is a snapshot of the consensus state of core for introspection
+type ConsensusState struct {
+	BlockNumber *hexutil.Big      `json:"blockNumber"`
+	Round       int64             `json:"round"`
+	Step        string            `json:"step"`
+	StartTime   time.Time         `json:"startTime"`
+	Proposer    common.Address    `json:"proposer"`
+	Validators  []common.Address  `json:"validators"`
+	LockedRound int64             `json:"lockedRound"`
+	LockedBlock *common.Hash      `json:"lockedBlock"`
+	ValidRound  int64             `json:"validRound"`
+	ValidBlock  *common.Hash      `json:"validBlock"`
+	Votes       []*RoundVotes     `json:"votes"`
+	CatchUp     *CatchUpState     `json:"catchUp"`
+	Rebroadcast *RebroadcastState `json:"rebroadcast"`
+}
+
+// RoundVotes is the votes received in a round.
+// The bit-arrays are ordered as the validators, 'x' marks a received vote and '_' a missing one.
+type RoundVotes struct {
+	Round           int64        `json:"round"`
+	Prevotes        string       `json:"prevotes"`
+	PrevotesMaj23   *common.Hash `json:"prevotesMaj23"`
+	Precommits      string       `json:"precommits"`
+	PrecommitsMaj23 *common.Hash `json:"precommitsMaj23"`
+}
+
+// CatchUpState is the last catch-up request sent when core was stuck waiting for votes
+type CatchUpState struct {
+	BlockNumber *hexutil.Big `json:"blockNumber"`
+	Round       int64        `json:"round"`
+	Step        string       `json:"step"`
+	Retry       uint64       `json:"retry"`
+	Time        time.Time    `json:"time"`
+}
+
+// RebroadcastState describes how core re-gossips the votes and serves the catch-up requests
+type RebroadcastState struct {
+	Enabled   bool             `json:"enabled"`
+	Neighbors []common.Address `json:"neighbors"` // validators which the received votes are re-gossiped to
+	SentMsgs  int              `json:"sentMsgs"`  // own proposal and votes of the current height kept for catch-up
+}
+
+// bitArray returns the bit-array of the validators which sent a vote in the set, nil set means no vote was received
+func (ms *messageSet) bitArray(validators []common.Address) (string, *common.Hash) {
+	var (
+		bits  strings.Builder
+		maj23 *common.Hash
+	)
+	if ms != nil {
+		ms.messagesMu.Lock()
+		defer ms.messagesMu.Unlock()
+		if ms.maj23 != nil {
+			hash := *ms.maj23
+			maj23 = &hash
+		}
+	}
+	for _, addr := range validators {
+		if ms != nil && ms.voteByAddress[addr] != nil {
+			bits.WriteByte('x')
+		} else {
+			bits.WriteByte('_')
+		}
+	}
+	return bits.String(), maj23
+}
+
+// ConsensusState implements core.Engine.ConsensusState
+func (c *core) ConsensusState() *ConsensusState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	state := c.currentState
+	if state == nil || c.valSet == nil {
+		return nil
+	}
+
+	validators := make([]common.Address, 0, c.valSet.Size())
+	for _, val := range c.valSet.List() {
+		validators = append(validators, val.Address())
+	}
+	cs := &ConsensusState{
+		BlockNumber: (*hexutil.Big)(state.CopyBlockNumber()),
+		Round:       state.Round(),
+		Step:        state.Step().String(),
+		StartTime:   state.startTime,
+		Validators:  validators,
+		LockedRound: state.LockedRound(),
+		ValidRound:  state.ValidRound(),
+		CatchUp:     c.catchUp,
+		Rebroadcast: &RebroadcastState{
+			Enabled:  c.rebroadcast,
+			SentMsgs: c.sentMsgStorage.size(),
+		},
+	}
+	if proposer := c.valSet.GetProposer(); proposer != nil {
+		cs.Proposer = proposer.Address()
+	}
+	if block := state.LockedBlock(); block != nil {
+		hash := block.Hash()
+		cs.LockedBlock = &hash
+	}
+	if block := state.ValidBlock(); block != nil {
+		hash := block.Hash()
+		cs.ValidBlock = &hash
+	}
+	for addr := range c.valSet.GetNeighbors(c.getAddress()) {
+		cs.Rebroadcast.Neighbors = append(cs.Rebroadcast.Neighbors, addr)
+	}
+	sort.Slice(cs.Rebroadcast.Neighbors, func(i, j int) bool {
+		return bytes.Compare(cs.Rebroadcast.Neighbors[i].Bytes(), cs.Rebroadcast.Neighbors[j].Bytes()) < 0
+	})
+
+	rounds := make(map[int64]bool)
+	for round := range state.PrevotesReceived {
+		rounds[round] = true
+	}
+	for round := range state.PrecommitsReceived {
+		rounds[round] = true
+	}
+	for round := range rounds {
+		votes := &RoundVotes{Round: round}
+		votes.Prevotes, votes.PrevotesMaj23 = state.PrevotesReceived[round].bitArray(validators)
+		votes.Precommits, votes.PrecommitsMaj23 = state.PrecommitsReceived[round].bitArray(validators)
+		cs.Votes = append(cs.Votes, votes)
+	}
+	sort.Slice(cs.Votes, func(i, j int) bool {
+		return cs.Votes[i].Round < cs.Votes[j].Round
+	})
+	return cs
+}
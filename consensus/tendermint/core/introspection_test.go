@@ -0,0 +1,58 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+func TestCore_ConsensusState(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		otherKey       = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		otherAddr      = crypto.PubkeyToAddress(otherKey.PublicKey)
+		validators     = []common.Address{
+			nodeAddr,
+			otherAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	core := newTestCore(be, tendermint.DefaultConfig)
+	assert.Nil(t, core.ConsensusState())
+	require.NoError(t, core.Start())
+	defer core.Stop()
+
+	require.NoError(t, core.handleMsg(createSignedVote(t, otherKey, msgPrevote, big.NewInt(1), 0, common.HexToHash("0xa"))))
+
+	state := core.ConsensusState()
+	require.NotNil(t, state)
+	assert.Equal(t, int64(1), state.BlockNumber.ToInt().Int64())
+	assert.Equal(t, int64(0), state.Round)
+	assert.Equal(t, int64(-1), state.LockedRound)
+	assert.Nil(t, state.LockedBlock)
+	assert.Nil(t, state.CatchUp)
+	// the test core does not rebroadcast
+	assert.False(t, state.Rebroadcast.Enabled)
+	assert.Equal(t, []common.Address{otherAddr}, state.Rebroadcast.Neighbors)
+	require.Len(t, state.Validators, 2)
+
+	expectedPrevotes := "_x"
+	if bytes.Compare(otherAddr.Bytes(), nodeAddr.Bytes()) < 0 {
+		expectedPrevotes = "x_"
+	}
+	require.Len(t, state.Votes, 1)
+	assert.Equal(t, int64(0), state.Votes[0].Round)
+	assert.Equal(t, expectedPrevotes, state.Votes[0].Prevotes)
+	assert.Equal(t, "__", state.Votes[0].Precommits)
+	assert.Nil(t, state.Votes[0].PrevotesMaj23)
+}
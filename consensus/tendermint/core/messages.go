@@ -1,7 +1,7 @@
 package core
 
 import (
-	"io"
+	"math/big"
 	"sync"
 
 	"github.com/Workiva/go-datastructures/queue"
@@ -9,7 +9,7 @@ import (
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
-	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/wire"
 	"github.com/lvbin2012/NeuralChain/log"
 	"github.com/lvbin2012/NeuralChain/rlp"
 )
@@ -19,63 +19,18 @@ var (
 	ErrDifferentMsgType = errors.New("message set is not of the same type of the received message")
 )
 
-// TODO: More msg codes here if needed
+// The consensus messages and their codes are the ones of the wire package, which the signers decode as well
 const (
-	msgPropose uint64 = iota
-	msgPrevote
-	msgPrecommit
-	msgCatchUpRequest
-	msgCatchUpReply
+	msgPropose        = wire.MsgPropose
+	msgPrevote        = wire.MsgPrevote
+	msgPrecommit      = wire.MsgPrecommit
+	msgCatchUpRequest = wire.MsgCatchUpRequest
+	msgCatchUpReply   = wire.MsgCatchUpReply
+	msgEvidence       = wire.MsgEvidence
 )
 
 //message is used to store consensus information between steps
-type message struct {
-	Code      uint64
-	Msg       []byte
-	Address   common.Address
-	Signature []byte
-}
-
-// EncodeRLP serializes m into the NeuralChain RLP format.
-func (m *message) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{m.Code, m.Msg, m.Address, m.Signature})
-}
-
-// DecodeRLP implements rlp.Decoder, and load the consensus fields from a RLP stream.
-func (m *message) DecodeRLP(s *rlp.Stream) error {
-	type msg message
-	var decodedMsg msg
-	if err := s.Decode(&decodedMsg); err != nil {
-		return err
-	}
-	m.Code, m.Msg, m.Address, m.Signature = decodedMsg.Code, decodedMsg.Msg, decodedMsg.Address, decodedMsg.Signature
-	return nil
-}
-
-func (m *message) PayLoadWithoutSignature() ([]byte, error) {
-	return rlp.EncodeToBytes(&message{
-		Code:      m.Code,
-		Address:   m.Address,
-		Msg:       m.Msg,
-		Signature: []byte{},
-	})
-}
-
-// GetAddressFromSignature gets the signer address from the signature
-func (m *message) GetAddressFromSignature() (common.Address, error) {
-	payLoad, err := m.PayLoadWithoutSignature()
-	if err != nil {
-		return common.Address{}, err
-	}
-	hashData := crypto.Keccak256(payLoad)
-
-	// 2. Recover public key
-	pubkey, err := crypto.SigToPub(hashData, m.Signature)
-	if err != nil {
-		return common.Address{}, err
-	}
-	return crypto.PubkeyToAddress(*pubkey), nil
-}
+type message = wire.Message
 
 type msgItem struct {
 	message interface{}
@@ -102,6 +57,7 @@ func (item *msgItem) Compare(other queue.Item) int {
 type blockVotes struct {
 	votes         []*Vote // validatorIndex -> *Vote
 	totalReceived int
+	votingPower   *big.Int // sum of the voting power of the validators voting for the block
 }
 
 type messageSet struct {
@@ -114,6 +70,7 @@ type messageSet struct {
 	voteByBlock   map[common.Hash]*blockVotes
 	maj23         *common.Hash
 	totalReceived int
+	votingPower   *big.Int // sum of the voting power of the validators sending message
 	//TODO: Do we have to keep track of which peer has 2/3Majority?
 }
 
@@ -127,6 +84,7 @@ func newMessageSet(valSet tendermint.ValidatorSet, code uint64, view *tendermint
 		voteByBlock:   make(map[common.Hash]*blockVotes),
 		voteByAddress: make(map[common.Address]*Vote),
 		valSet:        valSet,
+		votingPower:   new(big.Int),
 	}
 }
 
@@ -149,7 +107,7 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	if ms.msgCode != msg.Code {
 		return false, ErrDifferentMsgType
 	}
-	index, _ := ms.valSet.GetByAddress(msg.Address)
+	index, validator := ms.valSet.GetByAddress(msg.Address)
 	if index == -1 {
 		return false, errors.Wrapf(ErrVoteInvalidValidatorAddress, "address in vote message:%s ", msg.Address.String())
 	}
@@ -179,11 +137,12 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	ms.messages[msg.Address] = &msg
 	ms.voteByAddress[msg.Address] = vote
 	ms.totalReceived++
-	if err := ms.addVoteToBlockVote(vote, index); err != nil {
+	ms.votingPower.Add(ms.votingPower, validator.VotingPower())
+	if err := ms.addVoteToBlockVote(vote, index, validator.VotingPower()); err != nil {
 		return false, err
 	}
 
-	if ms.voteByBlock[copyHash].totalReceived >= ms.valSet.MinMajority() {
+	if ms.voteByBlock[copyHash].votingPower.Cmp(ms.valSet.QuorumVotingPower()) >= 0 {
 		if ms.maj23 == nil {
 			ms.maj23 = &copyHash
 		}
@@ -192,12 +151,13 @@ func (ms *messageSet) AddVote(msg message, vote *Vote) (bool, error) {
 	return true, nil
 }
 
-func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int) error {
+func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int, votingPower *big.Int) error {
 	bvotes, exist := ms.voteByBlock[*(vote.BlockHash)]
 	if !exist {
 		bvotes = &blockVotes{
 			votes:         make([]*Vote, ms.valSet.Size()),
 			totalReceived: 0,
+			votingPower:   new(big.Int),
 		}
 	}
 	//shouldn't happen but just making sure
@@ -206,6 +166,7 @@ func (ms *messageSet) addVoteToBlockVote(vote *Vote, index int) error {
 	}
 	bvotes.votes[index] = vote
 	bvotes.totalReceived++
+	bvotes.votingPower.Add(bvotes.votingPower, votingPower)
 	ms.voteByBlock[*(vote.BlockHash)] = bvotes
 	return nil
 }
@@ -225,7 +186,7 @@ func (ms *messageSet) HasTwoThirdAny() bool {
 	}
 	ms.messagesMu.Lock()
 	defer ms.messagesMu.Unlock()
-	return ms.totalReceived >= ms.valSet.MinMajority()
+	return ms.votingPower.Cmp(ms.valSet.QuorumVotingPower()) >= 0
 }
 
 //TwoThirdMajority return a blockHash and a bool inidicate if this messageSet hash got a
@@ -242,6 +203,14 @@ func (ms *messageSet) TwoThirdMajority() (common.Hash, bool) {
 	return common.Hash{}, false
 }
 
+//MessageByAddress returns the message received from the given address
+func (ms *messageSet) MessageByAddress(addr common.Address) (*message, bool) {
+	ms.messagesMu.Lock()
+	defer ms.messagesMu.Unlock()
+	msg, ok := ms.messages[addr]
+	return msg, ok
+}
+
 //MissingVotes returns a set of address not sending vote
 func (ms *messageSet) MissingVotes() map[common.Address]bool {
 	missing := make(map[common.Address]bool)
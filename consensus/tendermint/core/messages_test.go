@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/validator"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+func TestMessageSet_WeightedMajority(t *testing.T) {
+	var (
+		bigKey   = tests_utils.MakeNodeKey()
+		smallKey = tests_utils.MakeNodeKey()
+		midKey   = tests_utils.MakeNodeKey()
+		addrs    = []common.Address{
+			crypto.PubkeyToAddress(bigKey.PublicKey),
+			crypto.PubkeyToAddress(smallKey.PublicKey),
+			crypto.PubkeyToAddress(midKey.PublicKey),
+		}
+		powers      = []*big.Int{big.NewInt(60), big.NewInt(10), big.NewInt(30)}
+		blockNumber = big.NewInt(1)
+		blockHash   = common.HexToHash("0xa")
+	)
+	valSet, err := validator.NewWeightedSet(addrs, powers, tendermint.RoundRobin, 1)
+	require.NoError(t, err)
+	msgSet := newMessageSet(valSet, msgPrevote, &tendermint.View{BlockNumber: blockNumber, Round: 0})
+
+	addVote := func(msg message) {
+		var vote Vote
+		require.NoError(t, rlp.DecodeBytes(msg.Msg, &vote))
+		added, err := msgSet.AddVote(msg, &vote)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// 2 of 3 validators voted, but their voting power is only 40%
+	addVote(createSignedVote(t, smallKey, msgPrevote, blockNumber, 0, blockHash))
+	addVote(createSignedVote(t, midKey, msgPrevote, blockNumber, 0, blockHash))
+	assert.False(t, msgSet.HasMajority())
+	assert.False(t, msgSet.HasTwoThirdAny())
+
+	addVote(createSignedVote(t, bigKey, msgPrevote, blockNumber, 0, common.HexToHash("0xb")))
+	assert.False(t, msgSet.HasMajority())
+	assert.True(t, msgSet.HasTwoThirdAny())
+}
+
+// TestVoteData_Privval checks that the signers recognize the proposals, the vote messages and the committed seals signed by core
+func TestVoteData_Privval(t *testing.T) {
+	var (
+		blockHash = common.HexToHash("0xa")
+		height    = big.NewInt(10)
+	)
+	for _, code := range []uint64{msgPrevote, msgPrecommit} {
+		msgData, err := rlp.EncodeToBytes(&Vote{BlockHash: &blockHash, BlockNumber: height, Round: 1})
+		require.NoError(t, err)
+		payload, err := (&message{Code: code, Msg: msgData, Address: common.HexToAddress("0x1")}).PayLoadWithoutSignature()
+		require.NoError(t, err)
+		step := privval.StepPrevote
+		if code == msgPrecommit {
+			step = privval.StepPrecommit
+		}
+		req := &privval.VoteRequest{Height: height.Uint64(), Round: 1, Step: step, BlockHash: blockHash, Data: payload}
+		assert.NoError(t, req.VerifyData())
+		assert.True(t, privval.IsVoteData(payload))
+	}
+
+	seal := &privval.VoteRequest{Height: height.Uint64(), Round: 1, Step: privval.StepPrecommit, BlockHash: blockHash,
+		Data: utils.PrepareCommittedSeal(blockHash)}
+	assert.NoError(t, seal.VerifyData())
+
+	block := types.NewBlockWithHeader(&types.Header{Number: height})
+	msgData, err := rlp.EncodeToBytes(&Proposal{Block: block, Round: 1, POLRound: -1})
+	require.NoError(t, err)
+	payload, err := (&message{Code: msgPropose, Msg: msgData, Address: common.HexToAddress("0x1")}).PayLoadWithoutSignature()
+	require.NoError(t, err)
+	proposal := &privval.VoteRequest{Height: height.Uint64(), Round: 1, Step: privval.StepPropose, BlockHash: block.Hash(), Data: payload}
+	assert.NoError(t, proposal.VerifyData())
+	assert.True(t, privval.IsVoteData(payload))
+}
@@ -75,6 +75,13 @@ func (c *msgStorage) get(index int) ([]byte, error) {
 	return c.savedMsg[index].Data, nil
 }
 
+// size returns the number of messages stored
+func (c *msgStorage) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.savedMsg)
+}
+
 // truncateMsgStored removes all data stored by the block's number
 func (c *msgStorage) truncateMsgStored(logger *zap.SugaredLogger) {
 	c.mu.Lock()
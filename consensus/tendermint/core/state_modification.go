@@ -74,6 +74,7 @@ func (c *core) updateStateForNewblock() {
 		Round:       0,
 		BlockNumber: height.Add(height, big.NewInt(1)),
 	})
+	c.updateConfig(state.BlockNumber())
 
 	if state.commitTime.IsZero() {
 		// "Now" makes it easier to sync up dev nodes.
@@ -89,5 +90,11 @@ func (c *core) updateStateForNewblock() {
 	c.currentState = state
 	c.valSet = c.backend.Validators(c.CurrentState().BlockNumber())
 	c.futureProposals = make(map[int64]message)
+	c.resetWAL()
 	logger.Infow("updated to new block", "new_block_number", state.BlockNumber())
 }
+
+// updateConfig applies the timeouts approved by the governance contract for the epoch of the block number
+func (c *core) updateConfig(blockNumber *big.Int) {
+	c.config = c.baseConfig.WithGovernedParams(c.backend.GovernedParams(blockNumber))
+}
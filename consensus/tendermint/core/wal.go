@@ -0,0 +1,386 @@
+package core
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+const (
+	// walStateCode marks an entry written on a step transition
+	walStateCode uint64 = iota
+	// walMsgCode marks an entry written before a signed proposal/ vote is broadcast
+	walMsgCode
+)
+
+const (
+	walHeaderSize    = 8       // 4 bytes crc32 + 4 bytes length
+	maxWALEntrySize  = 1 << 26 // 64MB, an entry larger than this is treated as corrupted
+	walFilePerm      = 0600
+	walDirectoryPerm = 0700
+)
+
+var (
+	// ErrWALClosed is returned when writing to a closed WAL
+	ErrWALClosed = errors.New("consensus wal is closed")
+	// ErrWALCorrupted is returned when an entry of the WAL does not match its checksum
+	ErrWALCorrupted = errors.New("consensus wal is corrupted")
+
+	walCrcTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// WAL is the write-ahead log used by core to persist its consensus state.
+// Every write must be on stable storage when Write returns, so that a crashed node
+// never signs a message conflicting with one it has already sent.
+type WAL interface {
+	// Write appends the entry to the log and flushes it to disk
+	Write(entry *WALEntry) error
+	// ReadAll returns all entries stored in the log in written order
+	ReadAll() ([]*WALEntry, error)
+	// Truncate removes all entries from the log
+	Truncate() error
+	// Close closes the underlying storage
+	Close() error
+}
+
+// WALEntry is a snapshot of the consensus state at a step transition or
+// before a signed message is broadcast. Payload is only set for walMsgCode.
+type WALEntry struct {
+	Code        uint64
+	BlockNumber *big.Int
+	Round       int64
+	Step        RoundStepType
+	LockedRound int64
+	LockedBlock *types.Block
+	ValidRound  int64
+	ValidBlock  *types.Block
+	Payload     []byte
+}
+
+// EncodeRLP serializes e into the NeuralChain RLP format.
+func (e *WALEntry) EncodeRLP(w io.Writer) error {
+	lockedBlock, err := encodeWALBlock(e.LockedBlock)
+	if err != nil {
+		return err
+	}
+	validBlock, err := encodeWALBlock(e.ValidBlock)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, []interface{}{
+		e.Code,
+		e.BlockNumber,
+		strconv.FormatInt(e.Round, 10),
+		e.Step,
+		strconv.FormatInt(e.LockedRound, 10),
+		lockedBlock,
+		strconv.FormatInt(e.ValidRound, 10),
+		validBlock,
+		e.Payload,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder, and load the wal entry fields from a RLP stream.
+func (e *WALEntry) DecodeRLP(s *rlp.Stream) error {
+	var es struct {
+		Code        uint64
+		BlockNumber *big.Int
+		RStr        string
+		Step        RoundStepType
+		LockedRStr  string
+		LockedBlock []byte
+		ValidRStr   string
+		ValidBlock  []byte
+		Payload     []byte
+	}
+	if err := s.Decode(&es); err != nil {
+		return err
+	}
+	round, err := strconv.ParseInt(es.RStr, 10, 64)
+	if err != nil {
+		return err
+	}
+	lockedRound, err := strconv.ParseInt(es.LockedRStr, 10, 64)
+	if err != nil {
+		return err
+	}
+	validRound, err := strconv.ParseInt(es.ValidRStr, 10, 64)
+	if err != nil {
+		return err
+	}
+	lockedBlock, err := decodeWALBlock(es.LockedBlock)
+	if err != nil {
+		return err
+	}
+	validBlock, err := decodeWALBlock(es.ValidBlock)
+	if err != nil {
+		return err
+	}
+	e.Code, e.BlockNumber, e.Round, e.Step = es.Code, es.BlockNumber, round, es.Step
+	e.LockedRound, e.LockedBlock = lockedRound, lockedBlock
+	e.ValidRound, e.ValidBlock = validRound, validBlock
+	e.Payload = es.Payload
+	return nil
+}
+
+func encodeWALBlock(block *types.Block) ([]byte, error) {
+	if block == nil {
+		return []byte{}, nil
+	}
+	return rlp.EncodeToBytes(block)
+}
+
+func decodeWALBlock(data []byte) (*types.Block, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var block types.Block
+	if err := rlp.DecodeBytes(data, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// fileWAL is the WAL implementation which stores entries in a single append-only file.
+// Each entry is written as: crc32(data) | len(data) | data
+type fileWAL struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// OpenFileWAL opens (or creates) the WAL file at the given path
+func OpenFileWAL(path string) (WAL, error) {
+	if err := os.MkdirAll(filepath.Dir(path), walDirectoryPerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, walFilePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &fileWAL{file: file}, nil
+}
+
+// Write implements WAL.Write
+func (w *fileWAL) Write(entry *WALEntry) error {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, walHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf[0:4], crc32.Checksum(data, walCrcTable))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(len(data)))
+	copy(buf[walHeaderSize:], data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return ErrWALClosed
+	}
+	if _, err := w.file.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := w.file.Write(buf); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// ReadAll implements WAL.ReadAll
+// A partially written or corrupted tail (i.e, the node crashed while writing) is discarded
+// and the file is truncated to the last valid entry.
+func (w *fileWAL) ReadAll() ([]*WALEntry, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil, ErrWALClosed
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var (
+		reader  = bufio.NewReader(w.file)
+		entries []*WALEntry
+		offset  int64
+		header  = make([]byte, walHeaderSize)
+	)
+	for {
+		entry, size, err := readWALEntry(reader, header)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			log.Warn("discarding corrupted tail of consensus wal", "offset", offset, "err", err)
+			if err := w.file.Truncate(offset); err != nil {
+				return nil, err
+			}
+			return entries, w.file.Sync()
+		}
+		entries = append(entries, entry)
+		offset += size
+	}
+}
+
+func readWALEntry(reader io.Reader, header []byte) (*WALEntry, int64, error) {
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, 0, ErrWALCorrupted
+		}
+		return nil, 0, err
+	}
+	var (
+		checksum = binary.BigEndian.Uint32(header[0:4])
+		length   = binary.BigEndian.Uint32(header[4:8])
+	)
+	if length > maxWALEntrySize {
+		return nil, 0, ErrWALCorrupted
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, 0, ErrWALCorrupted
+	}
+	if crc32.Checksum(data, walCrcTable) != checksum {
+		return nil, 0, ErrWALCorrupted
+	}
+	var entry WALEntry
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		return nil, 0, err
+	}
+	return &entry, int64(walHeaderSize) + int64(length), nil
+}
+
+// Truncate implements WAL.Truncate
+func (w *fileWAL) Truncate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return ErrWALClosed
+	}
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// Close implements WAL.Close
+func (w *fileWAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// newWALEntry returns a snapshot of the current state with the given code, round, step and payload
+func (c *core) newWALEntry(code uint64, round int64, step RoundStepType, payload []byte) *WALEntry {
+	state := c.CurrentState()
+	return &WALEntry{
+		Code:        code,
+		BlockNumber: state.CopyBlockNumber(),
+		Round:       round,
+		Step:        step,
+		LockedRound: state.LockedRound(),
+		LockedBlock: state.LockedBlock(),
+		ValidRound:  state.ValidRound(),
+		ValidBlock:  state.ValidBlock(),
+		Payload:     payload,
+	}
+}
+
+// updateRoundStep moves the current state to the given round and step and records the transition to the WAL
+func (c *core) updateRoundStep(round int64, step RoundStepType) {
+	c.CurrentState().UpdateRoundStep(round, step)
+	if c.wal == nil {
+		return
+	}
+	if err := c.wal.Write(c.newWALEntry(walStateCode, round, step, nil)); err != nil {
+		c.getLogger().Errorw("failed to write step transition to wal", "err", err)
+	}
+}
+
+// writeSignedMsgToWAL records a signed message to the WAL, it must be called before the message is broadcast
+func (c *core) writeSignedMsgToWAL(step RoundStepType, round int64, payload []byte) error {
+	if c.wal == nil {
+		return nil
+	}
+	return c.wal.Write(c.newWALEntry(walMsgCode, round, step, payload))
+}
+
+// resetWAL discards entries of the previous heights and records the new height's state
+func (c *core) resetWAL() {
+	if c.wal == nil {
+		return
+	}
+	if err := c.wal.Truncate(); err != nil {
+		c.getLogger().Errorw("failed to truncate wal", "err", err)
+		return
+	}
+	state := c.CurrentState()
+	if err := c.wal.Write(c.newWALEntry(walStateCode, state.Round(), state.Step(), nil)); err != nil {
+		c.getLogger().Errorw("failed to write new height to wal", "err", err)
+	}
+}
+
+// replayWAL restores round, step, locked and valid block and the sent messages of the current height
+// from the WAL. It is called on start, before core subscribes to any event.
+func (c *core) replayWAL() error {
+	if c.wal == nil {
+		return nil
+	}
+	entries, err := c.wal.ReadAll()
+	if err != nil {
+		return err
+	}
+	var (
+		state    = c.CurrentState()
+		replayed = 0
+	)
+	for _, entry := range entries {
+		// entries from older heights are stale, the block has been committed already
+		if entry.BlockNumber == nil || entry.BlockNumber.Cmp(state.BlockNumber()) != 0 {
+			continue
+		}
+		if entry.Round > state.Round() || (entry.Round == state.Round() && entry.Step > state.Step()) {
+			state.UpdateRoundStep(entry.Round, entry.Step)
+		}
+		state.SetLockedRoundAndBlock(entry.LockedRound, entry.LockedBlock)
+		state.SetValidRoundAndBlock(entry.ValidRound, entry.ValidBlock)
+		if entry.Code == walMsgCode && len(entry.Payload) > 0 {
+			c.sentMsgStorage.storeSentMsg(c.getLogger(), entry.Step, entry.Round, entry.Payload)
+		}
+		replayed++
+	}
+	if replayed == 0 {
+		return nil
+	}
+	// commit and new round are transient steps without any timeout,
+	// resume from the closest step which makes core progress without signing again.
+	switch state.Step() {
+	case RoundStepCommit:
+		state.UpdateRoundStep(state.Round(), RoundStepPrecommit)
+	case RoundStepNewRound:
+		state.UpdateRoundStep(state.Round(), RoundStepPropose)
+	}
+	if state.Round() > 0 && c.valSet != nil && c.valSet.GetProposer() != nil {
+		c.valSet.CalcProposer(c.valSet.GetProposer().Address(), state.Round())
+	}
+	c.getLogger().Infow("replayed consensus wal", "entries", replayed,
+		"locked_round", state.LockedRound(), "valid_round", state.ValidRound())
+	return nil
+}
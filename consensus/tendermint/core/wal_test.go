@@ -0,0 +1,126 @@
+package core
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/tests_utils"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+func mustOpenTestWAL(t *testing.T) (WAL, string) {
+	dir, err := ioutil.TempDir("", "tendermint-wal")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "wal")
+	wal, err := OpenFileWAL(path)
+	require.NoError(t, err)
+	return wal, dir
+}
+
+func TestFileWAL_WriteAndReadAll(t *testing.T) {
+	wal, dir := mustOpenTestWAL(t)
+	defer os.RemoveAll(dir)
+	defer wal.Close()
+
+	lockedBlock := tests_utils.MakeBlockWithoutSeal(tests_utils.MakeGenesisHeader([]common.Address{tests_utils.GetAddress()}))
+	entries := []*WALEntry{
+		{Code: walStateCode, BlockNumber: big.NewInt(1), Round: 0, Step: RoundStepNewHeight, LockedRound: -1, ValidRound: -1},
+		{Code: walMsgCode, BlockNumber: big.NewInt(1), Round: 1, Step: RoundStepPrecommit, LockedRound: 1, LockedBlock: lockedBlock,
+			ValidRound: -1, Payload: []byte("precommit")},
+	}
+	for _, entry := range entries {
+		require.NoError(t, wal.Write(entry))
+	}
+
+	read, err := wal.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	assert.Equal(t, RoundStepNewHeight, read[0].Step)
+	assert.Equal(t, int64(-1), read[0].LockedRound)
+	assert.Nil(t, read[0].LockedBlock)
+	assert.Equal(t, int64(1), read[1].Round)
+	assert.Equal(t, int64(1), read[1].LockedRound)
+	assert.Equal(t, lockedBlock.Hash(), read[1].LockedBlock.Hash())
+	assert.Equal(t, []byte("precommit"), read[1].Payload)
+
+	require.NoError(t, wal.Truncate())
+	read, err = wal.ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, read, 0)
+}
+
+func TestFileWAL_DiscardCorruptedTail(t *testing.T) {
+	wal, dir := mustOpenTestWAL(t)
+	defer os.RemoveAll(dir)
+
+	entry := &WALEntry{Code: walStateCode, BlockNumber: big.NewInt(1), Step: RoundStepPropose, LockedRound: -1, ValidRound: -1}
+	require.NoError(t, wal.Write(entry))
+	require.NoError(t, wal.Close())
+
+	// simulate a crash in the middle of writing an entry
+	file, err := os.OpenFile(filepath.Join(dir, "wal"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0x01, 0x02, 0x03, 0x04, 0x00, 0x00, 0x10})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	wal, err = OpenFileWAL(filepath.Join(dir, "wal"))
+	require.NoError(t, err)
+	defer wal.Close()
+	read, err := wal.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+
+	require.NoError(t, wal.Write(entry))
+	read, err = wal.ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, read, 2)
+}
+
+func TestCore_ReplayWAL(t *testing.T) {
+	var (
+		nodePrivateKey = tests_utils.MakeNodeKey()
+		nodeAddr       = crypto.PubkeyToAddress(nodePrivateKey.PublicKey)
+		validators     = []common.Address{
+			nodeAddr,
+		}
+		genesisHeader = tests_utils.MakeGenesisHeader(validators)
+	)
+	be, _ := tests_utils.MustCreateAndStartNewBackend(t, nodePrivateKey, genesisHeader, validators)
+	wal, dir := mustOpenTestWAL(t)
+	defer os.RemoveAll(dir)
+	defer wal.Close()
+
+	lockedBlock := tests_utils.MakeBlockWithoutSeal(genesisHeader)
+	// an entry of a committed height must be ignored
+	require.NoError(t, wal.Write(&WALEntry{Code: walStateCode, BlockNumber: big.NewInt(0), Round: 5,
+		Step: RoundStepPrecommit, LockedRound: -1, ValidRound: -1}))
+	require.NoError(t, wal.Write(&WALEntry{Code: walStateCode, BlockNumber: big.NewInt(1), Round: 1,
+		Step: RoundStepPrevote, LockedRound: -1, ValidRound: -1}))
+	require.NoError(t, wal.Write(&WALEntry{Code: walMsgCode, BlockNumber: big.NewInt(1), Round: 1,
+		Step: RoundStepPrecommit, LockedRound: 1, LockedBlock: lockedBlock, ValidRound: 1, ValidBlock: lockedBlock,
+		Payload: []byte("precommit")}))
+
+	core := newTestCore(be, tendermint.DefaultConfig)
+	core.wal = wal
+	require.NoError(t, core.Start())
+	defer core.Stop()
+
+	core.mu.Lock()
+	defer core.mu.Unlock()
+	state := core.CurrentState()
+	assert.Equal(t, int64(1), state.BlockNumber().Int64())
+	assert.Equal(t, int64(1), state.Round())
+	assert.Equal(t, RoundStepPrecommit, state.Step())
+	assert.Equal(t, int64(1), state.LockedRound())
+	assert.Equal(t, lockedBlock.Hash(), state.LockedBlock().Hash())
+	assert.Equal(t, 0, core.sentMsgStorage.lookup(RoundStepPrecommit, 1))
+}
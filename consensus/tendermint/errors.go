@@ -13,6 +13,10 @@ var (
 	ErrEmptyValSet = errors.New("zero validator set")
 	// ErrMismatchValSet is returned if the field of validator set is mismatch.
 	ErrMismatchValSet = errors.New("mismatch validator set")
+	// ErrUnexpectedValSet is returned if a header which is not an epoch checkpoint hands off a validator set.
+	ErrUnexpectedValSet = errors.New("validator set out of an epoch checkpoint")
+	// ErrInvalidValSetHash is returned if the validator set hash of a header does not match the validator set it hands off.
+	ErrInvalidValSetHash = errors.New("invalid validator set hash")
 	// ErrMismatchTxhashes is returned if the TxHash in header is mismatch.
 	ErrMismatchTxhashes = errors.New("mismatch transaction hashes")
 	// errInvalidSignature is returned when given signature is not signed by given
@@ -47,4 +51,18 @@ var (
 	ErrUnknownParent = errors.New("unknown parent")
 	// ErrFinalizeZeroBlock is returned if node finalize with block number = 0
 	ErrFinalizeZeroBlock = errors.New("finalize zero block")
+	// ErrZeroVotingPower is returned if the total voting power of the validator set is zero
+	ErrZeroVotingPower = errors.New("total voting power of validators is zero")
+	// ErrTooManyEvidences is returned if a block includes more evidences than allowed
+	ErrTooManyEvidences = errors.New("too many evidences")
+	// ErrUnexpectedEvidences is returned if a block includes evidences before the Evidence fork
+	ErrUnexpectedEvidences = errors.New("evidences before the evidence fork")
+	// ErrInvalidRound is returned if a round is negative
+	ErrInvalidRound = errors.New("invalid round")
+	// ErrUnexpectedParentCommittedSeals is returned if a block records the committed seals of its parent before the Liveness fork
+	ErrUnexpectedParentCommittedSeals = errors.New("parent committed seals before the liveness fork")
+	// ErrInvalidParentCommittedSeals is returned if the parent committed seals of a block are not signed by a quorum of the parent's validators
+	ErrInvalidParentCommittedSeals = errors.New("invalid parent committed seals")
+	// ErrInvalidGovernedParams is returned if the governed parameters of a block are not the ones approved by the governance contract
+	ErrInvalidGovernedParams = errors.New("invalid governed parameters")
 )
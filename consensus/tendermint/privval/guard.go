@@ -0,0 +1,126 @@
+package privval
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/lvbin2012/NeuralChain/common"
+)
+
+// SignState is the position and the block hash of the last vote signed by a validator
+type SignState struct {
+	Height    uint64      `json:"height"`
+	Round     uint64      `json:"round"`
+	Step      uint64      `json:"step"`
+	BlockHash common.Hash `json:"blockHash"`
+}
+
+// conflicts returns whether the vote is at an earlier position than the last signed vote,
+// or at the same position for another block
+func (s *SignState) conflicts(req *VoteRequest) bool {
+	switch {
+	case req.Height != s.Height:
+		return req.Height < s.Height
+	case req.Round != s.Round:
+		return req.Round < s.Round
+	case req.Step != s.Step:
+		return req.Step < s.Step
+	default:
+		return req.BlockHash != s.BlockHash
+	}
+}
+
+// Guard is the double-sign protection of a signer. It records the last proposal or vote signed by each validator
+// and refuses to sign a conflicting one. The records are persisted to a file to survive restarts.
+type Guard struct {
+	path  string // the file of the records, empty to keep them in memory
+	mu    sync.Mutex
+	votes map[common.Address]*SignState
+}
+
+// NewGuard returns a guard persisting its records to the file of the path, loading the records it
+// already holds. An empty path keeps the records in memory.
+func NewGuard(path string) (*Guard, error) {
+	g := &Guard{
+		path:  path,
+		votes: make(map[common.Address]*SignState),
+	}
+	if path == "" {
+		return g, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return g, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &g.votes); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// LastVote returns the last vote signed by the validator, nil if there is none
+func (g *Guard) LastVote(validator common.Address) *SignState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state, ok := g.votes[validator]; ok {
+		copied := *state
+		return &copied
+	}
+	return nil
+}
+
+// SignVote signs the vote of the validator with the sign function, unless the data is not the one of the vote
+// or the vote conflicts with the last vote signed by the validator. The signature is only returned once the vote is recorded.
+func (g *Guard) SignVote(validator common.Address, req *VoteRequest, sign func() ([]byte, error)) ([]byte, error) {
+	// the position of the vote only protects against double signing if the data is the one of the vote
+	if err := req.VerifyData(); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	last, ok := g.votes[validator]
+	if ok && last.conflicts(req) {
+		return nil, ErrDoubleSign
+	}
+	signature, err := sign()
+	if err != nil {
+		return nil, err
+	}
+	g.votes[validator] = &SignState{
+		Height:    req.Height,
+		Round:     req.Round,
+		Step:      req.Step,
+		BlockHash: req.BlockHash,
+	}
+	if err := g.save(); err != nil {
+		if ok {
+			g.votes[validator] = last
+		} else {
+			delete(g.votes, validator)
+		}
+		return nil, err
+	}
+	return signature, nil
+}
+
+// save writes the records to the file, replacing it at once so that a crash never leaves it partially written
+func (g *Guard) save() error {
+	if g.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(g.votes, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := g.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, g.path)
+}
@@ -0,0 +1,159 @@
+package privval
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/wire"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// newVoteRequest returns the request to sign the message of the vote, encoded as by the Tendermint core
+func newVoteRequest(t *testing.T, height, round, step uint64, blockHash common.Hash) *VoteRequest {
+	code := wire.MsgPrevote
+	if step == StepPrecommit {
+		code = wire.MsgPrecommit
+	}
+	msg, err := rlp.EncodeToBytes(&wire.Vote{BlockHash: &blockHash, BlockNumber: new(big.Int).SetUint64(height), Round: int64(round), Seal: []byte{}})
+	require.NoError(t, err)
+	data, err := (&wire.Message{Code: code, Msg: msg, Address: common.HexToAddress("0x1")}).PayLoadWithoutSignature()
+	require.NoError(t, err)
+	return &VoteRequest{Height: height, Round: round, Step: step, BlockHash: blockHash, Data: data}
+}
+
+// newSealRequest returns the request to sign the committed seal of the precommit
+func newSealRequest(height, round uint64, blockHash common.Hash) *VoteRequest {
+	return &VoteRequest{Height: height, Round: round, Step: StepPrecommit, BlockHash: blockHash, Data: wire.CommittedSealData(blockHash)}
+}
+
+// newProposalRequest returns the request to sign the proposal of a block of the height with the given extra-data
+func newProposalRequest(t *testing.T, height, round uint64, extra string) *VoteRequest {
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(height), Extra: []byte(extra)})
+	msg, err := rlp.EncodeToBytes(&wire.Proposal{Block: block, Round: int64(round), POLRound: -1})
+	require.NoError(t, err)
+	data, err := (&wire.Message{Code: wire.MsgPropose, Msg: msg, Address: common.HexToAddress("0x1")}).PayLoadWithoutSignature()
+	require.NoError(t, err)
+	return &VoteRequest{Height: height, Round: round, Step: StepPropose, BlockHash: block.Hash(), Data: data}
+}
+
+func TestGuard_SignVote(t *testing.T) {
+	var (
+		validator = common.HexToAddress("0x1")
+		blockA    = common.HexToHash("0xa")
+		blockB    = common.HexToHash("0xb")
+	)
+	guard, err := NewGuard("")
+	require.NoError(t, err)
+	sign := func(req *VoteRequest) error {
+		_, err := guard.SignVote(validator, req, func() ([]byte, error) { return req.Data, nil })
+		return err
+	}
+
+	require.NoError(t, sign(newVoteRequest(t, 10, 1, StepPrevote, blockA)))
+	require.NoError(t, sign(newVoteRequest(t, 10, 1, StepPrevote, blockA)))
+	require.Equal(t, ErrDoubleSign, sign(newVoteRequest(t, 10, 1, StepPrevote, blockB)))
+
+	// the seal and the message of the precommit are signed for the same block
+	require.NoError(t, sign(newSealRequest(10, 1, blockB)))
+	require.NoError(t, sign(newVoteRequest(t, 10, 1, StepPrecommit, blockB)))
+	require.Equal(t, ErrDoubleSign, sign(newSealRequest(10, 1, blockA)))
+	require.Equal(t, ErrDoubleSign, sign(newVoteRequest(t, 10, 1, StepPrevote, blockA)))
+	require.Equal(t, ErrDoubleSign, sign(newVoteRequest(t, 10, 0, StepPrecommit, blockB)))
+	require.Equal(t, ErrDoubleSign, sign(newVoteRequest(t, 9, 5, StepPrecommit, blockB)))
+
+	// the data must be the one of the declared vote, otherwise a conflicting vote could be signed at the last position
+	conflicting := newVoteRequest(t, 10, 1, StepPrecommit, blockA)
+	conflicting.BlockHash = blockB
+	require.Equal(t, ErrInvalidVoteData, sign(conflicting))
+	require.Equal(t, ErrInvalidVoteData, sign(&VoteRequest{Height: 10, Round: 1, Step: StepPrecommit, BlockHash: blockB, Data: []byte("msg")}))
+
+	require.NoError(t, sign(newVoteRequest(t, 10, 2, StepPrevote, blockA)))
+	require.NoError(t, sign(newVoteRequest(t, 11, 0, StepPrevote, common.Hash{})))
+	require.Equal(t, &SignState{Height: 11, Round: 0, Step: StepPrevote}, guard.LastVote(validator))
+
+	// a proposer signs a single proposal per round, before its votes
+	require.NoError(t, sign(newProposalRequest(t, 12, 0, "a")))
+	require.NoError(t, sign(newProposalRequest(t, 12, 0, "a")))
+	require.Equal(t, ErrDoubleSign, sign(newProposalRequest(t, 12, 0, "b")))
+	require.NoError(t, sign(newVoteRequest(t, 12, 0, StepPrevote, newProposalRequest(t, 12, 0, "a").BlockHash)))
+	require.Equal(t, ErrDoubleSign, sign(newProposalRequest(t, 12, 0, "a")))
+	require.NoError(t, sign(newProposalRequest(t, 12, 1, "b")))
+
+	// the votes of the validators are guarded separately
+	require.Nil(t, guard.LastVote(common.HexToAddress("0x2")))
+	_, err = guard.SignVote(common.HexToAddress("0x2"), newVoteRequest(t, 1, 0, StepPrevote, blockA), func() ([]byte, error) { return nil, nil })
+	require.NoError(t, err)
+}
+
+func TestVoteRequest_VerifyData(t *testing.T) {
+	blockA := common.HexToHash("0xa")
+	require.NoError(t, newVoteRequest(t, 10, 1, StepPrevote, blockA).VerifyData())
+	require.NoError(t, newSealRequest(10, 1, blockA).VerifyData())
+
+	// a prevote carries no committed seal
+	seal := newSealRequest(10, 1, blockA)
+	seal.Step = StepPrevote
+	require.Equal(t, ErrInvalidVoteData, seal.VerifyData())
+	for _, mutate := range []func(req *VoteRequest){
+		func(req *VoteRequest) { req.Height = 11 },
+		func(req *VoteRequest) { req.Round = 2 },
+		func(req *VoteRequest) { req.Step = StepPrecommit },
+		func(req *VoteRequest) { req.BlockHash = common.HexToHash("0xb") },
+	} {
+		req := newVoteRequest(t, 10, 1, StepPrevote, blockA)
+		mutate(req)
+		require.Equal(t, ErrInvalidVoteData, req.VerifyData())
+	}
+
+	// the proposal must be the one of the declared block
+	require.NoError(t, newProposalRequest(t, 10, 1, "a").VerifyData())
+	for _, mutate := range []func(req *VoteRequest){
+		func(req *VoteRequest) { req.Height = 11 },
+		func(req *VoteRequest) { req.Round = 2 },
+		func(req *VoteRequest) { req.Step = StepPrevote },
+		func(req *VoteRequest) { req.BlockHash = common.HexToHash("0xb") },
+	} {
+		req := newProposalRequest(t, 10, 1, "a")
+		mutate(req)
+		require.Equal(t, ErrInvalidVoteData, req.VerifyData())
+	}
+
+	// the proposals, the votes and the seals are recognized among the data signed without their position
+	require.True(t, IsVoteData(newProposalRequest(t, 10, 1, "a").Data))
+	require.True(t, IsVoteData(newVoteRequest(t, 10, 1, StepPrecommit, blockA).Data))
+	require.True(t, IsVoteData(newSealRequest(10, 1, blockA).Data))
+	catchUp, err := (&wire.Message{Code: wire.MsgCatchUpRequest, Msg: []byte("catch up")}).PayLoadWithoutSignature()
+	require.NoError(t, err)
+	require.False(t, IsVoteData(catchUp))
+	require.False(t, IsVoteData([]byte("data")))
+}
+
+func TestGuard_Persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "privval")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		path      = filepath.Join(dir, "votes.json")
+		validator = common.HexToAddress("0x1")
+		vote      = newVoteRequest(t, 10, 1, StepPrecommit, common.HexToHash("0xa"))
+	)
+	guard, err := NewGuard(path)
+	require.NoError(t, err)
+	_, err = guard.SignVote(validator, vote, func() ([]byte, error) { return nil, nil })
+	require.NoError(t, err)
+
+	// a restarted signer still refuses the conflicting votes
+	guard, err = NewGuard(path)
+	require.NoError(t, err)
+	require.Equal(t, &SignState{Height: 10, Round: 1, Step: StepPrecommit, BlockHash: vote.BlockHash}, guard.LastVote(validator))
+	_, err = guard.SignVote(validator, newVoteRequest(t, 10, 1, StepPrecommit, common.Hash{}), func() ([]byte, error) { return nil, nil })
+	require.Equal(t, ErrDoubleSign, err)
+}
@@ -0,0 +1,37 @@
+package privval
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+// keySigner signs with a private key held in memory, like the node key
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner returns a signer of the private key. It keeps no double-sign protection.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// Address implements Signer.Address
+func (s *keySigner) Address() common.Address {
+	return s.address
+}
+
+// Sign implements Signer.Sign
+func (s *keySigner) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), s.key)
+}
+
+// SignVote implements Signer.SignVote
+func (s *keySigner) SignVote(req *VoteRequest) ([]byte, error) {
+	return s.Sign(req.Data)
+}
@@ -0,0 +1,43 @@
+// Package privval implements the signers of the Tendermint validators. The validator key may be the node key,
+// an account of the keystore or an account of an external signer keeping its own double-sign protection.
+package privval
+
+import (
+	"errors"
+
+	"github.com/lvbin2012/NeuralChain/common"
+)
+
+// Steps of the votes, in the order they are signed within a round. The proposal of the proposer
+// is guarded as the vote of the propose step.
+const (
+	StepPropose   uint64 = 0
+	StepPrevote   uint64 = 1
+	StepPrecommit uint64 = 2
+)
+
+// ErrDoubleSign is returned if a validator is requested to sign a proposal or a vote conflicting with one it signed before
+var ErrDoubleSign = errors.New("conflicting vote already signed")
+
+// VoteRequest asks a validator to sign the data of its vote for the block hash at the height, round and step.
+// The data is either the proposal message, the vote message or the committed seal of the vote.
+type VoteRequest struct {
+	Height    uint64
+	Round     uint64
+	Step      uint64
+	BlockHash common.Hash
+	Data      []byte
+}
+
+// Signer signs the consensus messages and the seals of a validator
+type Signer interface {
+	// Address returns the address of the validator key
+	Address() common.Address
+
+	// Sign signs the Keccak256 hash of the data
+	Sign(data []byte) ([]byte, error)
+
+	// SignVote signs the Keccak256 hash of the data of the proposal or vote. Signers keeping a double-sign
+	// protection return ErrDoubleSign for a proposal or vote conflicting with one they signed before.
+	SignVote(req *VoteRequest) ([]byte, error)
+}
@@ -0,0 +1,88 @@
+package privval
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/accounts"
+	"github.com/lvbin2012/NeuralChain/accounts/keystore"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+func recoverSigner(t *testing.T, data, signature []byte) common.Address {
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(data), signature)
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(*pubkey)
+}
+
+func TestKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := NewKeySigner(key)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	signature, err := signer.SignVote(&VoteRequest{Height: 1, Data: []byte("vote")})
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), recoverSigner(t, []byte("vote"), signature))
+}
+
+func TestWalletSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "privval-keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.NewAccount("password")
+	require.NoError(t, err)
+	signer := NewWalletSigner(ks.Wallets()[0], account)
+	require.Equal(t, account.Address, signer.Address())
+
+	// the validator key is encrypted until unlocked
+	_, err = signer.Sign([]byte("seal"))
+	require.Equal(t, keystore.ErrLocked, err)
+	require.NoError(t, ks.Unlock(account, "password"))
+
+	signature, err := signer.Sign([]byte("seal"))
+	require.NoError(t, err)
+	require.Equal(t, account.Address, recoverSigner(t, []byte("seal"), signature))
+	signature, err = signer.SignVote(&VoteRequest{Height: 1, Data: []byte("vote")})
+	require.NoError(t, err)
+	require.Equal(t, account.Address, recoverSigner(t, []byte("vote"), signature))
+}
+
+// externalWallet records the data an external signer is requested to sign
+type externalWallet struct {
+	accounts.Wallet
+	mimeType string
+	data     []byte
+}
+
+func (w *externalWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	w.mimeType, w.data = mimeType, data
+	return nil, nil
+}
+
+func TestExternalSigner(t *testing.T) {
+	var (
+		wallet = &externalWallet{}
+		signer = NewExternalSigner(wallet, accounts.Account{Address: common.HexToAddress("0x1")})
+		vote   = &VoteRequest{Height: 10, Round: 1, Step: StepPrecommit, BlockHash: common.HexToHash("0xa"), Data: []byte("vote")}
+	)
+	_, err := signer.Sign([]byte("seal"))
+	require.NoError(t, err)
+	require.Equal(t, accounts.MimetypeTendermint, wallet.mimeType)
+	require.Equal(t, []byte("seal"), wallet.data)
+
+	// the votes are sent with their position
+	_, err = signer.SignVote(vote)
+	require.NoError(t, err)
+	require.Equal(t, accounts.MimetypeTendermintVote, wallet.mimeType)
+	var sent VoteRequest
+	require.NoError(t, rlp.DecodeBytes(wallet.data, &sent))
+	require.Equal(t, vote, &sent)
+}
@@ -0,0 +1,80 @@
+package privval
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/wire"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// ErrInvalidVoteData is returned if the data of a vote is not the message or the committed seal of the vote
+var ErrInvalidVoteData = errors.New("vote data does not match the vote")
+
+// VerifyData checks that the data of the request is the message or the committed seal of the vote it declares.
+// The data of a vote is either its consensus message or its committed seal, encoded as by the Tendermint core.
+// The signers decode them since they can not trust the node requesting the signature. Only precommits carry a
+// committed seal.
+func (req *VoteRequest) VerifyData() error {
+	if wire.IsCommittedSealData(req.Data) {
+		if req.Step != StepPrecommit || !bytes.Equal(req.Data[:common.HashLength], req.BlockHash.Bytes()) {
+			return ErrInvalidVoteData
+		}
+		return nil
+	}
+	var msg wire.Message
+	if err := rlp.DecodeBytes(req.Data, &msg); err != nil {
+		return ErrInvalidVoteData
+	}
+	switch {
+	case req.Step == StepPropose && msg.Code == wire.MsgPropose:
+		return req.verifyProposal(msg.Msg)
+	case req.Step == StepPrevote && msg.Code == wire.MsgPrevote:
+	case req.Step == StepPrecommit && msg.Code == wire.MsgPrecommit:
+	default:
+		return ErrInvalidVoteData
+	}
+	var v wire.Vote
+	if err := rlp.DecodeBytes(msg.Msg, &v); err != nil {
+		return ErrInvalidVoteData
+	}
+	if v.Round < 0 || uint64(v.Round) != req.Round {
+		return ErrInvalidVoteData
+	}
+	if v.BlockHash == nil || *v.BlockHash != req.BlockHash {
+		return ErrInvalidVoteData
+	}
+	if v.BlockNumber == nil || !v.BlockNumber.IsUint64() || v.BlockNumber.Uint64() != req.Height {
+		return ErrInvalidVoteData
+	}
+	return nil
+}
+
+// verifyProposal checks that the proposal is the one of the block declared by the request
+func (req *VoteRequest) verifyProposal(data []byte) error {
+	var p wire.Proposal
+	if err := rlp.DecodeBytes(data, &p); err != nil {
+		return ErrInvalidVoteData
+	}
+	if p.Round < 0 || uint64(p.Round) != req.Round {
+		return ErrInvalidVoteData
+	}
+	if p.Block == nil || p.Block.Hash() != req.BlockHash || p.Block.NumberU64() != req.Height {
+		return ErrInvalidVoteData
+	}
+	return nil
+}
+
+// IsVoteData returns whether the data has the shape of a proposal, a vote message or a committed seal,
+// which must only be signed as a vote request so that the double-sign protection applies.
+func IsVoteData(data []byte) bool {
+	if wire.IsCommittedSealData(data) {
+		return true
+	}
+	var msg wire.Message
+	if err := rlp.DecodeBytes(data, &msg); err != nil {
+		return false
+	}
+	return msg.Code == wire.MsgPropose || msg.Code == wire.MsgPrevote || msg.Code == wire.MsgPrecommit
+}
@@ -0,0 +1,49 @@
+package privval
+
+import (
+	"github.com/lvbin2012/NeuralChain/accounts"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// walletSigner signs with an account of a wallet of the account manager
+type walletSigner struct {
+	wallet   accounts.Wallet
+	account  accounts.Account
+	external bool // whether the wallet is an external signer receiving the votes with their position
+}
+
+// NewWalletSigner returns a signer of the account of the wallet, like an account of the keystore.
+// The account must be unlocked to sign. It keeps no double-sign protection.
+func NewWalletSigner(wallet accounts.Wallet, account accounts.Account) Signer {
+	return &walletSigner{wallet: wallet, account: account}
+}
+
+// NewExternalSigner returns a signer of the account of an external signer. The votes are sent
+// with their position as accounts.MimetypeTendermintVote data, so that the external signer
+// refuses to sign conflicting votes.
+func NewExternalSigner(wallet accounts.Wallet, account accounts.Account) Signer {
+	return &walletSigner{wallet: wallet, account: account, external: true}
+}
+
+// Address implements Signer.Address
+func (s *walletSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// Sign implements Signer.Sign
+func (s *walletSigner) Sign(data []byte) ([]byte, error) {
+	return s.wallet.SignData(s.account, accounts.MimetypeTendermint, data)
+}
+
+// SignVote implements Signer.SignVote
+func (s *walletSigner) SignVote(req *VoteRequest) ([]byte, error) {
+	if !s.external {
+		return s.Sign(req.Data)
+	}
+	payload, err := rlp.EncodeToBytes(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.wallet.SignData(s.account, accounts.MimetypeTendermintVote, payload)
+}
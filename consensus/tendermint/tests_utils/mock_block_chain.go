@@ -1,11 +1,14 @@
 package tests_utils
 
 import (
+	"math/big"
+
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/state"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/event"
+	"github.com/lvbin2012/NeuralChain/params"
 )
 
 //MockBlockChain is mock struct for block chain
@@ -33,6 +36,10 @@ func (bc *MockBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
 	return bc.Statedb, nil
 }
 
+func (bc *MockBlockChain) MinGasPrice(*types.Header) *big.Int {
+	return big.NewInt(params.GasPriceConfig)
+}
+
 func (bc *MockBlockChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
 	return bc.ChainHeadFeed.Subscribe(ch)
 }
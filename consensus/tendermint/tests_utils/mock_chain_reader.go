@@ -16,11 +16,15 @@ import (
 type MockChainReader struct {
 	GenesisHeader *types.Header
 	*MockBlockChain
-	Address common.Address
-	Trigger *bool
+	Address     common.Address
+	Trigger     *bool
+	ChainConfig *params.ChainConfig
 }
 
 func (c *MockChainReader) Config() *params.ChainConfig {
+	if c.ChainConfig != nil {
+		return c.ChainConfig
+	}
 	return &params.ChainConfig{
 		Tendermint: &params.TendermintConfig{
 			Epoch: params.EpochDuration,
@@ -75,7 +79,7 @@ type headersMockChainReader struct {
 }
 
 func (c *headersMockChainReader) Config() *params.ChainConfig {
-	panic("implement me")
+	return params.TendermintTestChainConfig
 }
 
 func (c *headersMockChainReader) CurrentHeader() *types.Header {
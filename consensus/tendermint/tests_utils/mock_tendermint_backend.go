@@ -9,6 +9,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint/validator"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/types"
@@ -34,6 +35,8 @@ type MockBackend struct {
 	currentBlock func() *types.Block
 	// SendEventMux is used for receiving output msg from core
 	SendEventMux *event.TypeMux
+	// evidences stores evidences added by core
+	evidences map[common.Hash]*types.DuplicateVoteEvidence
 }
 
 //SentMsgEvent represents an action send to an peer
@@ -74,6 +77,22 @@ func (mb *MockBackend) VerifyProposalBlock(block *types.Block) error {
 	return nil
 }
 
+// AddEvidence implements tendermint.Backend.AddEvidence
+// It records evidences without verifying them
+func (mb *MockBackend) AddEvidence(evidence *types.DuplicateVoteEvidence) (bool, error) {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+	if mb.evidences == nil {
+		mb.evidences = make(map[common.Hash]*types.DuplicateVoteEvidence)
+	}
+	hash := evidence.Hash()
+	if _, ok := mb.evidences[hash]; ok {
+		return false, nil
+	}
+	mb.evidences[hash] = evidence
+	return true, nil
+}
+
 // EventMux implements tendermint.Backend.EventMux
 func (mb *MockBackend) EventMux() *event.TypeMux {
 	return mb.tendermintEventMux
@@ -85,6 +104,11 @@ func (mb *MockBackend) Sign(data []byte) ([]byte, error) {
 	return crypto.Sign(hashData, mb.privateKey)
 }
 
+// SignVote implements tendermint.Backend.SignVote
+func (mb *MockBackend) SignVote(req *privval.VoteRequest) ([]byte, error) {
+	return mb.Sign(req.Data)
+}
+
 // Address implements tendermint.Backend.Address
 func (mb *MockBackend) Address() common.Address {
 	return mb.address
@@ -144,6 +168,16 @@ func (mb *MockBackend) Validators(blockNumber *big.Int) tendermint.ValidatorSet
 	return validator.NewSet(mb.validators, mb.config.ProposerPolicy, int64(0))
 }
 
+// GovernedParams returns no governed parameters, the mocked backend uses its config
+func (mb *MockBackend) GovernedParams(blockNumber *big.Int) []*types.GovernedParam {
+	return nil
+}
+
+// ChainConfig returns the config of the mocked chain
+func (mb *MockBackend) ChainConfig() *params.ChainConfig {
+	return mb.chain.Config()
+}
+
 // FindExistingPeers check validator peers exist or not by address
 func (mb *MockBackend) FindExistingPeers(valSet tendermint.ValidatorSet) map[common.Address]consensus.Peer {
 	log.Error("not implemented")
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+)
+
+const (
+	// number of headers whose latest checkpoint is kept in memory
+	inMemoryCheckpoints = 4096
+)
+
+// HeaderReader retrieves the headers of a local chain
+type HeaderReader interface {
+	// GetHeader retrieves a block header from the database by hash and number.
+	GetHeader(hash common.Hash, number uint64) *types.Header
+	// GetHeaderByNumber retrieves a block header from the database by number.
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// EpochSchedule finds the epoch checkpoint headers of a Tendermint chain. The genesis header is the first checkpoint,
+// and the epoch following a checkpoint lasts the epoch length approved by the governance contract and recorded in the
+// checkpoint header, or the configured one. A checkpoint header hands off the validator set of the next epoch, and
+// only the checkpoint headers do, so they are told apart from the other headers without knowing the epoch lengths.
+type EpochSchedule struct {
+	epoch       uint64        // the configured epoch length
+	checkpoints *lru.ARCCache // the latest checkpoint among a header and its ancestors, by header hash
+
+	lock      sync.Mutex
+	canonical []uint64 // the numbers of the canonical checkpoints found so far, they are final
+}
+
+// NewEpochSchedule returns the schedule of the epochs lasting the configured epoch length unless governed otherwise
+func NewEpochSchedule(epoch uint64) *EpochSchedule {
+	checkpoints, _ := lru.NewARC(inMemoryCheckpoints)
+	return &EpochSchedule{
+		epoch:       epoch,
+		checkpoints: checkpoints,
+		canonical:   []uint64{0},
+	}
+}
+
+// IsCheckpointHeader returns whether the header is the genesis header or hands off a validator set.
+func IsCheckpointHeader(header *types.Header) bool {
+	if header.Number.Sign() == 0 {
+		return true
+	}
+	extra, err := types.ExtractTendermintExtra(header)
+	return err == nil && len(extra.ValidatorAdds) > 0
+}
+
+// EpochLength returns the number of blocks of the epoch following the checkpoint header
+func (s *EpochSchedule) EpochLength(checkpoint *types.Header) uint64 {
+	if extra, err := types.ExtractTendermintExtra(checkpoint); err == nil {
+		epoch := staking.GovernedValue(extra.GovernedParams, staking.GovernedEpoch)
+		if epoch != nil && epoch.Sign() > 0 && epoch.IsUint64() {
+			return epoch.Uint64()
+		}
+	}
+	return s.epoch
+}
+
+// NextCheckpointNumber returns the number of the checkpoint header ending the epoch following the checkpoint header
+func (s *EpochSchedule) NextCheckpointNumber(checkpoint *types.Header) uint64 {
+	return checkpoint.Number.Uint64() + s.EpochLength(checkpoint)
+}
+
+// LatestCheckpoint returns the latest checkpoint header among the header and its ancestors. The caller may
+// optionally pass in the ancestors of the header which are not in the chain yet (ascending order).
+func (s *EpochSchedule) LatestCheckpoint(chain HeaderReader, header *types.Header, parents []*types.Header) (*types.Header, error) {
+	var (
+		checkpoint *types.Header
+		walked     []common.Hash
+		err        error
+	)
+	for checkpoint == nil {
+		if IsCheckpointHeader(header) {
+			checkpoint = header
+			break
+		}
+		hash := header.Hash()
+		if cached, ok := s.checkpoints.Get(hash); ok {
+			checkpoint = cached.(*types.Header)
+			break
+		}
+		walked = append(walked, hash)
+		if header, parents, err = parentHeader(chain, header, parents); err != nil {
+			return nil, err
+		}
+	}
+	for _, hash := range walked {
+		s.checkpoints.Add(hash, checkpoint)
+	}
+	return checkpoint, nil
+}
+
+// Checkpoint returns the checkpoint header of the epoch of the header, whose validator set seals the header.
+// The genesis header is its own checkpoint.
+func (s *EpochSchedule) Checkpoint(chain HeaderReader, header *types.Header, parents []*types.Header) (*types.Header, error) {
+	if header.Number.Sign() == 0 {
+		return header, nil
+	}
+	parent, parents, err := parentHeader(chain, header, parents)
+	if err != nil {
+		return nil, err
+	}
+	return s.LatestCheckpoint(chain, parent, parents)
+}
+
+// IsCheckpoint returns whether the header ends the epoch of its checkpoint, so it must hand off a validator set
+func (s *EpochSchedule) IsCheckpoint(chain HeaderReader, header *types.Header, parents []*types.Header) (bool, error) {
+	if header.Number.Sign() == 0 {
+		return true, nil
+	}
+	checkpoint, err := s.Checkpoint(chain, header, parents)
+	if err != nil {
+		return false, err
+	}
+	return header.Number.Uint64() == s.NextCheckpointNumber(checkpoint), nil
+}
+
+// CheckpointByNumber returns the checkpoint header of the epoch of the canonical block with the number,
+// which may be the block following the head of the chain.
+func (s *EpochSchedule) CheckpointByNumber(chain HeaderReader, number uint64) (*types.Header, error) {
+	if number == 0 {
+		if genesis := chain.GetHeaderByNumber(0); genesis != nil {
+			return genesis, nil
+		}
+		return nil, consensus.ErrUnknownAncestor
+	}
+	parent := chain.GetHeaderByNumber(number - 1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	return s.LatestCheckpoint(chain, parent, nil)
+}
+
+// CanonicalCheckpoints returns the numbers of the checkpoint headers of the canonical chain up to the number,
+// in ascending order starting with the genesis. The nth checkpoint ends the nth epoch.
+// The canonical checkpoints are final, so the ones found are kept for the next calls.
+func (s *EpochSchedule) CanonicalCheckpoints(chain HeaderReader, number uint64) []uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for {
+		header := chain.GetHeaderByNumber(s.canonical[len(s.canonical)-1])
+		if header == nil {
+			break
+		}
+		next := s.NextCheckpointNumber(header)
+		if next > number || chain.GetHeaderByNumber(next) == nil {
+			break
+		}
+		s.canonical = append(s.canonical, next)
+	}
+	count := len(s.canonical)
+	for count > 0 && s.canonical[count-1] > number {
+		count--
+	}
+	return append([]uint64(nil), s.canonical[:count]...)
+}
+
+// parentHeader returns the parent of the header, taken from the end of the parents if any, and the remaining parents
+func parentHeader(chain HeaderReader, header *types.Header, parents []*types.Header) (*types.Header, []*types.Header, error) {
+	var (
+		hash   = header.ParentHash
+		number = header.Number.Uint64() - 1
+		parent *types.Header
+	)
+	if len(parents) > 0 {
+		parent, parents = parents[len(parents)-1], parents[:len(parents)-1]
+	} else {
+		parent = chain.GetHeader(hash, number)
+	}
+	if parent == nil || parent.Number.Uint64() != number || parent.Hash() != hash {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	return parent, parents, nil
+}
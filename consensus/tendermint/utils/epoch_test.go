@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// testHeaderChain is a canonical chain of headers
+type testHeaderChain []*types.Header
+
+func (c testHeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func (c testHeaderChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(c)) {
+		return c[number]
+	}
+	return nil
+}
+
+// makeEpochChain makes a chain of headers up to the number, the checkpoint headers hand off a validator set
+// and the governed epoch lengths are recorded in the checkpoints at their number
+func makeEpochChain(t *testing.T, checkpoints map[uint64]bool, governed map[uint64]int64, number uint64) testHeaderChain {
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chain testHeaderChain
+	for i := uint64(0); i <= number; i++ {
+		header := &types.Header{
+			Number: new(big.Int).SetUint64(i),
+			Extra:  append(bytes.Repeat([]byte{0x00}, types.TendermintExtraVanity), payload...),
+		}
+		if i > 0 {
+			header.ParentHash = chain[i-1].Hash()
+		}
+		if checkpoints[i] {
+			if err := WriteValSet(header, []common.Address{common.HexToAddress("0x1")}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if epoch, ok := governed[i]; ok {
+			params := []*types.GovernedParam{{Param: staking.GovernedEpoch, Value: big.NewInt(epoch)}}
+			if err := WriteGovernedParams(header, params); err != nil {
+				t.Fatal(err)
+			}
+		}
+		chain = append(chain, header)
+	}
+	return chain
+}
+
+func TestEpochSchedule(t *testing.T) {
+	var (
+		// the epochs last 4 blocks, then 6 blocks from the checkpoint 4 and 4 blocks again from the checkpoint 10
+		checkpoints = map[uint64]bool{4: true, 10: true, 14: true}
+		governed    = map[uint64]int64{4: 6, 10: 0}
+		chain       = makeEpochChain(t, checkpoints, governed, 16)
+		schedule    = NewEpochSchedule(4)
+	)
+	tests := []struct {
+		number     uint64
+		checkpoint uint64
+	}{
+		{number: 0, checkpoint: 0},
+		{number: 3, checkpoint: 0},
+		{number: 4, checkpoint: 0},
+		{number: 5, checkpoint: 4},
+		{number: 8, checkpoint: 4},
+		{number: 10, checkpoint: 4},
+		{number: 11, checkpoint: 10},
+		{number: 15, checkpoint: 14},
+	}
+	for _, tt := range tests {
+		checkpoint, err := schedule.Checkpoint(chain, chain[tt.number], nil)
+		if err != nil {
+			t.Fatalf("Checkpoint() of %d failed: %v", tt.number, err)
+		}
+		if got := checkpoint.Number.Uint64(); got != tt.checkpoint {
+			t.Errorf("Checkpoint() of %d = %d, want %d", tt.number, got, tt.checkpoint)
+		}
+		checkpoint, err = schedule.CheckpointByNumber(chain, tt.number)
+		if err != nil {
+			t.Fatalf("CheckpointByNumber() of %d failed: %v", tt.number, err)
+		}
+		if got := checkpoint.Number.Uint64(); got != tt.checkpoint {
+			t.Errorf("CheckpointByNumber() of %d = %d, want %d", tt.number, got, tt.checkpoint)
+		}
+		isCheckpoint, err := schedule.IsCheckpoint(chain, chain[tt.number], nil)
+		if err != nil {
+			t.Fatalf("IsCheckpoint() of %d failed: %v", tt.number, err)
+		}
+		if want := tt.number == 0 || checkpoints[tt.number]; isCheckpoint != want {
+			t.Errorf("IsCheckpoint() of %d = %v, want %v", tt.number, isCheckpoint, want)
+		}
+	}
+
+	// the headers not in the chain yet are found among the parents
+	var (
+		parents = chain[9:14]
+		header  = chain[14]
+	)
+	if _, err := NewEpochSchedule(4).Checkpoint(chain[:9], header, parents); err != nil {
+		t.Errorf("Checkpoint() with parents failed: %v", err)
+	}
+	if _, err := NewEpochSchedule(4).Checkpoint(chain[:9], header, parents[:len(parents)-1]); err == nil {
+		t.Error("Checkpoint() with missing parents succeeded")
+	}
+
+	if got, want := schedule.CanonicalCheckpoints(chain, 13), []uint64{0, 4, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CanonicalCheckpoints(13) = %v, want %v", got, want)
+	}
+	if got, want := schedule.CanonicalCheckpoints(chain, 16), []uint64{0, 4, 10, 14}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CanonicalCheckpoints(16) = %v, want %v", got, want)
+	}
+	if got, want := schedule.CanonicalCheckpoints(chain, 9), []uint64{0, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CanonicalCheckpoints(9) = %v, want %v", got, want)
+	}
+}
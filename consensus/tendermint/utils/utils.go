@@ -1,13 +1,15 @@
 package utils
 
 import (
-	"bytes"
 	"errors"
+	"math/big"
 
 	"golang.org/x/crypto/sha3"
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/validator"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/wire"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/rlp"
@@ -17,10 +19,6 @@ var (
 	ErrInvalidSealLength = errors.New("seal is expected to be multiplication of 65")
 )
 
-const (
-	msgCommit uint64 = iota
-)
-
 // sigHash returns the hash
 // signing. It is the hash of the entire header apart from the 65 byte signature
 // contained at the end of the extra data.
@@ -79,6 +77,99 @@ func WriteValSet(h *types.Header, validators []common.Address) error {
 	return nil
 }
 
+// WriteValSetPowers writes the extra-data field of the given header with the voting power of each validator in the val-set.
+func WriteValSetPowers(h *types.Header, votingPowers []*big.Int) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.ValidatorPowers = votingPowers
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteValSetHash writes the extra-data field of the given header with the hash of the val-set and its voting powers.
+func WriteValSetHash(h *types.Header) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.ValidatorSetHash = tendermintExtra.HashValidatorSet()
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteEvidences writes the extra-data field of the given header with the given evidences of misbehaving validators.
+func WriteEvidences(h *types.Header, evidences []*types.DuplicateVoteEvidence) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.Evidences = evidences
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteParentCommittedSeals writes the extra-data field of a block header with the committed seals of its parent.
+func WriteParentCommittedSeals(h *types.Header, committedSeals [][]byte) error {
+	for _, seal := range committedSeals {
+		if len(seal) != types.TendermintExtraSeal {
+			return ErrInvalidSealLength
+		}
+	}
+
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+
+	tendermintExtra.ParentCommittedSeal = make([][]byte, len(committedSeals))
+	copy(tendermintExtra.ParentCommittedSeal, committedSeals)
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
+// WriteGovernedParams writes the extra-data field of a checkpoint header with the parameters approved by the governance contract.
+func WriteGovernedParams(h *types.Header, params []*types.GovernedParam) error {
+	tendermintExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	tendermintExtra.GovernedParams = params
+
+	payload, err := rlp.EncodeToBytes(&tendermintExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.TendermintExtraVanity], payload...)
+	return nil
+}
+
 // WriteCommittedSeals writes the extra-data field of a block header with given committed seals.
 func WriteCommittedSeals(h *types.Header, committedSeals [][]byte) error {
 	if len(committedSeals) == 0 {
@@ -122,18 +213,7 @@ func GetSignatureAddress(data []byte, sig []byte) (common.Address, error) {
 
 // PrepareCommittedSeal returns a committed seal for the given hash
 func PrepareCommittedSeal(hash common.Hash) []byte {
-	var buf bytes.Buffer
-	buf.Write(hash.Bytes())
-	buf.Write([]byte{byte(msgCommit)})
-	return buf.Bytes()
-}
-
-// GetCheckpointNumber returns check-point block where header contains valset of current epoch
-func GetCheckpointNumber(epochDuration uint64, blockNumber uint64) uint64 {
-	if blockNumber == 0 || blockNumber < epochDuration {
-		return 0
-	}
-	return epochDuration * ((blockNumber - 1) / epochDuration)
+	return wire.CommittedSealData(hash)
 }
 
 // GetValSetAddresses returns the address of validators from the extra-data field.
@@ -155,3 +235,76 @@ func GetValSetAddresses(h *types.Header) ([]common.Address, error) {
 
 	return validators, nil
 }
+
+// GetValSet returns the validator set from the extra-data field of a checkpoint header.
+// The validators are weighted by the voting powers if the header records them, otherwise each validator has one vote.
+func GetValSet(h *types.Header, policy tendermint.ProposerPolicy, height int64) (tendermint.ValidatorSet, error) {
+	validators, err := GetValSetAddresses(h)
+	if err != nil {
+		return nil, err
+	}
+	tdmExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return nil, err
+	}
+	if len(tdmExtra.ValidatorPowers) == 0 {
+		return validator.NewSet(validators, policy, height), nil
+	}
+	return validator.NewWeightedSet(validators, tdmExtra.ValidatorPowers, policy, height)
+}
+
+// VerifyValSetHash checks that the header records the hash of the val-set it hands off if the ValidatorSetHash fork
+// is active, and no hash otherwise.
+func VerifyValSetHash(h *types.Header, forked bool) error {
+	tdmExtra, err := types.ExtractTendermintExtra(h)
+	if err != nil {
+		return err
+	}
+	expected := common.Hash{}
+	if forked {
+		expected = tdmExtra.HashValidatorSet()
+	}
+	if tdmExtra.ValidatorSetHash != expected {
+		return tendermint.ErrInvalidValSetHash
+	}
+	return nil
+}
+
+// VerifyCommittedSeals checks whether the committed seals of the header are signed by distinct validators of the set
+// holding at least the quorum voting power, which makes the header final.
+func VerifyCommittedSeals(header *types.Header, valSet tendermint.ValidatorSet) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return err
+	}
+	// The length of Committed seals should be larger than 0
+	if len(extra.CommittedSeal) == 0 {
+		return tendermint.ErrEmptyCommittedSeals
+	}
+
+	vals := valSet.Copy()
+	// Check whether the committed seals are generated by parent's validators
+	sealsPower := new(big.Int)
+	proposalSeal := PrepareCommittedSeal(header.Hash())
+	// 1. Get committed seals from current header
+	for _, seal := range extra.CommittedSeal {
+		// 2. Get the original address by seal and parent block hash
+		addr, err := GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return tendermint.ErrInvalidSignature
+		}
+		// Every validator can have only one seal. If more than one seals are signed by a
+		// validator, the validator cannot be found and errInvalidCommittedSeals is returned.
+		_, val := vals.GetByAddress(addr)
+		if val == nil || !vals.RemoveValidator(addr) {
+			return tendermint.ErrInvalidCommittedSeals
+		}
+		sealsPower.Add(sealsPower, val.VotingPower())
+	}
+
+	// The voting power of valid seals should be larger or equal than the quorum (more than 2/3 of total voting power)
+	if sealsPower.Cmp(valSet.QuorumVotingPower()) < 0 {
+		return tendermint.ErrInvalidCommittedSeals
+	}
+	return nil
+}
@@ -1,47 +1,49 @@
 package utils
 
-import "testing"
+import (
+	"bytes"
+	"math/big"
+	"testing"
 
-func TestGetCheckpointNumber(t *testing.T) {
-	type args struct {
-		epochDuration uint64
-		blockNumber   uint64
-	}
-	tests := []struct {
-		name string
-		args args
-		want uint64
-	}{
-		{
-			name: "the block's number is lower than epoch duration",
-			args: args{
-				epochDuration: 1000,
-				blockNumber:   400,
-			},
-			want: 0,
-		},
-		{
-			name: "the block's number is greater than epoch duration",
-			args: args{
-				epochDuration: 1000,
-				blockNumber:   1999,
-			},
-			want: 1000,
-		},
-		{
-			name: "the block's number is a checkpoint",
-			args: args{
-				epochDuration: 1000,
-				blockNumber:   2000,
-			},
-			want: 1000,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := GetCheckpointNumber(tt.args.epochDuration, tt.args.blockNumber); got != tt.want {
-				t.Errorf("GetCheckpointNumber() = %v, want %v", got, tt.want)
-			}
-		})
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+func TestGetValSet(t *testing.T) {
+	var (
+		validators = []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}
+		powers     = []*big.Int{big.NewInt(3), big.NewInt(1)}
+	)
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &types.Header{Extra: append(bytes.Repeat([]byte{0x00}, types.TendermintExtraVanity), payload...)}
+	if err := WriteValSet(header, validators); err != nil {
+		t.Fatal(err)
+	}
+
+	valSet, err := GetValSet(header, tendermint.RoundRobin, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valSet.TotalVotingPower(); got.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("TotalVotingPower() of unweighted set = %v, want 2", got)
+	}
+
+	if err := WriteValSetPowers(header, powers); err != nil {
+		t.Fatal(err)
+	}
+	valSet, err = GetValSet(header, tendermint.RoundRobin, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valSet.TotalVotingPower(); got.Cmp(big.NewInt(4)) != 0 {
+		t.Errorf("TotalVotingPower() of weighted set = %v, want 4", got)
+	}
+	if _, val := valSet.GetByAddress(validators[0]); val == nil || val.VotingPower().Cmp(powers[0]) != 0 {
+		t.Errorf("VotingPower() of %s = %v, want %v", validators[0].Hex(), val, powers[0])
 	}
 }
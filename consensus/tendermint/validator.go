@@ -11,6 +11,9 @@ type Validator interface {
 	// Address returns address
 	Address() common.Address
 
+	// VotingPower returns the weight of the validator's votes
+	VotingPower() *big.Int
+
 	// String representation of Validator
 	String() string
 }
@@ -55,8 +58,12 @@ type ValidatorSet interface {
 	RemoveValidator(address common.Address) bool
 	// Copy validator set
 	Copy() ValidatorSet
-	// Get the minimum number of votes for a polka
+	// Get the minimum number of votes for a polka, counting one vote per validator
 	MinMajority() int
+	// TotalVotingPower returns the sum of the voting power of all validators
+	TotalVotingPower() *big.Int
+	// QuorumVotingPower returns the minimum voting power for a polka, i.e, more than 2/3 of the total voting power
+	QuorumVotingPower() *big.Int
 	// Get the minimum number of peers to archive consensus
 	MinPeers() int
 	// Get the maximum number of faulty nodes
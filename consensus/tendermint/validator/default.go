@@ -2,6 +2,7 @@ package validator
 
 import (
 	"math"
+	"math/big"
 	"sort"
 	"sync"
 
@@ -10,7 +11,8 @@ import (
 )
 
 type defaultValidator struct {
-	address common.Address
+	address     common.Address
+	votingPower *big.Int
 }
 
 // Address will return address of defaultValidator
@@ -18,6 +20,11 @@ func (val *defaultValidator) Address() common.Address {
 	return val.address
 }
 
+// VotingPower will return a copy of the voting power of defaultValidator
+func (val *defaultValidator) VotingPower() *big.Int {
+	return new(big.Int).Set(val.votingPower)
+}
+
 // String will parse address of defaultValidator to string and return it
 func (val *defaultValidator) String() string {
 	return val.Address().String()
@@ -36,32 +43,33 @@ type defaultSet struct {
 	validatorMu sync.RWMutex
 	selector    tendermint.ProposalSelector
 
-	height int64 // current height when backend init validator set
+	height      int64 // current height when backend init validator set
+	proposerSeq int64 // number of proposer selections since height 1, used by ProportionalToStake policy
+	schedule    []int // proposer sequence of the ProportionalToStake policy, computed once for the validators
 }
 
-func newDefaultSet(addrs []common.Address, policy tendermint.ProposerPolicy, height int64) *defaultSet {
+// proportionalPrecision is the sum of the normalized weights used by the ProportionalToStake policy,
+// so a proposer sequence repeats every proportionalPrecision selections
+const proportionalPrecision = 10000
+
+// newDefaultSet creates the validator set, every validator has the same voting power if votingPowers is nil
+func newDefaultSet(addrs []common.Address, votingPowers []*big.Int, policy tendermint.ProposerPolicy, height int64) *defaultSet {
 	valSet := &defaultSet{}
 
 	valSet.policy = policy
 	// init validators
 	valSet.validators = make([]tendermint.Validator, len(addrs))
 	for i, addr := range addrs {
-		valSet.validators[i] = New(addr)
+		if votingPowers != nil {
+			valSet.validators[i] = NewWithVotingPower(addr, votingPowers[i])
+		} else {
+			valSet.validators[i] = New(addr)
+		}
 	}
 
 	// sort validator
 	sort.Sort(valSet.validators)
 
-	// init proposer
-	if valSet.Size() > 0 {
-		// this ensure first validator in array can propose block height 1
-		shiftHeight := height
-		if shiftHeight > 0 {
-			shiftHeight = shiftHeight - 1
-		}
-		index := shiftHeight % int64(valSet.Size())
-		valSet.proposer = valSet.GetByIndex(index)
-	}
 	if policy == tendermint.Sticky {
 		valSet.selector = stickyProposer
 	} else {
@@ -69,10 +77,29 @@ func newDefaultSet(addrs []common.Address, policy tendermint.ProposerPolicy, hei
 	}
 
 	valSet.height = height
+	valSet.initProposer()
 
 	return valSet
 }
 
+// initProposer sets the proposer of the first round at the height of the validator set
+func (valSet *defaultSet) initProposer() {
+	if len(valSet.validators) == 0 {
+		return
+	}
+	// this ensure first validator in array can propose block height 1
+	shiftHeight := valSet.height
+	if shiftHeight > 0 {
+		shiftHeight = shiftHeight - 1
+	}
+	index := shiftHeight % int64(len(valSet.validators))
+	valSet.proposer = valSet.validators[index]
+	valSet.proposerSeq = shiftHeight
+	if valSet.policy == tendermint.ProportionalToStake {
+		valSet.proposer = valSet.proportionalProposer()
+	}
+}
+
 // Size will return the length of validators in defaultSet
 func (valSet *defaultSet) Size() int {
 	valSet.validatorMu.RLock()
@@ -149,7 +176,80 @@ func stickyProposer(valSet tendermint.ValidatorSet, proposer common.Address, rou
 	return valSet.GetByIndex(pick)
 }
 
-// AddValidator will add a validator to validators collection
+// proportionalWeights normalizes the voting powers to integer weights summing up to about proportionalPrecision.
+// A validator with a positive voting power always has a weight of at least 1.
+func proportionalWeights(validators tendermint.Validators) []int64 {
+	total := new(big.Int)
+	for _, val := range validators {
+		total.Add(total, val.VotingPower())
+	}
+	weights := make([]int64, len(validators))
+	if total.Sign() <= 0 {
+		return weights
+	}
+	for i, val := range validators {
+		power := val.VotingPower()
+		if power.Sign() <= 0 {
+			continue
+		}
+		weight := new(big.Int).Mul(power, big.NewInt(proportionalPrecision))
+		weights[i] = weight.Div(weight, total).Int64()
+		if weights[i] == 0 {
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
+// proportionalSchedule returns the indexes of the validators picked by a sequence of selections using the weighted
+// round-robin of Tendermint: every selection increases the priority of each validator by its weight, picks the
+// validator with the highest priority and decreases its priority by the sum of the weights. Each validator is picked
+// as many times as its weight in a sequence of sum of the weights selections, after which the priorities are back
+// to zero and the sequence repeats. It returns nil if no validator has a weight.
+func proportionalSchedule(validators tendermint.Validators) []int {
+	weights := proportionalWeights(validators)
+	var period int64
+	for _, weight := range weights {
+		period += weight
+	}
+	if period == 0 {
+		return nil
+	}
+
+	schedule := make([]int, period)
+	priorities := make([]int64, len(validators))
+	for step := range schedule {
+		pick := 0
+		for i, weight := range weights {
+			priorities[i] += weight
+			// ties are broken by the lowest index, validators are sorted by address
+			if priorities[i] > priorities[pick] {
+				pick = i
+			}
+		}
+		priorities[pick] -= period
+		schedule[step] = pick
+	}
+	return schedule
+}
+
+// proportionalProposer returns the proposer of the proposerSeq-th selection of the proportional schedule of the
+// validators, so the proposer only depends on the validators and the sequence. The schedule is computed once for
+// the validators of the set, the caller must hold validatorMu unless the set is not shared yet.
+func (valSet *defaultSet) proportionalProposer() tendermint.Validator {
+	if len(valSet.validators) == 0 {
+		return nil
+	}
+	if valSet.schedule == nil {
+		valSet.schedule = proportionalSchedule(valSet.validators)
+	}
+	if len(valSet.schedule) == 0 {
+		return valSet.validators[valSet.proposerSeq%int64(len(valSet.validators))]
+	}
+	return valSet.validators[valSet.schedule[valSet.proposerSeq%int64(len(valSet.schedule))]]
+}
+
+// AddValidator will add a validator with a voting power of 1 to validators collection
 func (valSet *defaultSet) AddValidator(address common.Address) bool {
 	valSet.validatorMu.Lock()
 	defer valSet.validatorMu.Unlock()
@@ -159,6 +259,7 @@ func (valSet *defaultSet) AddValidator(address common.Address) bool {
 		}
 	}
 	valSet.validators = append(valSet.validators, New(address))
+	valSet.schedule = nil
 	return true
 }
 
@@ -170,6 +271,7 @@ func (valSet *defaultSet) RemoveValidator(address common.Address) bool {
 	for i, v := range valSet.validators {
 		if v.Address() == address {
 			valSet.validators = append(valSet.validators[:i], valSet.validators[i+1:]...)
+			valSet.schedule = nil
 			return true
 		}
 	}
@@ -181,11 +283,24 @@ func (valSet *defaultSet) Copy() tendermint.ValidatorSet {
 	valSet.validatorMu.RLock()
 	defer valSet.validatorMu.RUnlock()
 
-	addresses := make([]common.Address, 0, len(valSet.validators))
-	for _, v := range valSet.validators {
-		addresses = append(addresses, v.Address())
+	newValSet := &defaultSet{
+		validators: make([]tendermint.Validator, len(valSet.validators)),
+		policy:     valSet.policy,
+		selector:   valSet.selector,
+		height:     valSet.height,
+	}
+	for i, v := range valSet.validators {
+		newValSet.validators[i] = NewWithVotingPower(v.Address(), v.VotingPower())
 	}
-	return NewSet(addresses, valSet.policy, valSet.height)
+	sort.Sort(newValSet.validators)
+	// the schedule is never modified, the copy shares it unless the validators changed since it was computed
+	newValSet.schedule = valSet.schedule
+	newValSet.initProposer()
+	if valSet.policy == tendermint.ProportionalToStake {
+		newValSet.proposerSeq = valSet.proposerSeq
+		newValSet.proposer = newValSet.proportionalProposer()
+	}
+	return newValSet
 }
 
 // Get the minimum number of peers to archive consensus
@@ -198,6 +313,23 @@ func (valSet *defaultSet) MinMajority() int {
 	return valSet.Size() - valSet.F()
 }
 
+// TotalVotingPower returns the sum of the voting power of all validators
+func (valSet *defaultSet) TotalVotingPower() *big.Int {
+	total := new(big.Int)
+	for _, val := range valSet.List() {
+		total.Add(total, val.VotingPower())
+	}
+	return total
+}
+
+// QuorumVotingPower returns the minimum voting power for a polka, which is floor(2/3 * total) + 1.
+// If all validators have a voting power of 1, it is equal to MinMajority
+func (valSet *defaultSet) QuorumVotingPower() *big.Int {
+	quorum := new(big.Int).Mul(valSet.TotalVotingPower(), big.NewInt(2))
+	quorum.Div(quorum, big.NewInt(3))
+	return quorum.Add(quorum, common.Big1)
+}
+
 // F get the maximum number of faulty nodes
 func (valSet *defaultSet) F() int { return int(math.Ceil(float64(valSet.Size())/3)) - 1 }
 
@@ -231,7 +363,15 @@ func (valSet *defaultSet) GetNeighbors(addr common.Address) map[common.Address]b
 
 //CalcProposer implement valSet.CalcProposer. Based on the proposer selection scheme,
 //it will set valSet.proposer to the address of the pre-determined round.
+//With ProportionalToStake policy, the proposer is selected by the number of rounds since height 1 instead of lastProposer.
 func (valSet *defaultSet) CalcProposer(lastProposer common.Address, roundDiff int64) {
+	if valSet.policy == tendermint.ProportionalToStake {
+		valSet.validatorMu.Lock()
+		defer valSet.validatorMu.Unlock()
+		valSet.proposerSeq += roundDiff
+		valSet.proposer = valSet.proportionalProposer()
+		return
+	}
 	valSet.validatorMu.RLock()
 	defer valSet.validatorMu.RUnlock()
 	valSet.proposer = valSet.selector(valSet, lastProposer, roundDiff)
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"log"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
@@ -38,7 +39,7 @@ func TestDefaultSet_GetNeighbor(t *testing.T) {
 			c,
 			d,
 		}
-		valSet      = newDefaultSet(addresses, tendermint.RoundRobin, 0)
+		valSet      = newDefaultSet(addresses, nil, tendermint.RoundRobin, 0)
 		neighbors   = valSet.GetNeighbors(addresses[0])
 		expectedLen = 2
 	)
@@ -61,9 +62,145 @@ func testMajorityFormulation(t *testing.T) {
 		}
 		valSet := NewSet(addresses, tendermint.RoundRobin, int64(0))
 		require.Equal(t, majority, valSet.MinMajority())
+		require.Equal(t, int64(majority), valSet.QuorumVotingPower().Int64())
 	}
 }
 
+func TestWeightedValidatorSet(t *testing.T) {
+	var (
+		a, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf")
+		b, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjezqzTxcW")
+		c, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjezwhg9vh")
+		addresses = []common.Address{a, b, c}
+		powers    = []*big.Int{big.NewInt(70), big.NewInt(20), big.NewInt(10)}
+	)
+	_, err := NewWeightedSet(addresses, powers[:2], tendermint.RoundRobin, 0)
+	require.Equal(t, ErrVotingPowersMismatch, err)
+
+	valSet, err := NewWeightedSet(addresses, powers, tendermint.RoundRobin, 0)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), valSet.TotalVotingPower())
+	// more than 2/3 of the voting power is required, head count is irrelevant
+	assert.Equal(t, big.NewInt(67), valSet.QuorumVotingPower())
+	for i, addr := range addresses {
+		_, val := valSet.GetByAddress(addr)
+		require.NotNil(t, val)
+		assert.Equal(t, powers[i], val.VotingPower())
+	}
+
+	copied := valSet.Copy()
+	assert.Equal(t, valSet.TotalVotingPower(), copied.TotalVotingPower())
+	require.True(t, copied.RemoveValidator(a))
+	assert.Equal(t, big.NewInt(30), copied.TotalVotingPower())
+	assert.Equal(t, big.NewInt(100), valSet.TotalVotingPower())
+}
+
+func TestProportionalToStakeProposer(t *testing.T) {
+	var (
+		a, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf")
+		b, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjezqzTxcW")
+		c, _      = common.NeutAddressStringToAddressCheck("NKuyBkoGdZZSLyPbJEetheRhMjezwhg9vh")
+		addresses = []common.Address{a, b, c}
+		powers    = []*big.Int{big.NewInt(70), big.NewInt(20), big.NewInt(10)}
+	)
+	proposerAt := func(height int64, round int64) common.Address {
+		valSet, err := NewWeightedSet(addresses, powers, tendermint.ProportionalToStake, height)
+		require.NoError(t, err)
+		if round > 0 {
+			valSet.CalcProposer(valSet.GetProposer().Address(), round)
+		}
+		return valSet.GetProposer().Address()
+	}
+
+	t.Run("fairness", func(t *testing.T) {
+		counts := make(map[common.Address]int)
+		for height := int64(1); height <= proportionalPrecision; height++ {
+			counts[proposerAt(height, 0)]++
+		}
+		// over a whole sequence, every validator proposes exactly in proportion to its stake
+		assert.Equal(t, 7000, counts[a])
+		assert.Equal(t, 2000, counts[b])
+		assert.Equal(t, 1000, counts[c])
+
+		// proposers are interleaved, so the proportion holds for short windows as well
+		counts = make(map[common.Address]int)
+		for height := int64(1); height <= 100; height++ {
+			counts[proposerAt(height, 0)]++
+		}
+		assert.InDelta(t, 70, counts[a], 1)
+		assert.InDelta(t, 20, counts[b], 1)
+		assert.InDelta(t, 10, counts[c], 1)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		valSet, err := NewWeightedSet(addresses, powers, tendermint.ProportionalToStake, 5)
+		require.NoError(t, err)
+		for round := int64(1); round < 20; round++ {
+			// moving to the next round selects the same proposer as a fresh set at the next height
+			valSet.CalcProposer(valSet.GetProposer().Address(), 1)
+			assert.Equal(t, proposerAt(5+round, 0), valSet.GetProposer().Address())
+			assert.Equal(t, proposerAt(5, round), valSet.GetProposer().Address())
+		}
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		valSet, err := NewWeightedSet(addresses, powers, tendermint.ProportionalToStake, 42)
+		require.NoError(t, err)
+		valSet.CalcProposer(valSet.GetProposer().Address(), 3)
+		copied := valSet.Copy()
+		assert.Equal(t, valSet.GetProposer().Address(), copied.GetProposer().Address())
+		copied.CalcProposer(copied.GetProposer().Address(), 2)
+		assert.Equal(t, proposerAt(42, 5), copied.GetProposer().Address())
+	})
+
+	t.Run("cached schedule", func(t *testing.T) {
+		valSet, err := NewWeightedSet(addresses, powers, tendermint.ProportionalToStake, 1)
+		require.NoError(t, err)
+		// the sequence is computed once for the validators of the set, not at every selection
+		set := valSet.(*defaultSet)
+		require.Len(t, set.schedule, proportionalPrecision)
+		valSet.CalcProposer(valSet.GetProposer().Address(), 3)
+		assert.True(t, &set.schedule[0] == &valSet.Copy().(*defaultSet).schedule[0])
+
+		// a change of the validators invalidates it
+		copied := valSet.Copy()
+		require.True(t, copied.RemoveValidator(c))
+		assert.Nil(t, copied.(*defaultSet).schedule)
+		copied.CalcProposer(copied.GetProposer().Address(), 1)
+		assert.NotEmpty(t, copied.(*defaultSet).schedule)
+		assert.Len(t, set.schedule, proportionalPrecision)
+	})
+
+	t.Run("independent sets", func(t *testing.T) {
+		reordered := []common.Address{c, a, b}
+		reorderedPowers := []*big.Int{big.NewInt(10), big.NewInt(70), big.NewInt(20)}
+		for height := int64(1); height <= 50; height++ {
+			for round := int64(0); round < 5; round++ {
+				first, err := NewWeightedSet(addresses, powers, tendermint.ProportionalToStake, height)
+				require.NoError(t, err)
+				second, err := NewWeightedSet(reordered, reorderedPowers, tendermint.ProportionalToStake, height)
+				require.NoError(t, err)
+				// a set moving through the rounds does not affect the other one
+				first.CalcProposer(first.GetProposer().Address(), round)
+				first.CalcProposer(first.GetProposer().Address(), 1)
+				second.CalcProposer(second.GetProposer().Address(), round+1)
+				assert.Equal(t, first.GetProposer().Address(), second.GetProposer().Address())
+				assert.Equal(t, proposerAt(height, round+1), second.GetProposer().Address())
+			}
+		}
+	})
+
+	t.Run("equal voting power", func(t *testing.T) {
+		proportional := NewSet(addresses, tendermint.ProportionalToStake, 1)
+		roundRobin := NewSet(addresses, tendermint.RoundRobin, 1)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, roundRobin.GetProposer().Address(), proportional.GetProposer().Address())
+			roundRobin.CalcProposer(roundRobin.GetProposer().Address(), 1)
+			proportional.CalcProposer(proportional.GetProposer().Address(), 1)
+		}
+	})
+}
+
 func testNewValidatorSet(t *testing.T) {
 	const ValCnt = 3
 
@@ -110,7 +247,7 @@ func testNormalValSet(t *testing.T) {
 	val1 := New(addr1)
 	val2 := New(addr2)
 
-	valSet := newDefaultSet([]common.Address{addr1, addr2}, tendermint.RoundRobin, int64(0))
+	valSet := newDefaultSet([]common.Address{addr1, addr2}, nil, tendermint.RoundRobin, int64(0))
 	assert.NotNil(t, valSet, "the format of validator set is invalid")
 
 	// check size
@@ -136,7 +273,7 @@ func testNormalValSet(t *testing.T) {
 	}
 
 	blockHeight := 1
-	valSetWilHeight := newDefaultSet([]common.Address{addr1, addr2}, tendermint.RoundRobin, int64(blockHeight))
+	valSetWilHeight := newDefaultSet([]common.Address{addr1, addr2}, nil, tendermint.RoundRobin, int64(blockHeight))
 	assert.NotNil(t, valSet, "the format of validator set is invalid")
 	// test get by first index
 	if val := valSetWilHeight.GetProposer(); !reflect.DeepEqual(val, val1) {
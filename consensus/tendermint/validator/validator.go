@@ -1,22 +1,46 @@
 package validator
 
 import (
+	"errors"
+	"math/big"
 	"reflect"
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 )
 
-// New will create new validator
+const defaultVotingPower = 1
+
+var (
+	// ErrVotingPowersMismatch is returned when the number of voting powers is not equal to the number of validators
+	ErrVotingPowersMismatch = errors.New("number of voting powers is not equal to number of validators")
+)
+
+// New will create new validator with a voting power of 1
 func New(addr common.Address) tendermint.Validator {
+	return NewWithVotingPower(addr, big.NewInt(defaultVotingPower))
+}
+
+// NewWithVotingPower will create new validator with the given voting power
+func NewWithVotingPower(addr common.Address, votingPower *big.Int) tendermint.Validator {
 	return &defaultValidator{
-		address: addr,
+		address:     addr,
+		votingPower: new(big.Int).Set(votingPower),
 	}
 }
 
-// NewSet will create new validator set by address list & policy
+// NewSet will create new validator set by address list & policy, every validator has the same voting power
 func NewSet(addrs []common.Address, policy tendermint.ProposerPolicy, height int64) tendermint.ValidatorSet {
-	return newDefaultSet(addrs, policy, height)
+	return newDefaultSet(addrs, nil, policy, height)
+}
+
+// NewWeightedSet will create new validator set by address list, voting power list & policy.
+// votingPowers[i] is the voting power of addrs[i]
+func NewWeightedSet(addrs []common.Address, votingPowers []*big.Int, policy tendermint.ProposerPolicy, height int64) (tendermint.ValidatorSet, error) {
+	if len(addrs) != len(votingPowers) {
+		return nil, ErrVotingPowersMismatch
+	}
+	return newDefaultSet(addrs, votingPowers, policy, height), nil
 }
 
 // IsProposer will be checking whether the validator with given address is a proposer
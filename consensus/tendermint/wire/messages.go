@@ -0,0 +1,85 @@
+// Package wire defines the consensus messages exchanged by the Tendermint validators and their encoding.
+// They are shared by the Tendermint core, which sends them, and by the signers, which decode what they are
+// requested to sign.
+package wire
+
+import (
+	"io"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// Codes of the consensus messages
+const (
+	MsgPropose uint64 = iota
+	MsgPrevote
+	MsgPrecommit
+	MsgCatchUpRequest
+	MsgCatchUpReply
+	MsgEvidence
+)
+
+// msgCommit is the code appended to the block hash signed by a committed seal
+const msgCommit byte = 0
+
+// Message is used to store consensus information between steps
+type Message struct {
+	Code      uint64
+	Msg       []byte
+	Address   common.Address
+	Signature []byte
+}
+
+// EncodeRLP serializes m into the NeuralChain RLP format.
+func (m *Message) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, []interface{}{m.Code, m.Msg, m.Address, m.Signature})
+}
+
+// DecodeRLP implements rlp.Decoder, and load the consensus fields from a RLP stream.
+func (m *Message) DecodeRLP(s *rlp.Stream) error {
+	type msg Message
+	var decodedMsg msg
+	if err := s.Decode(&decodedMsg); err != nil {
+		return err
+	}
+	m.Code, m.Msg, m.Address, m.Signature = decodedMsg.Code, decodedMsg.Msg, decodedMsg.Address, decodedMsg.Signature
+	return nil
+}
+
+// PayLoadWithoutSignature returns the encoded message without its signature, which is the data signed by the sender
+func (m *Message) PayLoadWithoutSignature() ([]byte, error) {
+	return rlp.EncodeToBytes(&Message{
+		Code:      m.Code,
+		Address:   m.Address,
+		Msg:       m.Msg,
+		Signature: []byte{},
+	})
+}
+
+// GetAddressFromSignature gets the signer address from the signature
+func (m *Message) GetAddressFromSignature() (common.Address, error) {
+	payLoad, err := m.PayLoadWithoutSignature()
+	if err != nil {
+		return common.Address{}, err
+	}
+	hashData := crypto.Keccak256(payLoad)
+
+	// 2. Recover public key
+	pubkey, err := crypto.SigToPub(hashData, m.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// CommittedSealData returns the data signed by the committed seals of the block hash
+func CommittedSealData(hash common.Hash) []byte {
+	return append(hash.Bytes(), msgCommit)
+}
+
+// IsCommittedSealData returns whether the data is the one signed by a committed seal
+func IsCommittedSealData(data []byte) bool {
+	return len(data) == common.HashLength+1 && data[common.HashLength] == msgCommit
+}
@@ -65,10 +65,8 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
 
-	for _, tx := range block.Transactions() {
-		if tx.GasPrice().Cmp(v.config.GasPrice) != 0 {
-			return fmt.Errorf("transaction gas price and chainConfig gas price mismatch: has %s want %s", tx.GasPrice(), v.config.GasPrice)
-		}
+	if err := v.validateGasPrices(block); err != nil {
+		return err
 	}
 	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
 		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
@@ -79,6 +77,31 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	return nil
 }
 
+// validateGasPrices checks the gas price of the transactions of the block. Before the fee market,
+// it must be the minimum gas price of the block, afterwards it must cover the base fee of the block.
+func (v *BlockValidator) validateGasPrices(block *types.Block) error {
+	parent := v.bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	gasPrice := v.bc.MinGasPrice(parent)
+	if !v.config.IsFeeMarket(block.Number()) {
+		for _, tx := range block.Transactions() {
+			if tx.GasPrice().Cmp(gasPrice) != 0 {
+				return fmt.Errorf("transaction gas price and chainConfig gas price mismatch: has %s want %s", tx.GasPrice(), gasPrice)
+			}
+		}
+		return nil
+	}
+	baseFee := CalcBaseFee(v.config, gasPrice, parent)
+	for _, tx := range block.Transactions() {
+		if tx.GasPrice().Cmp(baseFee) < 0 {
+			return fmt.Errorf("%v: has %s want at least %s", ErrGasPriceBelowBaseFee, tx.GasPrice(), baseFee)
+		}
+	}
+	return nil
+}
+
 // ValidateState validates the various changes that happen after a state
 // transition, such as amount of used gas, the receipt roots and the state root
 // itself. ValidateState returns a database batch if the validation was a success
@@ -483,6 +483,12 @@ func (bc *BlockChain) CurrentFastBlock() *types.Block {
 	return bc.currentFastBlock.Load().(*types.Block)
 }
 
+// CurrentFinalizedBlock retrieves the latest block of the canonical chain which
+// can no longer be reverted according to the consensus engine.
+func (bc *BlockChain) CurrentFinalizedBlock() *types.Block {
+	return bc.GetBlockByNumber(consensus.FinalizedNumber(bc.engine, bc.CurrentBlock().Header()))
+}
+
 // Validator returns the current validator.
 func (bc *BlockChain) Validator() Validator {
 	return bc.validator
@@ -811,12 +817,12 @@ func (bc *BlockChain) Stop() {
 	//  - HEAD:     			So we don't need to reprocess any blocks in the general case
 	//  - HEAD-1:   			So we don't do large reorgs if our HEAD becomes an uncle
 	//  - HEAD-127: 			So we have a hard limit on the number of blocks reexecuted
-	//  - HEAD - HEAD % epoch:	So we have transition block to get state when finalize next transition block
+	//  - latest checkpoint:	So we have transition block to get state when finalize next transition block
 	if !bc.cacheConfig.TrieDirtyDisabled {
 		triedb := bc.stateCache.TrieDB()
 		var remainBlockOffset = []uint64{0, 1, TriesInMemory - 1}
-		if bc.chainConfig.Tendermint != nil {
-			remainBlockOffset = append(remainBlockOffset, bc.CurrentBlock().NumberU64()%bc.chainConfig.Tendermint.Epoch)
+		if checkpoint := bc.LatestCheckpoint(bc.CurrentHeader()); checkpoint != nil {
+			remainBlockOffset = append(remainBlockOffset, bc.CurrentBlock().NumberU64()-checkpoint.Number.Uint64())
 		}
 		for _, offset := range remainBlockOffset {
 			if number := bc.CurrentBlock().NumberU64(); number > offset {
@@ -1248,11 +1254,11 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		// Full but not archive node, do proper garbage collection
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		priority := -int64(block.NumberU64())
-		if bc.chainConfig.Tendermint != nil {
-			if block.NumberU64()%bc.chainConfig.Tendermint.Epoch == 0 {
+		if epochs, ok := bc.engine.(consensus.Epochs); ok {
+			if checkpoint := bc.LatestCheckpoint(block.Header()); checkpoint != nil && checkpoint.Hash() == block.Hash() {
 				// TODO: add a Flag transition-Block-preserve
 				// block number when transition block is dereference = block + 2 * epoch + TriesInMemory
-				priority -= int64(bc.chainConfig.Tendermint.Epoch) * 2
+				priority -= int64(epochs.EpochLength(checkpoint)) * 2
 				log.Info("transition block should be dereference with higher priority", "block", block.NumberU64(), "priority", priority)
 			}
 		}
@@ -1304,6 +1310,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Write other block data using a batch.
 	batch := bc.db.NewBatch()
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	if writer, ok := bc.engine.(consensus.BlockDataWriter); ok {
+		writer.WriteBlockData(batch, block)
+	}
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -2115,6 +2124,27 @@ func (bc *BlockChain) GetHeaderByNumber(number uint64) *types.Header {
 	return bc.hc.GetHeaderByNumber(number)
 }
 
+// LatestCheckpoint retrieves the latest epoch checkpoint header among the header and its ancestors,
+// nil if the consensus engine has no epochs.
+func (bc *BlockChain) LatestCheckpoint(header *types.Header) *types.Header {
+	epochs, ok := bc.engine.(consensus.Epochs)
+	if !ok {
+		return nil
+	}
+	checkpoint, err := epochs.LatestCheckpoint(bc, header)
+	if err != nil {
+		log.Error("Failed to find the epoch checkpoint", "number", header.Number, "hash", header.Hash(), "err", err)
+		return nil
+	}
+	return checkpoint
+}
+
+// MinGasPrice retrieves the minimum gas price of the block following the parent header,
+// which is governed by the consensus engine or the gas price of the chain config.
+func (bc *BlockChain) MinGasPrice(parent *types.Header) *big.Int {
+	return consensus.MinGasPrice(bc.engine, bc, parent)
+}
+
 // Config retrieves the blockchain's chain configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -104,7 +104,8 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 		b.SetCoinbase(common.Address{})
 	}
 	b.statedb.Prepare(tx.Hash(), common.Hash{}, len(b.txs))
-	receipt, _, err := ApplyTransaction(b.config, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
+	baseFee := CalcBaseFee(b.config, b.config.GasPrice, b.parent.Header())
+	receipt, _, err := applyTransaction(b.config, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, baseFee, tx, &b.header.GasUsed, vm.Config{})
 	if err != nil {
 		panic(err)
 	}
@@ -315,6 +316,16 @@ func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header {
 	}
 	return nil
 }
-func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
-func (cr *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
-func (cr *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
+func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header { return nil }
+func (cr *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if blk := cr.GetBlock(hash, number); blk != nil {
+		return blk.Header()
+	}
+	return nil
+}
+func (cr *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if blk, ok := cr.blocksByNumber[number]; ok && blk.Hash() == hash {
+		return blk
+	}
+	return nil
+}
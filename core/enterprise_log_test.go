@@ -0,0 +1,123 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/ethash"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// Tests that the changes of the providers and owner of enterprise contracts emit logs from the EnterpriseLogs fork on.
+func TestEnterpriseLogs(t *testing.T) {
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		newOwnerKey, _ = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		newOwner       = crypto.PubkeyToAddress(newOwnerKey.PublicKey)
+		provider       = common.Address{1}
+		contract       = crypto.CreateAddress(owner, 1)
+		db             = rawdb.NewMemoryDatabase()
+		config         = *params.TestChainConfig
+	)
+	config.EnterpriseLogsBlock = big.NewInt(2)
+	config.OwnershipTransferBlock = big.NewInt(0)
+	var (
+		gspec = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{
+				owner:    {Balance: big.NewInt(params.Ether)},
+				newOwner: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genesis  = gspec.MustCommit(db)
+		signer   = types.MakeSigner(gspec.Config, common.Big1)
+		gasPrice = gspec.Config.GasPrice
+		opts     = types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider}
+	)
+	signWith := func(key *ecdsa.PrivateKey) func(*types.Transaction, error) *types.Transaction {
+		return func(tx *types.Transaction, err error) *types.Transaction {
+			require.NoError(t, err)
+			tx, err = types.SignTx(tx, signer, key)
+			require.NoError(t, err)
+			return tx
+		}
+	}
+	byOwner, byNewOwner := signWith(ownerKey), signWith(newOwnerKey)
+	blocks, receipts := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 3, func(i int, gen *BlockGen) {
+		switch i {
+		case 0:
+			gen.AddTx(byOwner(types.NewContractCreation(0, common.Big0, 100000, gasPrice, nil, opts), nil))
+		case 1:
+			gen.AddTx(byOwner(types.NewContractCreation(1, common.Big0, 100000, gasPrice, nil, opts), nil))
+			gen.AddTx(byOwner(types.NewModifyProvidersTransaction(2, contract, params.TxGas, gasPrice, common.Address{2}, true)))
+			gen.AddTx(byOwner(types.NewModifyProvidersTransaction(3, contract, params.TxGas, gasPrice, provider, false)))
+			// removing a provider twice does not change the providers, thus emits no log
+			gen.AddTx(byOwner(types.NewModifyProvidersTransaction(4, contract, params.TxGas, gasPrice, provider, false)))
+			gen.AddTx(byOwner(types.NewTransferOwnershipTransaction(5, contract, params.TxGas, gasPrice, newOwner)))
+		case 2:
+			gen.AddTx(byNewOwner(types.NewAcceptOwnershipTransaction(0, contract, params.TxGas, gasPrice)))
+		}
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	require.NoError(t, err)
+	defer chain.Stop()
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	// no logs before the fork
+	require.Len(t, receipts[0], 1)
+	require.Empty(t, receipts[0][0].Logs)
+
+	type expectedLog struct {
+		address common.Address
+		topics  []common.Hash
+	}
+	expected := [][]expectedLog{
+		{
+			{contract, []common.Hash{types.OwnershipTransferredTopic, types.AddressTopic(common.Address{}), types.AddressTopic(owner)}},
+			{contract, []common.Hash{types.ProviderAddedTopic, types.AddressTopic(provider)}},
+		},
+		{{contract, []common.Hash{types.ProviderAddedTopic, types.AddressTopic(common.Address{2})}}},
+		{{contract, []common.Hash{types.ProviderRemovedTopic, types.AddressTopic(provider)}}},
+		nil,
+		{{contract, []common.Hash{types.OwnershipTransferRequestedTopic, types.AddressTopic(owner), types.AddressTopic(newOwner)}}},
+	}
+	stored := chain.GetReceiptsByHash(blocks[1].Hash())
+	require.Len(t, stored, len(expected))
+	for i, receipt := range stored {
+		require.Len(t, receipt.Logs, len(expected[i]), "receipt %d", i)
+		for j, log := range receipt.Logs {
+			require.Equal(t, expected[i][j].address, log.Address, "receipt %d log %d", i, j)
+			require.Equal(t, expected[i][j].topics, log.Topics, "receipt %d log %d", i, j)
+			require.True(t, types.BloomLookup(receipt.Bloom, expected[i][j].topics[0]))
+		}
+	}
+	require.Equal(t, types.ReceiptStatusSuccessful, stored[3].Status)
+
+	stored = chain.GetReceiptsByHash(blocks[2].Hash())
+	require.Len(t, stored, 1)
+	require.Len(t, stored[0].Logs, 1)
+	require.Equal(t, []common.Hash{types.OwnershipTransferredTopic, types.AddressTopic(owner), types.AddressTopic(newOwner)}, stored[0].Logs[0].Topics)
+	require.Equal(t, uint64(3), stored[0].Logs[0].BlockNumber)
+
+	// the ownership transfers are invalid before their fork
+	unforked := *gspec.Config
+	unforked.OwnershipTransferBlock = nil
+	db = rawdb.NewMemoryDatabase()
+	(&Genesis{Config: &unforked, Alloc: gspec.Alloc}).MustCommit(db)
+	chain, err = NewBlockChain(db, nil, &unforked, ethash.NewFaker(), vm.Config{}, nil)
+	require.NoError(t, err)
+	defer chain.Stop()
+	i, err := chain.InsertChain(blocks)
+	require.Equal(t, ErrTxTypeNotActive, err)
+	require.Equal(t, 1, i)
+}
@@ -33,6 +33,26 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrGasPriceBelowBaseFee is returned if the gas price of a transaction does not cover
+	// the base fee of its block.
+	ErrGasPriceBelowBaseFee = errors.New("gas price below base fee")
+
+	// ErrProviderBudgetExceeded is returned if the gas limit of a transaction to an enterprise contract exceeds
+	// the gas left in the budget of its provider for the epoch.
+	ErrProviderBudgetExceeded = errors.New("provider gas budget exceeded")
+
+	// ErrSenderAllowanceExceeded is returned if the gas limit of a transaction to an enterprise contract exceeds
+	// the gas left in the allowance of its sender for the epoch.
+	ErrSenderAllowanceExceeded = errors.New("sender gas allowance exceeded")
+
+	// ErrTxTypeNotActive is returned if the type of a transaction is introduced by a fork
+	// which is not active in its block.
+	ErrTxTypeNotActive = errors.New("transaction type not active")
+
+	// ErrNoValidators is returned by the validator set contract if the consensus engine does not
+	// elect the validators of the blocks.
+	ErrNoValidators = errors.New("consensus engine without validators")
+
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
 )
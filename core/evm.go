@@ -45,16 +45,19 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 		beneficiary = *author
 	}
 	return vm.Context{
-		CanTransfer: CanTransfer,
-		Transfer:    Transfer,
-		GetHash:     GetHashFn(header, chain),
-		Origin:      msg.From(),
-		Coinbase:    beneficiary,
-		BlockNumber: new(big.Int).Set(header.Number),
-		Time:        new(big.Int).SetUint64(header.Time),
-		Difficulty:  new(big.Int).Set(header.Difficulty),
-		GasLimit:    header.GasLimit,
-		GasPrice:    new(big.Int).Set(msg.GasPrice()),
+		CanTransfer:   CanTransfer,
+		Transfer:      Transfer,
+		GetHash:       GetHashFn(header, chain),
+		GetValidators: GetValidatorsFn(header, chain),
+		Origin:        msg.From(),
+		Coinbase:      beneficiary,
+		BlockNumber:   new(big.Int).Set(header.Number),
+		Time:          new(big.Int).SetUint64(header.Time),
+		Difficulty:    new(big.Int).Set(header.Difficulty),
+		GasLimit:      header.GasLimit,
+		GasPrice:      new(big.Int).Set(msg.GasPrice()),
+		GasPayer:      msg.GasPayer(),
+		Sponsored:     msg.HasProviderSignature(),
 	}
 }
 
@@ -84,6 +87,38 @@ func GetHashFn(ref *types.Header, chain ChainContext) func(n uint64) common.Hash
 	}
 }
 
+// GetValidatorsFn returns a GetValidatorsFunc which retrieves the validators of the block with
+// their voting powers and the number of committed seals on its parent from the consensus engine
+func GetValidatorsFn(ref *types.Header, chain ChainContext) func() ([]common.Address, []*big.Int, uint64, error) {
+	var (
+		validators  []common.Address
+		powers      []*big.Int
+		parentSeals uint64
+		err         error
+		done        bool
+	)
+	return func() ([]common.Address, []*big.Int, uint64, error) {
+		if done {
+			return validators, powers, parentSeals, err
+		}
+		done = true
+
+		membership, isMembership := chain.Engine().(consensus.Membership)
+		reader, isReader := chain.(consensus.ChainReader)
+		if !isMembership || !isReader {
+			err = ErrNoValidators
+			return nil, nil, 0, err
+		}
+		if validators, powers, err = membership.EpochValidators(reader, ref); err != nil {
+			return nil, nil, 0, err
+		}
+		if parentSeals, err = membership.ParentCommittedSeals(reader, ref); err != nil {
+			return nil, nil, 0, err
+		}
+		return validators, powers, parentSeals, nil
+	}
+}
+
 // CanTransfer checks whether there are enough funds in the address' account to make a transfer.
 // This does not take the necessary gas in to account to make the transfer valid.
 func CanTransfer(db vm.StateDB, addr common.Address, amount *big.Int) bool {
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// CalcBaseFee returns the base fee of the block following the parent, or nil if the fee market is not active for it.
+//
+// The base fee is the minimum gas price of the block while the parent used no more gas than its target, a half of its
+// gas limit. Above the target, it grows linearly with the gas used by the parent up to params.BaseFeeMaxMultiplier
+// times that price for a full parent. Transactions pay the base fee and tip the proposer with the rest of their gas price.
+func CalcBaseFee(config *params.ChainConfig, minGasPrice *big.Int, parent *types.Header) *big.Int {
+	if !config.IsFeeMarket(new(big.Int).Add(parent.Number, common.Big1)) {
+		return nil
+	}
+	var (
+		minBaseFee = minGasPrice
+		target     = parent.GasLimit / params.BaseFeeElasticityMultiplier
+	)
+	if parent.GasUsed <= target || parent.GasLimit <= target {
+		return new(big.Int).Set(minBaseFee)
+	}
+	baseFee := new(big.Int).Mul(minBaseFee, new(big.Int).SetUint64(params.BaseFeeMaxMultiplier-1))
+	baseFee.Mul(baseFee, new(big.Int).SetUint64(parent.GasUsed-target))
+	baseFee.Div(baseFee, new(big.Int).SetUint64(parent.GasLimit-target))
+	return baseFee.Add(baseFee, minBaseFee)
+}
+
+// minGasPrice returns the minimum gas price of the block following the parent, the one governed by the consensus
+// engine if the chain can be read, otherwise the gas price of the chain config.
+func minGasPrice(config *params.ChainConfig, chain ChainContext, parent *types.Header) *big.Int {
+	if reader, ok := chain.(consensus.ChainReader); ok {
+		return consensus.MinGasPrice(chain.Engine(), reader, parent)
+	}
+	return config.GasPrice
+}
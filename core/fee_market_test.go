@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/ethash"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+func feeMarketConfig(block int64) *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.FeeMarketBlock = big.NewInt(block)
+	return &config
+}
+
+func TestCalcBaseFee(t *testing.T) {
+	var (
+		config   = feeMarketConfig(10)
+		minPrice = config.GasPrice.Int64()
+	)
+	tests := []struct {
+		number  int64
+		gasUsed uint64
+		want    *big.Int
+	}{
+		{number: 8, gasUsed: 10000000, want: nil},                           // before the fork
+		{number: 9, gasUsed: 0, want: big.NewInt(minPrice)},                 // first block of the fork
+		{number: 10, gasUsed: 5000000, want: big.NewInt(minPrice)},          // at the target
+		{number: 10, gasUsed: 7500000, want: big.NewInt(minPrice * 9 / 2)},  // half way between the target and the limit
+		{number: 10, gasUsed: 10000000, want: big.NewInt(minPrice * 8)},     // full parent
+		{number: 10, gasUsed: 5000001, want: big.NewInt(minPrice + 1400)},   // slightly above the target
+		{number: 10, gasUsed: 2500000, want: big.NewInt(minPrice)},          // below the target
+		{number: 10, gasUsed: 9999999, want: big.NewInt(minPrice*8 - 1400)}, // almost full parent
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   big.NewInt(test.number),
+			GasLimit: 10000000,
+			GasUsed:  test.gasUsed,
+		}
+		require.Equal(t, test.want, CalcBaseFee(config, config.GasPrice, parent), "test %d", i)
+	}
+	// the base fee follows the minimum gas price governed by the consensus
+	parent := &types.Header{Number: big.NewInt(10), GasLimit: 10000000, GasUsed: 10000000}
+	require.Equal(t, big.NewInt(minPrice*16), CalcBaseFee(config, big.NewInt(minPrice*2), parent))
+}
+
+// Tests that the tip above the base fee is paid to the coinbase and that blocks with
+// transactions below the base fee are rejected.
+func TestFeeMarketTransactions(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.HexToAddress("0xc014ba5e")
+		db       = rawdb.NewMemoryDatabase()
+		gspec    = &Genesis{
+			Config: feeMarketConfig(1),
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.MakeSigner(gspec.Config, common.Big1)
+		tip     = big.NewInt(params.GWei)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 1, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(coinbase)
+		price := new(big.Int).Add(gspec.Config.GasPrice, tip)
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), params.TxGas, price, nil), signer, key)
+		gen.AddTx(tx)
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	require.NoError(t, err)
+	defer chain.Stop()
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	state, err := chain.State()
+	require.NoError(t, err)
+	// the coinbase gets the block reward of ethash on top of the tip
+	reward := new(big.Int).Sub(state.GetBalance(coinbase), ethash.OmahaBlockReward)
+	require.Equal(t, new(big.Int).Mul(tip, big.NewInt(int64(params.TxGas))), reward)
+
+	// a full parent raises the base fee above the gas price of the chain config
+	parent := blocks[0].Header()
+	parent.GasUsed = parent.GasLimit
+	require.True(t, CalcBaseFee(gspec.Config, gspec.Config.GasPrice, parent).Cmp(gspec.Config.GasPrice) > 0)
+
+	underpriced, _ := GenerateChain(gspec.Config, blocks[0], ethash.NewFaker(), db, 1, func(i int, gen *BlockGen) {
+		price := new(big.Int).Sub(gspec.Config.GasPrice, common.Big1)
+		tx, _ := types.SignTx(types.NewTransaction(1, common.Address{1}, big.NewInt(1), params.TxGas, price, nil), signer, key)
+		gen.AddUncheckedTx(tx)
+	})
+	_, err = chain.InsertChain(underpriced)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrGasPriceBelowBaseFee.Error())
+}
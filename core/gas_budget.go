@@ -0,0 +1,27 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+// GasBudgetEpoch returns the epoch of the block number for the gas budgets of the providers of enterprise contracts,
+// the epochs are config.GasBudgetEpochLength() blocks long.
+func GasBudgetEpoch(config *params.ChainConfig, number *big.Int) uint64 {
+	return number.Uint64() / config.GasBudgetEpochLength()
+}
+
+// checkGasBudgets checks whether the provider of an enterprise contract may pay the gas for the sender at the epoch,
+// against the gas budget of the provider and the gas allowance of the sender set by the owner of the contract.
+func checkGasBudgets(statedb vm.StateDB, contract, provider, sender common.Address, epoch uint64, gas uint64) error {
+	if remaining, limited := statedb.GetProviderBudget(contract, provider, epoch); limited && remaining < gas {
+		return ErrProviderBudgetExceeded
+	}
+	if remaining, limited := statedb.GetSenderAllowance(contract, sender, epoch); limited && remaining < gas {
+		return ErrSenderAllowanceExceeded
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/ethash"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+func TestGasBudgetEpoch(t *testing.T) {
+	config := *params.TestChainConfig
+	require.Equal(t, uint64(0), GasBudgetEpoch(&config, big.NewInt(int64(params.GasBudgetEpochLength-1))))
+	require.Equal(t, uint64(1), GasBudgetEpoch(&config, big.NewInt(int64(params.GasBudgetEpochLength))))
+
+	config.Tendermint = &params.TendermintConfig{Epoch: 10}
+	require.Equal(t, uint64(2), GasBudgetEpoch(&config, big.NewInt(25)))
+
+	config.GasBudgetEpoch = 20
+	require.Equal(t, uint64(1), GasBudgetEpoch(&config, big.NewInt(25)))
+}
+
+// Tests that the providers of an enterprise contract pay the gas of the transactions within the gas budgets and
+// allowances set by its owner, and that blocks exceeding them are rejected.
+func TestGasBudgetTransactions(t *testing.T) {
+	config := *params.TestChainConfig
+	config.GasBudgetBlock = common.Big0
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		senderKey, _   = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		sender         = crypto.PubkeyToAddress(senderKey.PublicKey)
+		contract       = crypto.CreateAddress(owner, 0)
+		db             = rawdb.NewMemoryDatabase()
+		gspec          = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{
+				owner:    {Balance: big.NewInt(params.Ether)},
+				provider: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genesis  = gspec.MustCommit(db)
+		signer   = types.MakeSigner(gspec.Config, common.Big1)
+		gasPrice = gspec.Config.GasPrice
+	)
+	sign := func(tx *types.Transaction, err error) *types.Transaction {
+		require.NoError(t, err)
+		tx, err = types.SignTx(tx, signer, ownerKey)
+		require.NoError(t, err)
+		return tx
+	}
+	providerTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, contract, common.Big0, params.TxGas, gasPrice, nil), signer, senderKey)
+		require.NoError(t, err)
+		tx, err = types.ProviderSignTx(tx, signer, providerKey)
+		require.NoError(t, err)
+		return tx
+	}
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 2, func(i int, gen *BlockGen) {
+		switch i {
+		case 0:
+			opts := types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider}
+			gen.AddTx(sign(types.NewContractCreation(0, common.Big0, 100000, gasPrice, nil, opts), nil))
+			gen.AddTx(sign(types.NewSetGasLimitTransaction(1, contract, params.TxGas, gasPrice, provider, 3*params.TxGas, true)))
+			gen.AddTx(sign(types.NewSetGasLimitTransaction(2, contract, params.TxGas, gasPrice, sender, 2*params.TxGas, false)))
+		case 1:
+			gen.AddTx(providerTx(0))
+			gen.AddTx(providerTx(1))
+		}
+	})
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	require.NoError(t, err)
+	defer chain.Stop()
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+
+	state, err := chain.State()
+	require.NoError(t, err)
+	remaining, limited := state.GetProviderBudget(contract, provider, 0)
+	require.True(t, limited)
+	require.Equal(t, params.TxGas, remaining)
+	remaining, limited = state.GetSenderAllowance(contract, sender, 0)
+	require.True(t, limited)
+	require.Equal(t, uint64(0), remaining)
+
+	// the allowance of the sender is spent for the epoch
+	exceeding, _ := GenerateChain(gspec.Config, blocks[1], ethash.NewFaker(), db, 1, func(i int, gen *BlockGen) {
+		gen.AddUncheckedTx(providerTx(2))
+	})
+	_, err = chain.InsertChain(exceeding)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrSenderAllowanceExceeded.Error())
+
+	// the limits can't be set before the fork
+	unforkedDb := rawdb.NewMemoryDatabase()
+	unforked := &Genesis{Config: params.TestChainConfig, Alloc: gspec.Alloc}
+	unforkedGenesis := unforked.MustCommit(unforkedDb)
+	unforkedBlocks, _ := GenerateChain(unforked.Config, unforkedGenesis, ethash.NewFaker(), unforkedDb, 1, func(i int, gen *BlockGen) {
+		opts := types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider}
+		gen.AddTx(sign(types.NewContractCreation(0, common.Big0, 100000, gasPrice, nil, opts), nil))
+		gen.AddUncheckedTx(sign(types.NewSetGasLimitTransaction(1, contract, params.TxGas, gasPrice, provider, 3*params.TxGas, true)))
+	})
+	unforkedChain, err := NewBlockChain(unforkedDb, nil, unforked.Config, ethash.NewFaker(), vm.Config{}, nil)
+	require.NoError(t, err)
+	defer unforkedChain.Stop()
+	_, err = unforkedChain.InsertChain(unforkedBlocks)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrTxTypeNotActive.Error())
+}
@@ -0,0 +1,35 @@
+package rawdb
+
+import (
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+	"github.com/lvbin2012/NeuralChain/neutdb"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+// ReadEpochReward retrieves the reward distribution of the epoch ending at the block of the given number and state root.
+// The state root identifies the block, since its hash is not known yet when the rewards are distributed.
+func ReadEpochReward(db neutdb.KeyValueReader, number uint64, root common.Hash) *types.EpochReward {
+	data, _ := db.Get(epochRewardKey(number, root))
+	if len(data) == 0 {
+		return nil
+	}
+	reward := new(types.EpochReward)
+	if err := rlp.DecodeBytes(data, reward); err != nil {
+		log.Error("Invalid epoch reward RLP", "number", number, "root", root, "err", err)
+		return nil
+	}
+	return reward
+}
+
+// WriteEpochReward stores the reward distribution of the epoch ending at the block of the given number and state root.
+func WriteEpochReward(db neutdb.KeyValueWriter, number uint64, root common.Hash, reward *types.EpochReward) {
+	data, err := rlp.EncodeToBytes(reward)
+	if err != nil {
+		log.Crit("Failed to RLP encode epoch reward", "err", err)
+	}
+	if err := db.Put(epochRewardKey(number, root), data); err != nil {
+		log.Crit("Failed to store epoch reward", "err", err)
+	}
+}
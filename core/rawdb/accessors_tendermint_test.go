@@ -0,0 +1,56 @@
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/types"
+)
+
+// Tests epoch reward storage and retrieval operations.
+func TestEpochRewardStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		root   = common.HexToHash("0x01")
+		reward = &types.EpochReward{
+			BlockNumber: 30,
+			Validators: []*types.ValidatorReward{{
+				Validator:   common.HexToAddress("0x10"),
+				BlockReward: big.NewInt(100),
+				TxFee:       big.NewInt(20),
+				Owner:       common.HexToAddress("0x11"),
+				OwnerReward: big.NewInt(60),
+				Voters: []*types.VoterReward{
+					{Voter: common.HexToAddress("0x12"), Reward: big.NewInt(40)},
+					{Voter: common.HexToAddress("0x13"), Reward: big.NewInt(20)},
+				},
+			}, {
+				Validator:   common.HexToAddress("0x20"),
+				BlockReward: big.NewInt(50),
+				TxFee:       big.NewInt(0),
+				Owner:       common.HexToAddress("0x21"),
+				OwnerReward: big.NewInt(50),
+				Voters:      []*types.VoterReward{},
+				Jailed:      true,
+			}},
+		}
+	)
+	if entry := ReadEpochReward(db, reward.BlockNumber, root); entry != nil {
+		t.Fatalf("Non existent epoch reward returned: %v", entry)
+	}
+	WriteEpochReward(db, reward.BlockNumber, root, reward)
+	entry := ReadEpochReward(db, reward.BlockNumber, root)
+	if entry == nil {
+		t.Fatalf("Stored epoch reward not found")
+	}
+	if !reflect.DeepEqual(entry, reward) {
+		t.Fatalf("Retrieved epoch reward mismatch: have %v, want %v", entry, reward)
+	}
+	// The reward of a block with another state root at the same height is not returned
+	if entry := ReadEpochReward(db, reward.BlockNumber, common.HexToHash("0x02")); entry != nil {
+		t.Fatalf("Epoch reward of another block returned: %v", entry)
+	}
+}
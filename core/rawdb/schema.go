@@ -56,7 +56,8 @@ var (
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("neuralChain-config-") // config prefix for the db
 
-	tendermintPrefix = []byte("tendermint-snapshot-")
+	tendermintPrefix            = []byte("tendermint-snapshot-")
+	tendermintEpochRewardPrefix = []byte("tendermint-reward-") // tendermintEpochRewardPrefix + num (uint64 big endian) + state root -> epoch reward
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
@@ -162,6 +163,11 @@ func preimageKey(hash common.Hash) []byte {
 	return append(preimagePrefix, hash.Bytes()...)
 }
 
+// epochRewardKey = tendermintEpochRewardPrefix + num (uint64 big endian) + state root
+func epochRewardKey(number uint64, root common.Hash) []byte {
+	return append(append(tendermintEpochRewardPrefix, encodeBlockNumber(number)...), root.Bytes()...)
+}
+
 // configKey = configPrefix + hash
 func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
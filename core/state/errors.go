@@ -7,4 +7,6 @@ var (
 	ErrOnlyProvider  = errors.New("only provider can execute transaction to enterprise contract")
 	ErrOnlyOwner     = errors.New("only owner can add or remove provider")
 	ErrOwnerNotFound = errors.New("adding or removing provider transaction should be sent to enterprise contract")
+
+	ErrOnlyPendingOwner = errors.New("only pending owner can accept ownership")
 )
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"github.com/lvbin2012/NeuralChain/common"
+)
+
+// GasBudget limits the gas the providers of an enterprise contract pay per epoch, either the gas
+// paid by a provider or the gas paid for a sender
+type GasBudget struct {
+	Address common.Address
+	Limit   uint64 // The gas per epoch
+	Epoch   uint64 // The epoch of the used gas
+	Used    uint64 // The gas used in the epoch
+}
+
+// remaining returns the gas left in the budget at the epoch
+func (b *GasBudget) remaining(epoch uint64) uint64 {
+	if b.Epoch != epoch {
+		return b.Limit
+	}
+	if b.Used >= b.Limit {
+		return 0
+	}
+	return b.Limit - b.Used
+}
+
+// findBudget returns the index of the budget of the address, -1 if there is none
+func findBudget(budgets []GasBudget, addr common.Address) int {
+	for i := range budgets {
+		if budgets[i].Address == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// setBudget sets the limit of the budget of the address, a limit of 0 removes the budget. The gas used
+// in the current epoch is kept, so that raising or lowering a limit does not reset the budget.
+func setBudget(budgets []GasBudget, addr common.Address, limit uint64) []GasBudget {
+	i := findBudget(budgets, addr)
+	switch {
+	case i == -1 && limit == 0:
+		return budgets
+	case i == -1:
+		return append(budgets, GasBudget{Address: addr, Limit: limit})
+	case limit == 0:
+		return append(budgets[:i], budgets[i+1:]...)
+	default:
+		budgets[i].Limit = limit
+		return budgets
+	}
+}
+
+// useBudget records the gas used at the epoch by the budget of the address, it returns false if there is no such budget
+func useBudget(budgets []GasBudget, addr common.Address, epoch uint64, gas uint64) bool {
+	i := findBudget(budgets, addr)
+	if i == -1 {
+		return false
+	}
+	if budgets[i].Epoch != epoch {
+		budgets[i].Epoch, budgets[i].Used = epoch, 0
+	}
+	budgets[i].Used += gas
+	return true
+}
+
+// SetGasLimit sets the gas budget per epoch of a provider if isProvider, or the gas allowance per epoch of a sender
+// otherwise. It assumes that the permission for setting the limit here is valid.
+func (s *stateObject) SetGasLimit(addr common.Address, limit uint64, isProvider bool) {
+	settings := s.settings()
+	if isProvider {
+		settings.ProviderBudgets = setBudget(settings.ProviderBudgets, addr, limit)
+	} else {
+		settings.SenderAllowances = setBudget(settings.SenderAllowances, addr, limit)
+	}
+	s.SetSettings(settings)
+}
+
+// RemainingGas returns the gas left at the epoch in the budget of a provider if isProvider, or in the allowance
+// of a sender otherwise. It returns false if the gas is not limited.
+func (s *stateObject) RemainingGas(addr common.Address, epoch uint64, isProvider bool) (uint64, bool) {
+	if len(s.data.Settings) == 0 {
+		return 0, false
+	}
+	budgets := s.data.Settings[0].SenderAllowances
+	if isProvider {
+		budgets = s.data.Settings[0].ProviderBudgets
+	}
+	if i := findBudget(budgets, addr); i != -1 {
+		return budgets[i].remaining(epoch), true
+	}
+	return 0, false
+}
+
+// UseGas records the gas paid at the epoch by the provider for the sender in their budget and allowance
+func (s *stateObject) UseGas(provider common.Address, sender common.Address, epoch uint64, gas uint64) {
+	if len(s.data.Settings) == 0 {
+		return
+	}
+	settings := s.settings()
+	usedBudget := useBudget(settings.ProviderBudgets, provider, epoch, gas)
+	usedAllowance := useBudget(settings.SenderAllowances, sender, epoch, gas)
+	if usedBudget || usedAllowance {
+		s.SetSettings(settings)
+	}
+}
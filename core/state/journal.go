@@ -136,6 +136,14 @@ type (
 		account *common.Address
 		prev    []common.Address
 	}
+	ownerChange struct {
+		account *common.Address
+		prev    *common.Address
+	}
+	settingsChange struct {
+		account *common.Address
+		prev    []EnterpriseSettings
+	}
 )
 
 func (ch createObjectChange) revert(s *StateDB) {
@@ -245,3 +253,19 @@ func (ch providersChange) revert(s *StateDB) {
 func (ch providersChange) dirtied() *common.Address {
 	return ch.account
 }
+
+func (ch ownerChange) revert(s *StateDB) {
+	s.getStateObject(*ch.account).setOwner(ch.prev)
+}
+
+func (ch ownerChange) dirtied() *common.Address {
+	return ch.account
+}
+
+func (ch settingsChange) revert(s *StateDB) {
+	s.getStateObject(*ch.account).setSettings(ch.prev)
+}
+
+func (ch settingsChange) dirtied() *common.Address {
+	return ch.account
+}
@@ -0,0 +1,100 @@
+package staking
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/lvbin2012/NeuralChain/common"
+)
+
+// CompareCallers checks whether two staking callers read the same candidates, validators, candidate data and
+// parameters from the staking contract. A mismatch between the EVM caller and the stateDB caller means the index
+// configuration of the stateDB caller does not match the storage layout of the deployed contract,
+// thus it would elect wrong validators.
+func CompareCallers(expected StakingCaller, actual StakingCaller, scAddress common.Address) error {
+	expectedCandidates, expectedErr := expected.GetCandidates(scAddress)
+	actualCandidates, actualErr := actual.GetCandidates(scAddress)
+	if err := compareResults("candidates", expectedCandidates, expectedErr, actualCandidates, actualErr); err != nil {
+		return err
+	}
+
+	expectedValidators, expectedErr := expected.GetValidators(scAddress)
+	actualValidators, actualErr := actual.GetValidators(scAddress)
+	if err := compareResults("validators", expectedValidators, expectedErr, actualValidators, actualErr); err != nil {
+		return err
+	}
+
+	expectedData, err := expected.GetValidatorsData(scAddress, expectedCandidates)
+	if err != nil {
+		return err
+	}
+	actualData, err := actual.GetValidatorsData(scAddress, expectedCandidates)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range expectedCandidates {
+		if !candidateDataEqual(expectedData[candidate], actualData[candidate]) {
+			return fmt.Errorf("candidate data mismatch for %s: expected %+v, got %+v", candidate, expectedData[candidate], actualData[candidate])
+		}
+	}
+
+	expectedParams, err := expected.GetContractParams(scAddress)
+	if err != nil {
+		return err
+	}
+	actualParams, err := actual.GetContractParams(scAddress)
+	if err != nil {
+		return err
+	}
+	if !contractParamsEqual(expectedParams, actualParams) {
+		return fmt.Errorf("contract params mismatch: expected %+v, got %+v", expectedParams, actualParams)
+	}
+	return nil
+}
+
+// compareResults compares the addresses read by two callers, the callers may agree on an empty validator set.
+func compareResults(name string, expected []common.Address, expectedErr error, actual []common.Address, actualErr error) error {
+	if expectedErr != nil && expectedErr != ErrEmptyValidatorSet {
+		return expectedErr
+	}
+	if actualErr != nil && actualErr != ErrEmptyValidatorSet {
+		return actualErr
+	}
+	if expectedErr != actualErr || !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("%s mismatch: expected %v (err: %v), got %v (err: %v)", name,
+			common.PrettyAddresses(expected), expectedErr, common.PrettyAddresses(actual), actualErr)
+	}
+	return nil
+}
+
+func candidateDataEqual(a, b CandidateData) bool {
+	if a.Owner != b.Owner || !bigEqual(a.TotalStake, b.TotalStake) {
+		return false
+	}
+	if len(a.VoterStakes) != len(b.VoterStakes) {
+		return false
+	}
+	for voter, stake := range a.VoterStakes {
+		if otherStake, ok := b.VoterStakes[voter]; !ok || !bigEqual(stake, otherStake) {
+			return false
+		}
+	}
+	return true
+}
+
+func contractParamsEqual(a, b *ContractParams) bool {
+	return a.Admin == b.Admin &&
+		bigEqual(a.StartBlock, b.StartBlock) &&
+		bigEqual(a.EpochPeriod, b.EpochPeriod) &&
+		bigEqual(a.MaxValidatorSize, b.MaxValidatorSize) &&
+		bigEqual(a.MinValidatorStake, b.MinValidatorStake) &&
+		bigEqual(a.MinVoterCap, b.MinVoterCap)
+}
+
+func bigEqual(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
@@ -0,0 +1,94 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/types"
+)
+
+var (
+	jailedPrefix        = []byte("jailed")
+	missedBlocksPrefix  = []byte("missedBlocks")
+	missedBlockPrefix   = []byte("missedBlock")
+	livenessSincePrefix = []byte("livenessSince")
+	livenessLastPrefix  = []byte("livenessLast")
+)
+
+func getStatusUint64(stateDB *state.StateDB, key common.Hash) uint64 {
+	return stateDB.GetState(ValidatorStatusAddress, key).Big().Uint64()
+}
+
+func setStatusUint64(stateDB *state.StateDB, key common.Hash, value uint64) {
+	if stateDB.GetNonce(ValidatorStatusAddress) == 0 {
+		stateDB.SetNonce(ValidatorStatusAddress, 1)
+	}
+	stateDB.SetState(ValidatorStatusAddress, key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+// IsJailed returns true if the validator is excluded from the next validator sets for missing too many blocks
+func IsJailed(stateDB *state.StateDB, validator common.Address) bool {
+	return stateDB.GetState(ValidatorStatusAddress, statusKey(jailedPrefix, validator.Bytes())) == statusFlag
+}
+
+// Jail excludes the validator from the next validator sets until it sends an unjail transaction.
+// The liveness of the validator is tracked from scratch afterwards.
+func Jail(stateDB *state.StateDB, validator common.Address) {
+	setStatusFlag(stateDB, statusKey(jailedPrefix, validator.Bytes()))
+	setStatusUint64(stateDB, statusKey(livenessLastPrefix, validator.Bytes()), 0)
+}
+
+// Unjail allows the validator to be elected in the next validator sets again
+func Unjail(stateDB *state.StateDB, validator common.Address) {
+	stateDB.SetState(ValidatorStatusAddress, statusKey(jailedPrefix, validator.Bytes()), common.Hash{})
+	setStatusUint64(stateDB, statusKey(livenessLastPrefix, validator.Bytes()), 0)
+}
+
+// MissedBlocks returns the number of blocks the validator missed to commit in the current sliding window
+func MissedBlocks(stateDB *state.StateDB, validator common.Address) uint64 {
+	return getStatusUint64(stateDB, statusKey(missedBlocksPrefix, validator.Bytes()))
+}
+
+// RecordLiveness records whether the validator committed the block of the given number, which must be greater than 0,
+// and returns the number of blocks it missed in the sliding window of the latest window blocks.
+// The blocks must be recorded in order. If the validator was not tracked for the previous block, e.g. it was not a validator
+// or it has just been jailed, the tracking restarts from this block.
+func RecordLiveness(stateDB *state.StateDB, validator common.Address, number uint64, missed bool, window uint64) uint64 {
+	var (
+		addr        = validator.Bytes()
+		missedKey   = statusKey(missedBlocksPrefix, addr)
+		sinceKey    = statusKey(livenessSincePrefix, addr)
+		lastKey     = statusKey(livenessLastPrefix, addr)
+		slotKey     = statusKey(missedBlockPrefix, append(addr, new(big.Int).SetUint64(number%window).Bytes()...))
+		missedCount = getStatusUint64(stateDB, missedKey)
+		since       = getStatusUint64(stateDB, sinceKey)
+	)
+	if last := getStatusUint64(stateDB, lastKey); last == 0 || last+1 != number {
+		missedCount = 0
+		since = number
+		setStatusUint64(stateDB, sinceKey, since)
+	}
+
+	// the slot stores the number of the last missed block which used it, that block leaves the window now
+	previous := getStatusUint64(stateDB, slotKey)
+	if previous != 0 && previous+window == number && previous >= since {
+		missedCount--
+	}
+	if missed {
+		setStatusUint64(stateDB, slotKey, number)
+		missedCount++
+	} else if previous != 0 {
+		setStatusUint64(stateDB, slotKey, 0)
+	}
+
+	setStatusUint64(stateDB, missedKey, missedCount)
+	setStatusUint64(stateDB, lastKey, number)
+	return missedCount
+}
+
+// NewUnjailTransaction returns the transaction which a jailed validator sends to be elected in the next validator sets again.
+// It is a transfer without value to ValidatorStatusAddress, which must be signed by the validator's key.
+func NewUnjailTransaction(nonce uint64, gasLimit uint64, gasPrice *big.Int) *types.Transaction {
+	return types.NewTransaction(nonce, ValidatorStatusAddress, common.Big0, gasLimit, gasPrice, nil)
+}
@@ -0,0 +1,65 @@
+package staking_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+)
+
+func TestRecordLiveness(t *testing.T) {
+	const window = 4
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	validator := common.HexToAddress("0x1")
+
+	// blocks 2 and 3 leave the window at blocks 6 and 7
+	for _, step := range []struct {
+		number   uint64
+		missed   bool
+		expected uint64
+	}{
+		{1, false, 0},
+		{2, true, 1},
+		{3, true, 2},
+		{4, false, 2},
+		{5, false, 2},
+		{6, true, 2},
+		{7, false, 1},
+		{8, false, 1},
+	} {
+		assert.Equal(t, step.expected, staking.RecordLiveness(stateDB, validator, step.number, step.missed, window), "block %d", step.number)
+	}
+	assert.Equal(t, uint64(1), staking.MissedBlocks(stateDB, validator))
+
+	// the tracking restarts after a gap, e.g. the validator was not in the validator set
+	assert.Equal(t, uint64(0), staking.RecordLiveness(stateDB, validator, 20, false, window))
+	// block 6 is not in the window anymore
+	assert.Equal(t, uint64(0), staking.RecordLiveness(stateDB, validator, 21, false, window))
+}
+
+func TestJail(t *testing.T) {
+	const window = 10
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	validator := common.HexToAddress("0x1")
+
+	assert.Equal(t, uint64(1), staking.RecordLiveness(stateDB, validator, 1, true, window))
+	assert.Equal(t, uint64(2), staking.RecordLiveness(stateDB, validator, 2, true, window))
+	assert.False(t, staking.IsJailed(stateDB, validator))
+	staking.Jail(stateDB, validator)
+	// the flags must survive the deletion of empty accounts
+	stateDB.Finalise(true)
+	assert.True(t, staking.IsJailed(stateDB, validator))
+	// the liveness is tracked from scratch after being jailed
+	assert.Equal(t, uint64(1), staking.RecordLiveness(stateDB, validator, 3, true, window))
+
+	staking.Unjail(stateDB, validator)
+	assert.False(t, staking.IsJailed(stateDB, validator))
+	assert.Equal(t, uint64(0), staking.RecordLiveness(stateDB, validator, 4, false, window))
+}
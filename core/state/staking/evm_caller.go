@@ -30,6 +30,22 @@ type evmStakingCaller struct {
 	vmConfig     vm.Config
 }
 
+// GetCandidates returns list candidate's address by smart-contract's address
+func (caller *evmStakingCaller) GetCandidates(scAddress common.Address) ([]common.Address, error) {
+	sc, err := staking_contracts.NewStakingContractsCaller(scAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	data, err := sc.GetListCandidates(nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Candidates) == 0 {
+		return nil, ErrEmptyValidatorSet
+	}
+	return data.Candidates, nil
+}
+
 // GetValidators returns validators from stateDB and block number of the caller by smart-contract's address
 func (caller *evmStakingCaller) GetValidators(scAddress common.Address) ([]common.Address, error) {
 	var (
@@ -123,6 +139,34 @@ func (caller *evmStakingCaller) GetValidatorsData(scAddress common.Address, cand
 	return allVoterStake, nil
 }
 
+// GetContractParams returns the parameters of the staking contract
+func (caller *evmStakingCaller) GetContractParams(scAddress common.Address) (*ContractParams, error) {
+	sc, err := staking_contracts.NewStakingContractsCaller(scAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	contractParams := new(ContractParams)
+	if contractParams.Admin, err = sc.Admin(nil); err != nil {
+		return nil, err
+	}
+	if contractParams.StartBlock, err = sc.StartBlock(nil); err != nil {
+		return nil, err
+	}
+	if contractParams.EpochPeriod, err = sc.EpochPeriod(nil); err != nil {
+		return nil, err
+	}
+	if contractParams.MaxValidatorSize, err = sc.MaxValidatorSize(nil); err != nil {
+		return nil, err
+	}
+	if contractParams.MinValidatorStake, err = sc.MinValidatorStake(nil); err != nil {
+		return nil, err
+	}
+	if contractParams.MinVoterCap, err = sc.MinVoterCap(nil); err != nil {
+		return nil, err
+	}
+	return contractParams, nil
+}
+
 // Deprecated: Using NewStateDbStakingCaller instead of
 // NewBECaller returns staking caller which reads data from staking smart-contract by execute a call from evm
 func NewEVMStakingCaller(stateDB *state.StateDB, chainContext core.ChainContext, header *types.Header,
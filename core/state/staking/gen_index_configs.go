@@ -0,0 +1,22 @@
+// Code generated by mklayout.go. DO NOT EDIT.
+
+package staking
+
+// DefaultConfig represents the default configuration, derived from the storage layout of the staking contract.
+var DefaultConfig = &IndexConfigs{
+	WithdrawsStateLayout:    NewLayOut(1, 0),
+	CandidateVotersLayout:   NewLayOut(2, 0),
+	CandidateDataLayout:     NewLayOut(3, 0),
+	CandidatesLayout:        NewLayOut(4, 0),
+	StartBlockLayout:        NewLayOut(5, 0),
+	EpochPeriodLayout:       NewLayOut(6, 0),
+	MaxValidatorSizeLayout:  NewLayOut(7, 0),
+	MinValidatorStakeLayout: NewLayOut(8, 0),
+	MinVoterCapLayout:       NewLayOut(9, 0),
+	AdminLayout:             NewLayOut(10, 0),
+	CandidateDataStruct: CandidateDataStructIndex{
+		Owner:        NewLayOut(2, 0),
+		TotalStake:   NewLayOut(1, 0),
+		VotersStakes: NewLayOut(3, 0),
+	},
+}
@@ -0,0 +1,80 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/types"
+)
+
+// The chain parameters governed by the governance contract, the ids must match the ones of the contract
+// (consensus/governance_contracts/NeuralChainGovernance.sol).
+const (
+	// GovernedBlockReward is the reward of a block in wei
+	GovernedBlockReward uint64 = iota
+	// GovernedTimeoutPropose is the timeout of the propose step in milliseconds
+	GovernedTimeoutPropose
+	// GovernedTimeoutPrevote is the timeout of the prevote step in milliseconds
+	GovernedTimeoutPrevote
+	// GovernedTimeoutPrecommit is the timeout of the precommit step in milliseconds
+	GovernedTimeoutPrecommit
+	// GovernedTimeoutCommit is the timeout of the commit step in milliseconds
+	GovernedTimeoutCommit
+	// GovernedEpoch is the number of blocks of an epoch, the staking contract keeps its own epoch period for the withdrawals
+	GovernedEpoch
+	// GovernedGasPrice is the minimum gas price in wei
+	GovernedGasPrice
+
+	governedParamsCount
+)
+
+// The slots of the state variables of the governance contract holding the approved values
+var (
+	approvedValuesSlot = common.BigToHash(big.NewInt(2)) // mapping(uint256 => uint256) approvedValues
+	isApprovedSlot     = common.BigToHash(big.NewInt(3)) // mapping(uint256 => bool) isApproved
+)
+
+// GovernanceCaller reads the chain parameters approved by the governance contract
+type GovernanceCaller interface {
+	// GetApprovedParams returns the approved parameters sorted by id, the ones never approved are omitted
+	GetApprovedParams(common.Address) ([]*types.GovernedParam, error)
+}
+
+// stateDBGovernanceCaller reads the approved parameters directly from the storage of the governance contract
+type stateDBGovernanceCaller struct {
+	stateDB *state.StateDB
+}
+
+// NewStateDbGovernanceCaller returns instance of GovernanceCaller which reads data directly from state DB
+func NewStateDbGovernanceCaller(state *state.StateDB) GovernanceCaller {
+	return &stateDBGovernanceCaller{
+		stateDB: state,
+	}
+}
+
+// GetApprovedParams returns the approved parameters sorted by id, there are none if the contract is not deployed
+func (c *stateDBGovernanceCaller) GetApprovedParams(scAddress common.Address) ([]*types.GovernedParam, error) {
+	var params []*types.GovernedParam
+	for param := uint64(0); param < governedParamsCount; param++ {
+		key := common.BigToHash(new(big.Int).SetUint64(param))
+		if c.stateDB.GetState(scAddress, getMappingElementLoc(isApprovedSlot, key)) == (common.Hash{}) {
+			continue
+		}
+		params = append(params, &types.GovernedParam{
+			Param: param,
+			Value: c.stateDB.GetState(scAddress, getMappingElementLoc(approvedValuesSlot, key)).Big(),
+		})
+	}
+	return params, nil
+}
+
+// GovernedValue returns the value of the parameter among the approved ones, nil if it is not approved
+func GovernedValue(params []*types.GovernedParam, param uint64) *big.Int {
+	for _, governed := range params {
+		if governed.Param == param {
+			return governed.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package staking_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/accounts/abi"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/compiler"
+	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+func TestGetApprovedParams(t *testing.T) {
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	scAddress := common.HexToAddress("0x1234")
+	caller := staking.NewStateDbGovernanceCaller(stateDB)
+
+	params, err := caller.GetApprovedParams(scAddress)
+	require.NoError(t, err)
+	assert.Len(t, params, 0)
+
+	// approvedValues and isApproved are the mappings at the slots 2 and 3 of the contract
+	approve := func(param uint64, value *big.Int) {
+		key := common.BigToHash(new(big.Int).SetUint64(param))
+		stateDB.SetState(scAddress, crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(2)).Bytes()), common.BigToHash(value))
+		stateDB.SetState(scAddress, crypto.Keccak256Hash(key.Bytes(), common.BigToHash(big.NewInt(3)).Bytes()), common.BigToHash(big.NewInt(1)))
+	}
+	approve(staking.GovernedTimeoutCommit, big.NewInt(2000))
+	approve(staking.GovernedBlockReward, big.NewInt(0))
+
+	params, err = caller.GetApprovedParams(scAddress)
+	require.NoError(t, err)
+	// a parameter approved with the value 0 is still returned
+	require.Len(t, params, 2)
+	assert.Equal(t, staking.GovernedBlockReward, params[0].Param)
+	assert.Equal(t, 0, params[0].Value.Sign())
+	assert.Equal(t, &types.GovernedParam{Param: staking.GovernedTimeoutCommit, Value: big.NewInt(2000)}, params[1])
+	assert.Equal(t, big.NewInt(2000), staking.GovernedValue(params, staking.GovernedTimeoutCommit))
+	assert.Nil(t, staking.GovernedValue(params, staking.GovernedTimeoutPropose))
+}
+
+// TestGovernanceContract deploys the compiled governance contract, votes on it and reads back the approved values
+func TestGovernanceContract(t *testing.T) {
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip(err)
+	}
+	const source = "../../../consensus/governance_contracts/NeuralChainGovernance.sol"
+	contracts, err := compiler.CompileSolidity("solc", source)
+	require.NoError(t, err)
+	contract := contracts[source+":NeuralChainGovernance"]
+	require.NotNil(t, contract)
+	abiJSON, err := json.Marshal(contract.Info.AbiDefinition)
+	require.NoError(t, err)
+	governanceABI, err := abi.JSON(bytes.NewReader(abiJSON))
+	require.NoError(t, err)
+
+	stateDB, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	require.NoError(t, err)
+	scAddress := common.HexToAddress("0x1234")
+	stateDB.SetCode(scAddress, common.FromHex(contract.RuntimeCode))
+
+	var (
+		validators = []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")}
+		powers     []*big.Int
+		number     = big.NewInt(1)
+		config     = *params.TestChainConfig
+	)
+	config.ValidatorSetBlock = big.NewInt(0)
+	run := func(from common.Address, method string, args ...interface{}) ([]byte, error) {
+		input, err := governanceABI.Pack(method, args...)
+		require.NoError(t, err)
+		evm := vm.NewEVM(vm.Context{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetValidators: func() ([]common.Address, []*big.Int, uint64, error) {
+				return validators, powers, 0, nil
+			},
+			BlockNumber: new(big.Int).Set(number),
+		}, stateDB, &config, vm.Config{})
+		ret, _, err := evm.Call(vm.AccountRef(from), scAddress, input, gasLimit, new(big.Int))
+		return ret, err
+	}
+	call := func(from common.Address, method string, args ...interface{}) error {
+		_, err := run(from, method, args...)
+		return err
+	}
+	approvedValue := func(param uint64) *big.Int {
+		approved, err := staking.NewStateDbGovernanceCaller(stateDB).GetApprovedParams(scAddress)
+		require.NoError(t, err)
+		return staking.GovernedValue(approved, param)
+	}
+
+	// only the validators can propose
+	assert.Error(t, call(common.HexToAddress("0x04"), "propose", big.NewInt(0), big.NewInt(5)))
+
+	// each validator has one vote, more than 2/3 of them must vote
+	require.NoError(t, call(validators[0], "propose", new(big.Int).SetUint64(staking.GovernedBlockReward), big.NewInt(5)))
+	require.NoError(t, call(validators[1], "vote", big.NewInt(0)))
+	assert.Nil(t, approvedValue(staking.GovernedBlockReward))
+	require.NoError(t, call(validators[2], "vote", big.NewInt(0)))
+	assert.Equal(t, big.NewInt(5), approvedValue(staking.GovernedBlockReward))
+
+	// the votes are weighted by stake
+	powers = []*big.Int{big.NewInt(5), big.NewInt(1), big.NewInt(1)}
+	timeoutCommit := new(big.Int).SetUint64(staking.GovernedTimeoutCommit)
+	require.NoError(t, call(validators[1], "propose", timeoutCommit, big.NewInt(2000)))
+	require.NoError(t, call(validators[1], "propose", timeoutCommit, big.NewInt(3000)))
+	assert.Nil(t, approvedValue(staking.GovernedTimeoutCommit))
+	require.NoError(t, call(validators[0], "vote", big.NewInt(2)))
+	assert.Equal(t, big.NewInt(3000), approvedValue(staking.GovernedTimeoutCommit))
+
+	// an older proposal approved late does not override the newer value
+	require.NoError(t, call(validators[0], "vote", big.NewInt(1)))
+	assert.Equal(t, big.NewInt(3000), approvedValue(staking.GovernedTimeoutCommit))
+
+	// the proposals expire
+	timeoutPropose := new(big.Int).SetUint64(staking.GovernedTimeoutPropose)
+	require.NoError(t, call(validators[1], "propose", timeoutPropose, big.NewInt(1000)))
+	ret, err := run(validators[0], "VOTING_PERIOD")
+	require.NoError(t, err)
+	votingPeriod := new(big.Int)
+	require.NoError(t, governanceABI.Unpack(&votingPeriod, "VOTING_PERIOD", ret))
+	number.Add(number, votingPeriod)
+	number.Add(number, common.Big1)
+	assert.Error(t, call(validators[0], "vote", big.NewInt(3)))
+	assert.Nil(t, approvedValue(staking.GovernedTimeoutPropose))
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of the NeuralChain library .
+//
+// The NeuralChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The NeuralChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the NeuralChain library . If not, see <http://www.gnu.org/licenses/>.
+
+// +build none
+
+/*
+
+   The mklayout tool creates the default index configuration of the staking contract in gen_index_configs.go
+   from the storage layout output of solc, so the slots read by the stateDB staking caller match the contract.
+
+       go run mklayout.go storage-layout.json > gen_index_configs.go.tmp && mv gen_index_configs.go.tmp gen_index_configs.go
+
+*/
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"reflect"
+
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+)
+
+// writeLiteral writes the fields of the index configuration as a composite literal.
+func writeLiteral(buf *bytes.Buffer, value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field, name := value.Field(i), value.Type().Field(i).Name
+		if layOut, ok := field.Interface().(staking.LayOut); ok {
+			fmt.Fprintf(buf, "%s: NewLayOut(%d, %d),\n", name, layOut.Slot, layOut.Offset)
+			continue
+		}
+		fmt.Fprintf(buf, "%s: %s{\n", name, field.Type().Name())
+		writeLiteral(buf, field)
+		buf.WriteString("},\n")
+	}
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: go run mklayout.go storage-layout.json")
+		os.Exit(1)
+	}
+	cfg, err := staking.LoadIndexConfigsFile(os.Args[1], staking.DefaultContractName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by mklayout.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package staking\n\n")
+	buf.WriteString("// DefaultConfig represents the default configuration, derived from the storage layout of the staking contract.\n")
+	buf.WriteString("var DefaultConfig = &IndexConfigs{\n")
+	writeLiteral(buf, reflect.ValueOf(*cfg))
+	buf.WriteString("}\n")
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(code)
+}
@@ -0,0 +1,114 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+var (
+	// ValidatorStatusAddress is the reserved account whose storage records the tombstoned validators
+	// and the offences which have been slashed.
+	ValidatorStatusAddress = common.BytesToAddress(crypto.Keccak256([]byte("tendermint.validatorStatus")))
+
+	tombstonedPrefix      = []byte("tombstoned")
+	slashedOffencesPrefix = []byte("slashedOffence")
+	// statusFlag is the value stored for a set flag in ValidatorStatusAddress's storage
+	statusFlag = common.BigToHash(common.Big1)
+)
+
+func statusKey(prefix []byte, key []byte) common.Hash {
+	return crypto.Keccak256Hash(prefix, key)
+}
+
+func setStatusFlag(stateDB *state.StateDB, key common.Hash) {
+	// an account without nonce, balance and code is removed as empty, so the storage would be lost
+	if stateDB.GetNonce(ValidatorStatusAddress) == 0 {
+		stateDB.SetNonce(ValidatorStatusAddress, 1)
+	}
+	stateDB.SetState(ValidatorStatusAddress, key, statusFlag)
+}
+
+// IsTombstoned returns true if the validator was permanently removed from the validator set for misbehaving
+func IsTombstoned(stateDB *state.StateDB, validator common.Address) bool {
+	return stateDB.GetState(ValidatorStatusAddress, statusKey(tombstonedPrefix, validator.Bytes())) == statusFlag
+}
+
+// Tombstone permanently removes the validator from the next validator sets
+func Tombstone(stateDB *state.StateDB, validator common.Address) {
+	setStatusFlag(stateDB, statusKey(tombstonedPrefix, validator.Bytes()))
+}
+
+// offenceKey returns the key of the double signing of the offender at the block number. The offender signs
+// many pairs of conflicting votes at that height, each of them a distinct evidence, but is slashed once.
+func offenceKey(offender common.Address, number *big.Int) common.Hash {
+	return statusKey(slashedOffencesPrefix, append(offender.Bytes(), common.BigToHash(number).Bytes()...))
+}
+
+// IsOffenceSlashed returns true if the offender has been slashed for double signing at the block number
+func IsOffenceSlashed(stateDB *state.StateDB, offender common.Address, number *big.Int) bool {
+	return stateDB.GetState(ValidatorStatusAddress, offenceKey(offender, number)) == statusFlag
+}
+
+// MarkOffenceSlashed records the double signing of the offender at the block number as slashed,
+// so no other evidence of it can be applied
+func MarkOffenceSlashed(stateDB *state.StateDB, offender common.Address, number *big.Int) {
+	setStatusFlag(stateDB, offenceKey(offender, number))
+}
+
+// Slash reduces the stake of every voter of the candidate by percentage and burns the slashed amount
+// from the balance of the staking smart-contract. It returns the total slashed amount.
+//
+// The staking smart-contract has no slashing method, so the stakes are written directly to its storage:
+// the VotersStakes mapping and the TotalStake of the candidate's CandidateData, at the slots of cfg.
+// They must match the storage layout of the deployed contract, so cfg has to be regenerated with mklayout
+// whenever the contract changes; TestSlash checks that the contract reads the slashed stakes back.
+func Slash(stateDB *state.StateDB, cfg *IndexConfigs, stakingContractAddr common.Address, candidate common.Address, percentage uint64) *big.Int {
+	var (
+		c       = &stateDBStakingCaller{stateDB: stateDB, config: cfg}
+		total   = new(big.Int)
+		loc     = getMappingElementLoc(cfg.CandidateDataLayout.slotHash(), candidate.Hash())
+		pct     = new(big.Int).SetUint64(percentage)
+		hundred = big.NewInt(100)
+	)
+	if percentage == 0 {
+		return total
+	}
+	if percentage > 100 {
+		pct = hundred
+	}
+	voterStakesSlot := addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.VotersStakes.Slot))
+	// a voter is listed again whenever it votes for the candidate, it must be slashed only once
+	slashedVoters := make(map[common.Address]bool)
+	for _, voter := range c.GetVoters(stakingContractAddr, candidate) {
+		if slashedVoters[voter] {
+			continue
+		}
+		slashedVoters[voter] = true
+		voterStakeSlot := getMappingElementLoc(voterStakesSlot, voter.Hash())
+		stake := c.getBigInt(stakingContractAddr, voterStakeSlot)
+		slashed := new(big.Int).Div(new(big.Int).Mul(stake, pct), hundred)
+		if slashed.Sign() == 0 {
+			continue
+		}
+		stateDB.SetState(stakingContractAddr, voterStakeSlot, common.BigToHash(new(big.Int).Sub(stake, slashed)))
+		total.Add(total, slashed)
+	}
+
+	totalStakeLoc := addOffsetToLoc(loc, new(big.Int).SetUint64(cfg.CandidateDataStruct.TotalStake.Slot))
+	totalStake := c.getBigInt(stakingContractAddr, totalStakeLoc)
+	if total.Cmp(totalStake) > 0 {
+		total.Set(totalStake)
+	}
+	stateDB.SetState(stakingContractAddr, totalStakeLoc, common.BigToHash(new(big.Int).Sub(totalStake, total)))
+
+	// burn the slashed amount, which is held by the staking smart-contract
+	burned := new(big.Int).Set(total)
+	if balance := stateDB.GetBalance(stakingContractAddr); burned.Cmp(balance) > 0 {
+		burned.Set(balance)
+	}
+	stateDB.SubBalance(stakingContractAddr, burned)
+	return total
+}
@@ -0,0 +1,116 @@
+package staking_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/accounts/abi/bind"
+	"github.com/lvbin2012/NeuralChain/accounts/abi/bind/backends"
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/ethash"
+	"github.com/lvbin2012/NeuralChain/consensus/staking_contracts"
+	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+)
+
+func TestSlash(t *testing.T) {
+	var (
+		a, _       = common.NeutAddressStringToAddressCheck("NTkhwcpZULSbKURKqw3PYV5GEbhZFXjjBK")
+		b, _       = common.NeutAddressStringToAddressCheck("NZXRfVCDbp8yttymzTg1FZ3Z4c5eJiKPDk")
+		c, _       = common.NeutAddressStringToAddressCheck("NQyDozx1bK12Mv7T1pyuSnJCz45FgiKCyu")
+		candidates = []common.Address{
+			a,
+			b,
+		}
+		newCandidate = c
+	)
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(*privateKey.Public().(*ecdsa.PublicKey))
+
+	be := backends.NewSimulatedBackend(core.GenesisAlloc{
+		addr: core.GenesisAccount{
+			Balance: big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil),
+		},
+		newCandidate: core.GenesisAccount{
+			Balance: new(big.Int).Mul(big.NewInt(gasLimit), big.NewInt(params.GasPriceConfig)),
+		},
+	}, gasLimit)
+
+	authOpts := bind.NewKeyedTransactor(privateKey)
+	authOpts.Nonce = big.NewInt(0)
+	scAddress, tx, contract, err := staking_contracts.DeployStakingContracts(authOpts, be, candidates, candidates,
+		big.NewInt(300000), common.Big0, big.NewInt(100), big.NewInt(20), big.NewInt(10), a)
+	require.NoError(t, err)
+	be.Commit()
+	assertTxSuccess(t, be, tx.Hash())
+
+	authOpts = bind.NewKeyedTransactor(privateKey)
+	authOpts.Nonce = big.NewInt(1)
+	tx, err = contract.Register(authOpts, newCandidate, newCandidate)
+	require.NoError(t, err)
+	be.Commit()
+	assertTxSuccess(t, be, tx.Hash())
+
+	ownerPk, _ := crypto.HexToECDSA(newCandidatePkHex)
+	authOpts = bind.NewKeyedTransactor(ownerPk)
+	authOpts.Nonce = big.NewInt(0)
+	authOpts.Value = big.NewInt(1000)
+	tx, err = contract.Vote(authOpts, newCandidate)
+	require.NoError(t, err)
+	authOpts = bind.NewKeyedTransactor(privateKey)
+	authOpts.Nonce = big.NewInt(2)
+	authOpts.Value = big.NewInt(30)
+	tx2, err := contract.Vote(authOpts, newCandidate)
+	require.NoError(t, err)
+	be.Commit()
+	assertTxSuccess(t, be, tx.Hash())
+	assertTxSuccess(t, be, tx2.Hash())
+
+	stateDB, err := be.CurrentStateDb()
+	require.NoError(t, err)
+	balance := stateDB.GetBalance(scAddress)
+
+	slashed := staking.Slash(stateDB, staking.DefaultConfig, scAddress, newCandidate, 10)
+	assert.Equal(t, big.NewInt(103), slashed)
+	assert.Equal(t, new(big.Int).Sub(balance, slashed), stateDB.GetBalance(scAddress))
+
+	data, err := staking.NewStateDbStakingCaller(stateDB, staking.DefaultConfig).GetValidatorsData(scAddress, []common.Address{newCandidate})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(927), data[newCandidate].TotalStake)
+	assert.Equal(t, big.NewInt(900), data[newCandidate].VoterStakes[newCandidate])
+	assert.Equal(t, big.NewInt(27), data[newCandidate].VoterStakes[addr])
+
+	// the stakes are written to the storage of the contract, which must read them back through its own getters
+	header := &types.Header{Number: big.NewInt(4), Difficulty: common.Big1, GasLimit: gasLimit}
+	chainContext := staking.NewChainContextWrapper(ethash.NewFaker(), func(common.Hash, uint64) *types.Header { return nil })
+	evmCaller := staking.NewEVMStakingCaller(stateDB.Copy(), chainContext, header, params.AllEthashProtocolChanges, vm.Config{})
+	data, err = evmCaller.GetValidatorsData(scAddress, []common.Address{newCandidate})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(927), data[newCandidate].TotalStake)
+	assert.Equal(t, big.NewInt(900), data[newCandidate].VoterStakes[newCandidate])
+	assert.Equal(t, big.NewInt(27), data[newCandidate].VoterStakes[addr])
+	require.NoError(t, staking.CompareCallers(evmCaller, staking.NewStateDbStakingCaller(stateDB, staking.DefaultConfig), scAddress))
+
+	assert.False(t, staking.IsTombstoned(stateDB, newCandidate))
+	staking.Tombstone(stateDB, newCandidate)
+	assert.True(t, staking.IsTombstoned(stateDB, newCandidate))
+	assert.False(t, staking.IsTombstoned(stateDB, addr))
+
+	assert.False(t, staking.IsOffenceSlashed(stateDB, newCandidate, big.NewInt(3)))
+	staking.MarkOffenceSlashed(stateDB, newCandidate, big.NewInt(3))
+	// the flags must survive the deletion of empty accounts
+	stateDB.Finalise(true)
+	assert.True(t, staking.IsOffenceSlashed(stateDB, newCandidate, big.NewInt(3)))
+	assert.False(t, staking.IsOffenceSlashed(stateDB, newCandidate, big.NewInt(4)))
+	assert.False(t, staking.IsOffenceSlashed(stateDB, addr, big.NewInt(3)))
+	assert.True(t, staking.IsTombstoned(stateDB, newCandidate))
+}
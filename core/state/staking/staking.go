@@ -20,10 +20,14 @@ var (
 )
 
 type StakingCaller interface {
+	// GetCandidates returns list of candidates, including the ones not elected as validators
+	GetCandidates(common.Address) ([]common.Address, error)
 	// GetValidators returns list of validators, calculate from current stateDB
 	GetValidators(common.Address) ([]common.Address, error)
 	// GetValidatorsData return information of validators including owner, totalStake and voterStakes
 	GetValidatorsData(common.Address, []common.Address) (map[common.Address]CandidateData, error)
+	// GetContractParams returns the parameters of the staking contract
+	GetContractParams(common.Address) (*ContractParams, error)
 }
 
 type CandidateData struct {
@@ -31,3 +35,13 @@ type CandidateData struct {
 	VoterStakes map[common.Address]*big.Int
 	TotalStake  *big.Int
 }
+
+// ContractParams are the parameters of the staking contract, set at its deployment or by its admin.
+type ContractParams struct {
+	Admin             common.Address
+	StartBlock        *big.Int
+	EpochPeriod       *big.Int
+	MaxValidatorSize  *big.Int
+	MinValidatorStake *big.Int
+	MinVoterCap       *big.Int
+}
@@ -77,6 +77,15 @@ func testGetValidators(t *testing.T, indexCfg *staking.IndexConfigs) {
 	stakingCaller, err := be.GetStakingCaller(indexCfg)
 	require.NoError(t, err)
 
+	contractParams, err := stakingCaller.GetContractParams(addr)
+	require.NoError(t, err)
+	require.Equal(t, adminAddr, contractParams.Admin)
+	require.Equal(t, startBlock.Uint64(), contractParams.StartBlock.Uint64())
+	require.Equal(t, epoch, contractParams.EpochPeriod)
+	require.Equal(t, maxValidatorSize, contractParams.MaxValidatorSize)
+	require.Equal(t, minValidatorStake, contractParams.MinValidatorStake)
+	require.Equal(t, minVoteCap, contractParams.MinVoterCap)
+
 	validators, err := stakingCaller.GetValidators(addr)
 	require.NoError(t, err)
 	require.Equal(t, len(validators), 2)
@@ -96,6 +105,10 @@ func testGetValidators(t *testing.T, indexCfg *staking.IndexConfigs) {
 	data, err := contract.GetListCandidates(nil)
 	require.Equal(t, len(data.Candidates), 3)
 	require.NotNil(t, data)
+	// the candidates include the one without enough stake to be a validator
+	allCandidates, err := stakingCaller.GetCandidates(addr)
+	require.NoError(t, err)
+	require.Equal(t, data.Candidates, allCandidates)
 	// new validator is voted
 	ownerPk, _ := crypto.HexToECDSA(newCandidatePkHex)
 	authOpts = bind.NewKeyedTransactor(ownerPk)
@@ -173,6 +186,15 @@ func testGetValidatorsData(t *testing.T, indexCfg *staking.IndexConfigs) {
 	stakingCaller, err := be.GetStakingCaller(indexCfg)
 	require.NoError(t, err)
 
+	contractParams, err := stakingCaller.GetContractParams(addr)
+	require.NoError(t, err)
+	require.Equal(t, adminAddr, contractParams.Admin)
+	require.Equal(t, startBlock.Uint64(), contractParams.StartBlock.Uint64())
+	require.Equal(t, epoch, contractParams.EpochPeriod)
+	require.Equal(t, maxValidatorSize, contractParams.MaxValidatorSize)
+	require.Equal(t, minValidatorStake, contractParams.MinValidatorStake)
+	require.Equal(t, minVoteCap, contractParams.MinVoterCap)
+
 	validators, err := stakingCaller.GetValidators(addr)
 	require.NoError(t, err)
 	require.Equal(t, len(validators), 2)
@@ -224,6 +246,40 @@ func testGetValidatorsData(t *testing.T, indexCfg *staking.IndexConfigs) {
 	assert.Equal(t, voterStakes[newCandidate].VoterStakes[adminAddr], big.NewInt(30))
 }
 
+func TestCompareCallers(t *testing.T) {
+	var (
+		a, _       = common.NeutAddressStringToAddressCheck("NTkhwcpZULSbKURKqw3PYV5GEbhZFXjjBK")
+		b, _       = common.NeutAddressStringToAddressCheck("NZXRfVCDbp8yttymzTg1FZ3Z4c5eJiKPDk")
+		candidates = []common.Address{a, b}
+	)
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	require.NoError(t, err)
+	be := backends.NewSimulatedBackend(core.GenesisAlloc{
+		crypto.PubkeyToAddress(privateKey.PublicKey): core.GenesisAccount{
+			Balance: big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil),
+		},
+	}, gasLimit)
+	authOpts := bind.NewKeyedTransactor(privateKey)
+	authOpts.Nonce = big.NewInt(0)
+	addr, tx, _, err := staking_contracts.DeployStakingContracts(authOpts, be, candidates, candidates, big.NewInt(300000), common.Big0, big.NewInt(100), big.NewInt(20), big.NewInt(10), a)
+	require.NoError(t, err)
+	be.Commit()
+	assertTxSuccess(t, be, tx.Hash())
+
+	evmCaller, err := be.GetStakingCaller(nil)
+	require.NoError(t, err)
+	stateDBCaller, err := be.GetStakingCaller(staking.DefaultConfig)
+	require.NoError(t, err)
+	require.NoError(t, staking.CompareCallers(evmCaller, stateDBCaller, addr))
+
+	// a reordered candidate data struct is detected
+	cfg := *staking.DefaultConfig
+	cfg.CandidateDataStruct.Owner, cfg.CandidateDataStruct.TotalStake = cfg.CandidateDataStruct.TotalStake, cfg.CandidateDataStruct.Owner
+	stateDBCaller, err = be.GetStakingCaller(&cfg)
+	require.NoError(t, err)
+	require.Error(t, staking.CompareCallers(evmCaller, stateDBCaller, addr))
+}
+
 func assertTxSuccess(t *testing.T, be *backends.SimulatedBackend, txHash common.Hash) {
 	receipt, err := be.TransactionReceipt(context.Background(), txHash)
 	require.NoError(t, err)
@@ -170,6 +170,18 @@ func (c *stateDBStakingCaller) GetAdmin(scAddress common.Address) common.Address
 	return c.getAddress(scAddress, c.config.AdminLayout.slotHash())
 }
 
+// GetContractParams returns the parameters of the staking contract
+func (c *stateDBStakingCaller) GetContractParams(scAddress common.Address) (*ContractParams, error) {
+	return &ContractParams{
+		Admin:             c.GetAdmin(scAddress),
+		StartBlock:        c.GetStartBlock(scAddress),
+		EpochPeriod:       c.GetEpochPeriod(scAddress),
+		MaxValidatorSize:  c.GetMaxValidatorSize(scAddress),
+		MinValidatorStake: c.GetMinValidatorStake(scAddress),
+		MinVoterCap:       c.GetMinVoterCap(scAddress),
+	}, nil
+}
+
 // GetCandidateOwner returns current owner of a candidate
 func (c *stateDBStakingCaller) GetCandidateOwner(stakingContractAddr common.Address, candidate common.Address) common.Address {
 	loc := getMappingElementLoc(c.config.CandidateDataLayout.slotHash(), candidate.Hash())
@@ -16,7 +16,11 @@ func (layOut *LayOut) slotHash() common.Hash {
 	return common.BigToHash(new(big.Int).SetUint64(layOut.Slot))
 }
 
+//go:generate sh -c "go run mklayout.go ../../../consensus/staking_contracts/storage-layout.json > gen_index_configs.go.tmp && mv gen_index_configs.go.tmp gen_index_configs.go"
+
 // IndexConfigs represents the configuration index of state variables.
+// The default configuration is generated from the storage layout of the staking contract by mklayout.go,
+// LoadIndexConfigs builds it at runtime for other versions of the contract.
 type IndexConfigs struct {
 	WithdrawsStateLayout    LayOut //1
 	CandidateVotersLayout   LayOut //2
@@ -39,25 +43,6 @@ type CandidateDataStructIndex struct {
 	VotersStakes LayOut
 }
 
-// DefaultConfig represents he default configuration.
-var DefaultConfig = &IndexConfigs{
-	WithdrawsStateLayout:    NewLayOut(1, 0),
-	CandidateVotersLayout:   NewLayOut(2, 0),
-	CandidateDataLayout:     NewLayOut(3, 0),
-	CandidatesLayout:        NewLayOut(4, 0),
-	StartBlockLayout:        NewLayOut(5, 0),
-	EpochPeriodLayout:       NewLayOut(6, 0),
-	MaxValidatorSizeLayout:  NewLayOut(7, 0),
-	MinValidatorStakeLayout: NewLayOut(8, 0),
-	MinVoterCapLayout:       NewLayOut(9, 0),
-	AdminLayout:             NewLayOut(10, 0),
-	CandidateDataStruct: CandidateDataStructIndex{
-		TotalStake:   NewLayOut(1, 0),
-		Owner:        NewLayOut(2, 0),
-		VotersStakes: NewLayOut(3, 0),
-	},
-}
-
 // NewLayOut returns new instance of a LayOut
 func NewLayOut(slot uint64, offset uint16) LayOut {
 	return LayOut{Offset: offset, Slot: slot}
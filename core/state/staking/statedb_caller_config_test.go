@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/tidwall/gjson"
@@ -117,3 +118,26 @@ func TestDefaultConfig(t *testing.T) {
 	}
 
 }
+
+func TestLoadIndexConfigs(t *testing.T) {
+	cfg, err := staking.LoadIndexConfigsFile(storageLayoutPath, staking.DefaultContractName)
+	require.NoError(t, err)
+	require.Equal(t, staking.DefaultConfig, cfg)
+
+	// the storageLayout of the contract alone is accepted too
+	data, err := ioutil.ReadFile(storageLayoutPath)
+	require.NoError(t, err)
+	cfg, err = staking.LoadIndexConfigs([]byte(gjson.Get(string(data), gjsonPath).Raw), staking.DefaultContractName)
+	require.NoError(t, err)
+	require.Equal(t, staking.DefaultConfig, cfg)
+
+	_, err = staking.LoadIndexConfigs(data, "OtherContract")
+	require.Error(t, err)
+	_, err = staking.LoadIndexConfigs([]byte(`{"storage":[{"label":"candidates","offset":0,"slot":"4","type":"t_array(t_address)dyn_storage"}]}`), staking.DefaultContractName)
+	require.Error(t, err)
+	// the packed variables are not supported by the stateDB caller
+	packed := strings.Replace(string(data), `"label":"admin","offset":0`, `"label":"admin","offset":1`, 1)
+	require.NotEqual(t, string(data), packed)
+	_, err = staking.LoadIndexConfigs([]byte(packed), staking.DefaultContractName)
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package staking
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultContractName is the name of the staking contract in the storage layout output of solc
+const DefaultContractName = "NeuralChainStaking"
+
+// solcStorageLayout is the storageLayout output of solc for a contract
+type solcStorageLayout struct {
+	Storage []solcStorageItem          `json:"storage"`
+	Types   map[string]solcStorageType `json:"types"`
+}
+
+// solcStorageItem is a state variable of a contract or a member of a struct in the storageLayout output of solc
+type solcStorageItem struct {
+	Label  string `json:"label"`
+	Offset uint16 `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+// solcStorageType is a type in the storageLayout output of solc, the value of a mapping or the members of a struct
+type solcStorageType struct {
+	Encoding string            `json:"encoding"`
+	Label    string            `json:"label"`
+	Value    string            `json:"value"`
+	Members  []solcStorageItem `json:"members"`
+}
+
+// variables returns the layouts of the state variables of the staking contract by their labels
+func (cfg *IndexConfigs) variables() map[string]*LayOut {
+	return map[string]*LayOut{
+		"withdrawsState":    &cfg.WithdrawsStateLayout,
+		"candidateVoters":   &cfg.CandidateVotersLayout,
+		"candidateData":     &cfg.CandidateDataLayout,
+		"candidates":        &cfg.CandidatesLayout,
+		"startBlock":        &cfg.StartBlockLayout,
+		"epochPeriod":       &cfg.EpochPeriodLayout,
+		"maxValidatorSize":  &cfg.MaxValidatorSizeLayout,
+		"minValidatorStake": &cfg.MinValidatorStakeLayout,
+		"minVoterCap":       &cfg.MinVoterCapLayout,
+		"admin":             &cfg.AdminLayout,
+	}
+}
+
+// candidateDataMembers returns the layouts of the members of the CandidateData struct by their labels
+func (cfg *IndexConfigs) candidateDataMembers() map[string]*LayOut {
+	return map[string]*LayOut{
+		"owner":      &cfg.CandidateDataStruct.Owner,
+		"totalStake": &cfg.CandidateDataStruct.TotalStake,
+		"voterStake": &cfg.CandidateDataStruct.VotersStakes,
+	}
+}
+
+// LoadIndexConfigs builds the index configuration of the state variables of the staking contract from the storage
+// layout output of solc. The input is either the standard JSON output of solc including the storageLayout of the
+// contract with the given name, or the storageLayout of the contract itself.
+func LoadIndexConfigs(layoutJSON []byte, contractName string) (*IndexConfigs, error) {
+	var output struct {
+		Contracts map[string]map[string]struct {
+			StorageLayout *solcStorageLayout `json:"storageLayout"`
+		} `json:"contracts"`
+		solcStorageLayout
+	}
+	if err := json.Unmarshal(layoutJSON, &output); err != nil {
+		return nil, errors.Wrap(err, "invalid storage layout")
+	}
+	layout := &output.solcStorageLayout
+	if output.Contracts != nil {
+		layout = nil
+		for _, contracts := range output.Contracts {
+			if contract, ok := contracts[contractName]; ok && contract.StorageLayout != nil {
+				layout = contract.StorageLayout
+				break
+			}
+		}
+		if layout == nil {
+			return nil, fmt.Errorf("no storage layout of contract %s", contractName)
+		}
+	}
+
+	cfg := new(IndexConfigs)
+	variables := cfg.variables()
+	for _, item := range layout.Storage {
+		layOut, ok := variables[item.Label]
+		if !ok {
+			continue
+		}
+		if err := item.setLayOut(layOut); err != nil {
+			return nil, err
+		}
+		delete(variables, item.Label)
+
+		if layOut != &cfg.CandidateDataLayout {
+			continue
+		}
+		// the candidate data is a mapping to the CandidateData struct
+		dataType := layout.Types[item.Type]
+		if dataType.Encoding == "mapping" {
+			dataType = layout.Types[dataType.Value]
+		}
+		members := cfg.candidateDataMembers()
+		for _, member := range dataType.Members {
+			if memberLayOut, ok := members[member.Label]; ok {
+				if err := member.setLayOut(memberLayOut); err != nil {
+					return nil, err
+				}
+				delete(members, member.Label)
+			}
+		}
+		for label := range members {
+			return nil, fmt.Errorf("member %s of the candidate data not found in the storage layout", label)
+		}
+	}
+	for label := range variables {
+		return nil, fmt.Errorf("state variable %s not found in the storage layout", label)
+	}
+	return cfg, nil
+}
+
+// LoadIndexConfigsFile builds the index configuration of the state variables of the staking contract from a file
+// containing the storage layout output of solc, see LoadIndexConfigs.
+func LoadIndexConfigsFile(path string, contractName string) (*IndexConfigs, error) {
+	layoutJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadIndexConfigs(layoutJSON, contractName)
+}
+
+// setLayOut sets the slot and offset of the item to the layout.
+// The staking callers read whole slots, so the variables packed with others into a slot are not supported.
+func (item *solcStorageItem) setLayOut(layOut *LayOut) error {
+	slot, err := strconv.ParseUint(item.Slot, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid slot of %s", item.Label)
+	}
+	if item.Offset != 0 {
+		return fmt.Errorf("%s is packed at offset %d of slot %d", item.Label, item.Offset, slot)
+	}
+	*layOut = NewLayOut(slot, item.Offset)
+	return nil
+}
@@ -105,6 +105,21 @@ type Account struct {
 	CodeHash          []byte
 	OwnerAddress      *common.Address  `rlp:"nil"`
 	ProviderAddresses []common.Address `rlp:"nil"`
+	// Settings holds the optional settings of an enterprise contract, at most one. It is a tail field
+	// so that the encoding of the accounts without settings is unchanged. The settings are only set by
+	// the transactions of forks, so the accounts are encoded as before until then.
+	Settings []EnterpriseSettings `rlp:"tail"`
+}
+
+// EnterpriseSettings are the optional settings of an enterprise contract
+type EnterpriseSettings struct {
+	PendingOwner     *common.Address `rlp:"nil"` // The owner proposed by a TransferOwnership transaction until it accepts
+	ProviderBudgets  []GasBudget     // The gas the providers pay per epoch
+	SenderAllowances []GasBudget     // The gas the providers pay for the senders per epoch
+}
+
+func (e *EnterpriseSettings) empty() bool {
+	return e.PendingOwner == nil && len(e.ProviderBudgets) == 0 && len(e.SenderAllowances) == 0
 }
 
 // AccountWithoutProvider represent an account without provider
@@ -480,6 +495,68 @@ func (s *stateObject) RemoveProvider(providerAddress common.Address) error {
 	return nil
 }
 
+// settings returns a copy of the enterprise settings, the zero settings if there is none
+func (s *stateObject) settings() EnterpriseSettings {
+	if len(s.data.Settings) == 0 {
+		return EnterpriseSettings{}
+	}
+	settings := s.data.Settings[0]
+	settings.ProviderBudgets = append([]GasBudget(nil), settings.ProviderBudgets...)
+	settings.SenderAllowances = append([]GasBudget(nil), settings.SenderAllowances...)
+	return settings
+}
+
+func (s *stateObject) PendingOwnerAddress() *common.Address {
+	return s.settings().PendingOwner
+}
+
+// TransferOwnership assumes that the permission for transfer ownership here is valid
+func (s *stateObject) TransferOwnership(newOwner common.Address) {
+	settings := s.settings()
+	settings.PendingOwner = &newOwner
+	s.SetSettings(settings)
+}
+
+// AcceptOwnership makes the pending owner the owner of the contract
+func (s *stateObject) AcceptOwnership(from common.Address) error {
+	settings := s.settings()
+	if settings.PendingOwner == nil || *settings.PendingOwner != from {
+		return ErrOnlyPendingOwner
+	}
+	s.SetOwner(&from)
+	settings.PendingOwner = nil
+	s.SetSettings(settings)
+	return nil
+}
+
+func (s *stateObject) SetOwner(owner *common.Address) {
+	s.db.journal.append(ownerChange{
+		account: &s.address,
+		prev:    s.data.OwnerAddress,
+	})
+	s.setOwner(owner)
+}
+
+func (s *stateObject) setOwner(owner *common.Address) {
+	s.data.OwnerAddress = owner
+}
+
+func (s *stateObject) SetSettings(settings EnterpriseSettings) {
+	s.db.journal.append(settingsChange{
+		account: &s.address,
+		prev:    s.data.Settings,
+	})
+	if settings.empty() {
+		s.setSettings(nil)
+	} else {
+		s.setSettings([]EnterpriseSettings{settings})
+	}
+}
+
+func (s *stateObject) setSettings(settings []EnterpriseSettings) {
+	s.data.Settings = settings
+}
+
 func (s *stateObject) SetProvider(providerAddresses []common.Address) {
 	s.db.journal.append(providersChange{
 		account: &s.address,
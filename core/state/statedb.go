@@ -227,6 +227,32 @@ func (self *StateDB) GetOwner(addr common.Address) *common.Address {
 	return nil
 }
 
+// GetPendingOwner returns the owner proposed for an enterprise contract, which has not accepted the ownership yet
+func (self *StateDB) GetPendingOwner(addr common.Address) *common.Address {
+	if so := self.getStateObject(addr); so != nil {
+		return so.PendingOwnerAddress()
+	}
+	return nil
+}
+
+// GetProviderBudget returns the gas left at the epoch in the budget of a provider of an enterprise contract,
+// it returns false if the gas paid by the provider is not limited
+func (self *StateDB) GetProviderBudget(addr common.Address, provider common.Address, epoch uint64) (uint64, bool) {
+	if so := self.getStateObject(addr); so != nil {
+		return so.RemainingGas(provider, epoch, true)
+	}
+	return 0, false
+}
+
+// GetSenderAllowance returns the gas left at the epoch in the allowance of a sender of an enterprise contract,
+// it returns false if the gas paid for the sender is not limited
+func (self *StateDB) GetSenderAllowance(addr common.Address, sender common.Address, epoch uint64) (uint64, bool) {
+	if so := self.getStateObject(addr); so != nil {
+		return so.RemainingGas(sender, epoch, false)
+	}
+	return 0, false
+}
+
 // GetProviders returns providers of account
 func (self *StateDB) GetProviders(addr common.Address) []common.Address {
 	so := self.getStateObject(addr)
@@ -383,6 +409,65 @@ func (self *StateDB) RemoveProvider(addr common.Address, from common.Address, pr
 	return stateObject.RemoveProvider(providerAddress)
 }
 
+// TransferOwnership proposes a new owner for an enterprise contract, the current owner stays until the new owner accepts
+func (self *StateDB) TransferOwnership(addr common.Address, from common.Address, newOwner common.Address) error {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	stateObject.TransferOwnership(newOwner)
+	return nil
+}
+
+// SetProviderBudget sets the gas a provider of an enterprise contract pays per epoch, a budget of 0 removes the limit
+func (self *StateDB) SetProviderBudget(addr common.Address, from common.Address, provider common.Address, budget uint64) error {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	stateObject.SetGasLimit(provider, budget, true)
+	return nil
+}
+
+// SetSenderAllowance sets the gas the providers of an enterprise contract pay for a sender per epoch,
+// an allowance of 0 removes the limit
+func (self *StateDB) SetSenderAllowance(addr common.Address, from common.Address, sender common.Address, allowance uint64) error {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if err := stateObject.CheckOwner(from); err != nil {
+		return err
+	}
+	stateObject.SetGasLimit(sender, allowance, false)
+	return nil
+}
+
+// UseEnterpriseGas records the gas paid at the epoch by a provider of an enterprise contract for a sender
+func (self *StateDB) UseEnterpriseGas(addr common.Address, provider common.Address, sender common.Address, epoch uint64, gas uint64) {
+	if stateObject := self.getStateObject(addr); stateObject != nil {
+		stateObject.UseGas(provider, sender, epoch, gas)
+	}
+}
+
+// AcceptOwnership makes the pending owner of an enterprise contract its owner
+func (self *StateDB) AcceptOwnership(addr common.Address, from common.Address) error {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject == nil {
+		return ErrOwnerNotFound
+	}
+	if stateObject.OwnerAddress() == nil {
+		return ErrOwnerNotFound
+	}
+	return stateObject.AcceptOwnership(from)
+}
+
 // AddBalance adds amount to the account associated with addr.
 func (self *StateDB) AddBalance(addr common.Address, amount *big.Int) {
 	stateObject := self.GetOrNewStateObject(addr)
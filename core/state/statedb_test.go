@@ -35,6 +35,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/core/vm"
+	"github.com/lvbin2012/NeuralChain/rlp"
 )
 
 // Tests that updating a state trie does not leak any database writes prior to
@@ -497,3 +498,130 @@ func TestEVM_RemoveProvider(t *testing.T) {
 	require.NoError(t, statedb.RemoveProvider(contractAddr, ownerAddr, providerAddr))
 	require.Equal(t, len(statedb.GetProviders(contractAddr)), 0)
 }
+
+func TestStateDB_TransferOwnership(t *testing.T) {
+	var (
+		contractAddr = common.Address{1}
+		ownerAddr    = common.Address{2}
+		providerAddr = common.Address{3}
+		newOwnerAddr = common.Address{4}
+		addr         = common.Address{5}
+	)
+
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	statedb.CreateAccount(contractAddr, types.CreateAccountOption{OwnerAddress: &ownerAddr, ProviderAddress: &providerAddr})
+	root, _ := statedb.Commit(false)
+	statedb, _ = New(root, db)
+
+	require.Equal(t, ErrOwnerNotFound, statedb.TransferOwnership(addr, ownerAddr, newOwnerAddr))
+	require.Equal(t, ErrOnlyOwner, statedb.TransferOwnership(contractAddr, newOwnerAddr, newOwnerAddr))
+	require.Equal(t, ErrOnlyPendingOwner, statedb.AcceptOwnership(contractAddr, newOwnerAddr))
+
+	// the transfer is reverted with its snapshot
+	snapshot := statedb.Snapshot()
+	require.NoError(t, statedb.TransferOwnership(contractAddr, ownerAddr, addr))
+	require.Equal(t, &addr, statedb.GetPendingOwner(contractAddr))
+	statedb.RevertToSnapshot(snapshot)
+	require.Nil(t, statedb.GetPendingOwner(contractAddr))
+
+	require.NoError(t, statedb.TransferOwnership(contractAddr, ownerAddr, newOwnerAddr))
+	// the owner keeps the ownership until the new owner accepts it
+	require.Equal(t, &ownerAddr, statedb.GetOwner(contractAddr))
+	root, _ = statedb.Commit(false)
+	statedb, _ = New(root, db)
+	require.Equal(t, &newOwnerAddr, statedb.GetPendingOwner(contractAddr))
+	require.Equal(t, ErrOnlyPendingOwner, statedb.AcceptOwnership(contractAddr, ownerAddr))
+
+	require.NoError(t, statedb.AcceptOwnership(contractAddr, newOwnerAddr))
+	root, _ = statedb.Commit(false)
+	statedb, _ = New(root, db)
+	require.Equal(t, &newOwnerAddr, statedb.GetOwner(contractAddr))
+	require.Nil(t, statedb.GetPendingOwner(contractAddr))
+	require.Equal(t, []common.Address{providerAddr}, statedb.GetProviders(contractAddr))
+	require.Equal(t, ErrOnlyOwner, statedb.AddProvider(contractAddr, ownerAddr, addr))
+}
+
+func TestStateDB_GasBudgets(t *testing.T) {
+	var (
+		contractAddr = common.Address{1}
+		ownerAddr    = common.Address{2}
+		providerAddr = common.Address{3}
+		senderAddr   = common.Address{4}
+	)
+
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	statedb.CreateAccount(contractAddr, types.CreateAccountOption{OwnerAddress: &ownerAddr, ProviderAddress: &providerAddr})
+
+	_, limited := statedb.GetProviderBudget(contractAddr, providerAddr, 0)
+	require.False(t, limited)
+	require.Equal(t, ErrOnlyOwner, statedb.SetProviderBudget(contractAddr, providerAddr, providerAddr, 100))
+
+	require.NoError(t, statedb.SetProviderBudget(contractAddr, ownerAddr, providerAddr, 100))
+	require.NoError(t, statedb.SetSenderAllowance(contractAddr, ownerAddr, senderAddr, 50))
+	statedb.UseEnterpriseGas(contractAddr, providerAddr, senderAddr, 1, 30)
+	root, _ := statedb.Commit(false)
+	statedb, _ = New(root, db)
+
+	remaining, limited := statedb.GetProviderBudget(contractAddr, providerAddr, 1)
+	require.True(t, limited)
+	require.Equal(t, uint64(70), remaining)
+	remaining, _ = statedb.GetSenderAllowance(contractAddr, senderAddr, 1)
+	require.Equal(t, uint64(20), remaining)
+	// the budgets are renewed every epoch
+	remaining, _ = statedb.GetProviderBudget(contractAddr, providerAddr, 2)
+	require.Equal(t, uint64(100), remaining)
+
+	// the used gas is reverted with its snapshot
+	snapshot := statedb.Snapshot()
+	statedb.UseEnterpriseGas(contractAddr, providerAddr, senderAddr, 1, 40)
+	remaining, _ = statedb.GetSenderAllowance(contractAddr, senderAddr, 1)
+	require.Equal(t, uint64(0), remaining)
+	statedb.RevertToSnapshot(snapshot)
+	remaining, _ = statedb.GetSenderAllowance(contractAddr, senderAddr, 1)
+	require.Equal(t, uint64(20), remaining)
+
+	// lowering a limit keeps the used gas, a limit of 0 removes it
+	require.NoError(t, statedb.SetProviderBudget(contractAddr, ownerAddr, providerAddr, 40))
+	remaining, _ = statedb.GetProviderBudget(contractAddr, providerAddr, 1)
+	require.Equal(t, uint64(10), remaining)
+	require.NoError(t, statedb.SetProviderBudget(contractAddr, ownerAddr, providerAddr, 0))
+	_, limited = statedb.GetProviderBudget(contractAddr, providerAddr, 1)
+	require.False(t, limited)
+	require.NoError(t, statedb.SetSenderAllowance(contractAddr, ownerAddr, senderAddr, 0))
+	root, _ = statedb.Commit(false)
+	statedb, _ = New(root, db)
+	require.Empty(t, statedb.getStateObject(contractAddr).data.Settings)
+}
+
+// TestAccountEncodingWithoutSettings tests that the accounts without enterprise settings are encoded
+// as before the settings were introduced, so that the state roots are unchanged.
+func TestAccountEncodingWithoutSettings(t *testing.T) {
+	owner := common.Address{1}
+	account := Account{
+		Nonce:             1,
+		Balance:           big.NewInt(2),
+		CodeHash:          emptyCodeHash,
+		OwnerAddress:      &owner,
+		ProviderAddresses: []common.Address{{2}},
+	}
+	legacy := struct {
+		Nonce             uint64
+		Balance           *big.Int
+		Root              common.Hash
+		CodeHash          []byte
+		OwnerAddress      *common.Address  `rlp:"nil"`
+		ProviderAddresses []common.Address `rlp:"nil"`
+	}{account.Nonce, account.Balance, account.Root, account.CodeHash, account.OwnerAddress, account.ProviderAddresses}
+
+	enc, err := rlp.EncodeToBytes(&account)
+	require.NoError(t, err)
+	legacyEnc, err := rlp.EncodeToBytes(&legacy)
+	require.NoError(t, err)
+	require.Equal(t, legacyEnc, enc)
+
+	var decoded Account
+	require.NoError(t, rlp.DecodeBytes(legacyEnc, &decoded))
+	require.Empty(t, decoded.Settings)
+}
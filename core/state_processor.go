@@ -17,6 +17,8 @@
 package core
 
 import (
+	"math/big"
+
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/core/state"
@@ -81,12 +83,27 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
+	var baseFee *big.Int
+	if config.IsFeeMarket(header.Number) {
+		parent := bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if parent == nil {
+			return nil, 0, consensus.ErrUnknownAncestor
+		}
+		baseFee = CalcBaseFee(config, minGasPrice(config, bc, parent), parent)
+	}
+	return applyTransaction(config, bc, author, gp, statedb, header, baseFee, tx, usedGas, cfg)
+}
+
+// applyTransaction applies the transaction like ApplyTransaction, with the base fee of
+// the block already known.
+func applyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, baseFee *big.Int, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
 		return nil, 0, err
 	}
 	// Create a new context to be used in the EVM environment
 	context := NewEVMContext(msg, header, bc, author)
+	context.BaseFee = baseFee
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
@@ -114,6 +114,19 @@ func IntrinsicGas(data []byte, contractCreation bool) (uint64, error) {
 	return gas, nil
 }
 
+// isTxTypeActive returns whether the transactions of the type are valid in the block of the number,
+// the types introduced by a fork are not before it.
+func isTxTypeActive(config *params.ChainConfig, number *big.Int, txType types.TransactionType) bool {
+	switch txType {
+	case types.TransferOwnershipTxType, types.AcceptOwnershipTxType:
+		return config.IsOwnershipTransfer(number)
+	case types.SetProviderBudgetTxType, types.SetSenderAllowanceTxType:
+		return config.IsGasBudget(number)
+	default:
+		return true
+	}
+}
+
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
 	return &StateTransition{
@@ -180,6 +193,19 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	if st.evm.BaseFee != nil && st.gasPrice.Cmp(st.evm.BaseFee) < 0 {
+		return ErrGasPriceBelowBaseFee
+	}
+	if !isTxTypeActive(st.evm.ChainConfig(), st.evm.BlockNumber, st.msg.TxType()) {
+		return ErrTxTypeNotActive
+	}
+	// The providers of an enterprise contract pay the gas within the limits set by its owner
+	if st.msg.HasProviderSignature() && st.msg.To() != nil && st.evm.ChainConfig().IsGasBudget(st.evm.BlockNumber) {
+		epoch := GasBudgetEpoch(st.evm.ChainConfig(), st.evm.BlockNumber)
+		if err := checkGasBudgets(st.state, *st.msg.To(), st.msg.GasPayer(), st.msg.From(), epoch, st.msg.Gas()); err != nil {
+			return err
+		}
+	}
 	//TODO: this should check if the address from provider list
 	return st.buyGas()
 }
@@ -221,7 +247,14 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		if msg.Provider() != nil {
 			option.ProviderAddress = msg.Provider()
 		}
-		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value, option)
+		var contractAddr common.Address
+		ret, contractAddr, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value, option)
+		if vmerr == nil && option.OwnerAddress != nil {
+			st.addLog(contractAddr, types.OwnershipTransferredTopic, types.AddressTopic(common.Address{}), types.AddressTopic(*option.OwnerAddress))
+		}
+		if vmerr == nil && option.ProviderAddress != nil {
+			st.addLog(contractAddr, types.ProviderAddedTopic, types.AddressTopic(*option.ProviderAddress))
+		}
 	case msg.TxType() == types.AddProviderTxType || msg.TxType() == types.RemoveProviderTxType:
 		var (
 			msgData types.ModifyProvidersMsg
@@ -231,10 +264,52 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			return nil, 0, false, errors.New("msg should be type ModifyProvidersMsg")
 		}
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		// adding a provider twice or removing a missing one succeeds without a change, thus without a log
+		var wasProvider bool
+		for _, provider := range st.state.GetProviders(st.to()) {
+			if provider == msgData.Provider {
+				wasProvider = true
+				break
+			}
+		}
 		if msg.TxType() == types.AddProviderTxType {
 			vmerr = st.state.AddProvider(st.to(), msg.From(), msgData.Provider)
+			if vmerr == nil && !wasProvider {
+				st.addLog(st.to(), types.ProviderAddedTopic, types.AddressTopic(msgData.Provider))
+			}
 		} else {
 			vmerr = st.state.RemoveProvider(st.to(), msg.From(), msgData.Provider)
+			if vmerr == nil && wasProvider {
+				st.addLog(st.to(), types.ProviderRemovedTopic, types.AddressTopic(msgData.Provider))
+			}
+		}
+	case msg.TxType() == types.TransferOwnershipTxType:
+		msgData, ok := st.msg.ExtraData().(types.TransferOwnershipMsg)
+		if !ok { // this should never to be happened
+			return nil, 0, false, errors.New("msg should be type TransferOwnershipMsg")
+		}
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		vmerr = st.state.TransferOwnership(st.to(), msg.From(), msgData.NewOwner)
+		if vmerr == nil {
+			st.addLog(st.to(), types.OwnershipTransferRequestedTopic, types.AddressTopic(msg.From()), types.AddressTopic(msgData.NewOwner))
+		}
+	case msg.TxType() == types.AcceptOwnershipTxType:
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		previousOwner := st.state.GetOwner(st.to())
+		vmerr = st.state.AcceptOwnership(st.to(), msg.From())
+		if vmerr == nil && previousOwner != nil {
+			st.addLog(st.to(), types.OwnershipTransferredTopic, types.AddressTopic(*previousOwner), types.AddressTopic(msg.From()))
+		}
+	case msg.TxType() == types.SetProviderBudgetTxType || msg.TxType() == types.SetSenderAllowanceTxType:
+		msgData, ok := st.msg.ExtraData().(types.GasLimitMsg)
+		if !ok { // this should never to be happened
+			return nil, 0, false, errors.New("msg should be type GasLimitMsg")
+		}
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		if msg.TxType() == types.SetProviderBudgetTxType {
+			vmerr = st.state.SetProviderBudget(st.to(), msg.From(), msgData.Address, msgData.Limit)
+		} else {
+			vmerr = st.state.SetSenderAllowance(st.to(), msg.From(), msgData.Address, msgData.Limit)
 		}
 	default:
 		// Increment the nonce for the next transaction
@@ -251,8 +326,16 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		}
 	}
 	st.refundGas()
-	// tx fee is now shared between voter of staking
+	if msg.HasProviderSignature() && msg.To() != nil && st.evm.ChainConfig().IsGasBudget(st.evm.BlockNumber) {
+		epoch := GasBudgetEpoch(st.evm.ChainConfig(), st.evm.BlockNumber)
+		st.state.UseEnterpriseGas(*msg.To(), msg.GasPayer(), msg.From(), epoch, st.gasUsed())
+	}
+	// tx fee is now shared between voter of staking, except the tip above the base fee which goes to the proposer
 	//st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+	if st.evm.BaseFee != nil {
+		tip := new(big.Int).Sub(st.gasPrice, st.evm.BaseFee)
+		st.state.AddBalance(st.evm.Coinbase, tip.Mul(tip, new(big.Int).SetUint64(st.gasUsed())))
+	}
 
 	return ret, st.gasUsed(), vmerr != nil, err
 }
@@ -274,6 +357,20 @@ func (st *StateTransition) refundGas() {
 	st.gp.AddGas(st.gas)
 }
 
+// addLog emits a log of the contract with the topics, from the EnterpriseLogs fork on.
+func (st *StateTransition) addLog(contract common.Address, topics ...common.Hash) {
+	if !st.evm.ChainConfig().IsEnterpriseLogs(st.evm.BlockNumber) {
+		return
+	}
+	st.state.AddLog(&types.Log{
+		Address: contract,
+		Topics:  topics,
+		// This is a non-consensus field, but assigned here because
+		// core/state doesn't know the current block number.
+		BlockNumber: st.evm.BlockNumber.Uint64(),
+	})
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -74,6 +75,14 @@ var (
 	// is higher than the balance of the provider's account (fee's paid by provider)
 	ErrProviderInsufficientFunds = errors.New("provider has insufficient funds for gas * price")
 
+	// ErrProviderOvercommitted is returned if the fees of the transactions sponsored by
+	// a provider in the pool, including the new one, are higher than its balance
+	ErrProviderOvercommitted = errors.New("provider has insufficient funds for its pooled transactions")
+
+	// ErrProviderSlotsExceeded is returned if a provider already sponsors the maximum
+	// number of transactions permitted in the pool
+	ErrProviderSlotsExceeded = errors.New("provider exceeds its sponsored transaction slots")
+
 	// ErrSenderInsufficientFunds is returned if the transaction value
 	// is higher than the balance of the user's account (fee's paid by provider)
 	ErrSenderInsufficientFunds = errors.New("sender has insufficient funds for value")
@@ -109,6 +118,15 @@ var (
 
 	// ErrInvalidAddressToModifyProviders is returned when modifying providers transaction is sent to non-enterprise contract
 	ErrInvalidAddressToModifyProviders = errors.New("only enterprise contract can modify providers")
+
+	// ErrOnlyOwnerTransferOwnership is returned if transferring ownership transaction is not from owner of enterprise contract
+	ErrOnlyOwnerTransferOwnership = errors.New("only owner can transfer ownership of enterprise contract")
+
+	// ErrOnlyPendingOwner is returned if accepting ownership transaction is not from pending owner of enterprise contract
+	ErrOnlyPendingOwner = errors.New("only pending owner can accept ownership of enterprise contract")
+
+	// ErrInvalidAddressToTransferOwnership is returned when ownership transaction is sent to non-enterprise contract
+	ErrInvalidAddressToTransferOwnership = errors.New("only enterprise contract can transfer ownership")
 )
 
 var (
@@ -133,6 +151,7 @@ var (
 	validMeter         = metrics.NewRegisteredMeter("txpool/valid", nil)
 	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
+	overcommitMeter    = metrics.NewRegisteredMeter("txpool/overcommitted", nil) // Sponsored transactions dropped due to over-committed providers
 
 	pendingCounter = metrics.NewRegisteredCounter("txpool/pending", nil)
 	queuedCounter  = metrics.NewRegisteredCounter("txpool/queued", nil)
@@ -155,6 +174,7 @@ type blockChain interface {
 	CurrentBlock() *types.Block
 	GetBlock(hash common.Hash, number uint64) *types.Block
 	StateAt(root common.Hash) (*state.StateDB, error)
+	MinGasPrice(parent *types.Header) *big.Int
 
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
@@ -169,10 +189,11 @@ type TxPoolConfig struct {
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
-	AccountSlots uint64 // Number of executable transaction slots guaranteed per account
-	GlobalSlots  uint64 // Maximum number of executable transaction slots for all accounts
-	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
-	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
+	AccountSlots  uint64 // Number of executable transaction slots guaranteed per account
+	GlobalSlots   uint64 // Maximum number of executable transaction slots for all accounts
+	AccountQueue  uint64 // Maximum number of non-executable transaction slots permitted per account
+	GlobalQueue   uint64 // Maximum number of non-executable transaction slots for all accounts
+	ProviderSlots uint64 // Maximum number of executable and non-executable transaction slots sponsored by a provider
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 }
@@ -186,10 +207,11 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	PriceLimit: 1,
 	PriceBump:  10,
 
-	AccountSlots: 16,
-	GlobalSlots:  4096,
-	AccountQueue: 64,
-	GlobalQueue:  1024,
+	AccountSlots:  16,
+	GlobalSlots:   4096,
+	AccountQueue:  64,
+	GlobalQueue:   1024,
+	ProviderSlots: 1024,
 
 	Lifetime: 3 * time.Hour,
 }
@@ -226,6 +248,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool global queue", "provided", conf.GlobalQueue, "updated", DefaultTxPoolConfig.GlobalQueue)
 		conf.GlobalQueue = DefaultTxPoolConfig.GlobalQueue
 	}
+	if conf.ProviderSlots < 1 {
+		log.Warn("Sanitizing invalid txpool provider slots", "provided", conf.ProviderSlots, "updated", DefaultTxPoolConfig.ProviderSlots)
+		conf.ProviderSlots = DefaultTxPoolConfig.ProviderSlots
+	}
 	if conf.Lifetime < 1 {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
@@ -255,6 +281,10 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
+	feeMarket     bool                // Whether the pending block prices the gas by its base fee
+	minGasPrice   *big.Int            // Minimum gas price of the pending block
+	budgetEpoch   uint64              // The epoch of the pending block for the gas budgets of the providers
+	pendingNumber *big.Int            // The number of the pending block
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -262,7 +292,7 @@ type TxPool struct {
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
-	all     *txLookup                    // All transactions to allow lookups
+	all     *txLookup                    // All transactions to allow lookups, with the sponsorships of the providers
 	priced  *txPricedList                // All transactions sorted by price
 
 	wg sync.WaitGroup // for shutdown sync
@@ -283,7 +313,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
-		all:         newTxLookup(),
+		all:         newTxLookup(types.NewOmahaSigner(chainconfig.ChainID)),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 	}
@@ -481,6 +511,10 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.pendingNumber = new(big.Int).Add(newHead.Number, common.Big1)
+	pool.feeMarket = pool.chainconfig.IsFeeMarket(pool.pendingNumber)
+	pool.minGasPrice = pool.chain.MinGasPrice(newHead)
+	pool.budgetEpoch = GasBudgetEpoch(pool.chainconfig, pool.pendingNumber)
 
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
@@ -501,6 +535,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Check the queue and move transactions over to the pending if possible
 	// or remove those that have become invalid
 	pool.promoteExecutables(nil)
+
+	// Drop the sponsored transactions the providers can't pay for anymore
+	pool.evictOvercommitted()
 }
 
 // Stop terminates the transaction pool.
@@ -654,15 +691,27 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 	}
 	from := txMsg.From()
 
-	//Validate gasPrice of tx must be as the same as gasPrice of chainConfig
-	if tx.GasPrice().Cmp(pool.chainconfig.GasPrice) != 0 {
+	// Before the fee market, the gasPrice of tx must be the minimum gas price of the pending block.
+	// Afterwards, it must cover the minimal base fee, that minimum gas price. Transactions below the
+	// base fee of the pending block are kept until the base fee drops.
+	if pool.feeMarket {
+		if tx.GasPrice().Cmp(pool.minGasPrice) < 0 {
+			return ErrGasPriceBelowBaseFee
+		}
+	} else if tx.GasPrice().Cmp(pool.minGasPrice) != 0 {
 		return ErrInvalidGasPrice
 	}
 
+	// The transactions of the types introduced by a fork are rejected until the pending block is forked
+	if !isTxTypeActive(pool.chainconfig, pool.pendingNumber, txMsg.TxType()) {
+		return ErrTxTypeNotActive
+	}
+
 	// Check permission to execute transaction to enterprise contract
 	switch {
 	case txMsg.To() == nil: // nothing need to check
-	case txMsg.TxType() == types.AddProviderTxType || txMsg.TxType() == types.RemoveProviderTxType:
+	case txMsg.TxType() == types.AddProviderTxType || txMsg.TxType() == types.RemoveProviderTxType,
+		txMsg.TxType() == types.SetProviderBudgetTxType || txMsg.TxType() == types.SetSenderAllowanceTxType:
 		owner := pool.currentState.GetOwner(*txMsg.To())
 		// if this is not an enterprise contract, return error
 		if owner == nil {
@@ -671,6 +720,22 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 		if *owner != txMsg.From() {
 			return ErrOnlyOwner
 		}
+	case txMsg.TxType() == types.TransferOwnershipTxType:
+		owner := pool.currentState.GetOwner(*txMsg.To())
+		if owner == nil {
+			return ErrInvalidAddressToTransferOwnership
+		}
+		if *owner != txMsg.From() {
+			return ErrOnlyOwnerTransferOwnership
+		}
+	case txMsg.TxType() == types.AcceptOwnershipTxType:
+		if pool.currentState.GetOwner(*txMsg.To()) == nil {
+			return ErrInvalidAddressToTransferOwnership
+		}
+		pendingOwner := pool.currentState.GetPendingOwner(*txMsg.To())
+		if pendingOwner == nil || *pendingOwner != txMsg.From() {
+			return ErrOnlyPendingOwner
+		}
 	default:
 		owner := pool.currentState.GetOwner(*txMsg.To())
 		// if this is not an enterprise contract, there must be no provider signature
@@ -713,6 +778,13 @@ func (pool *TxPool) ValidateTx(tx *types.Transaction, local bool) error {
 		if pool.currentState.GetBalance(txMsg.GasPayer()).Cmp(tx.TransactionFee()) < 0 {
 			return ErrProviderInsufficientFunds
 		}
+
+		// Check the gas budget of the provider and the gas allowance of the sender
+		if pool.chainconfig.IsGasBudget(pool.pendingNumber) {
+			if err := checkGasBudgets(pool.currentState, *txMsg.To(), txMsg.GasPayer(), from, pool.budgetEpoch, tx.Gas()); err != nil {
+				return err
+			}
+		}
 	} else {
 		// Sender pays transaction fee, check sender's balance for tx costs
 		// cost == V + GP * GL
@@ -752,6 +824,12 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	// If the transaction is sponsored, its provider must afford it along the ones it already sponsors
+	if err := pool.checkSponsorship(tx); err != nil {
+		log.Trace("Discarding over-committed sponsored transaction", "hash", hash, "err", err)
+		invalidTxMeter.Mark(1)
+		return false, err
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
 		//discard new transaction when transaction pool is full
@@ -787,6 +865,91 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	return replace, nil
 }
 
+// checkSponsorship checks that the provider of a sponsored transaction has a free slot and the funds to pay for
+// the fees of all the transactions it sponsors in the pool, the new one included. A queued transaction replaced
+// by the new one is not accounted for.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) checkSponsorship(tx *types.Transaction) error {
+	provider := tx.SignedProvider(pool.signer)
+	if provider == nil {
+		return nil
+	}
+	slots, fees := pool.all.Sponsorship(*provider)
+
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	if list := pool.queue[from]; list != nil {
+		if old := list.txs.Get(tx.Nonce()); old != nil {
+			if oldProvider := old.SignedProvider(pool.signer); oldProvider != nil && *oldProvider == *provider {
+				slots--
+				fees.Sub(fees, old.TransactionFee())
+			}
+		}
+	}
+	if uint64(slots) >= pool.config.ProviderSlots {
+		return ErrProviderSlotsExceeded
+	}
+	if fees.Add(fees, tx.TransactionFee()).Cmp(pool.currentState.GetBalance(*provider)) > 0 {
+		return ErrProviderOvercommitted
+	}
+	return nil
+}
+
+// evictOvercommitted drops the sponsored transactions of the providers exceeding their slots or whose balance
+// doesn't cover the fees of all the transactions they sponsor anymore. The queued transactions are dropped before
+// the pending ones, starting from the highest nonces of the senders with the most sponsored transactions.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) evictOvercommitted() {
+	for _, provider := range pool.all.Providers() {
+		balance := pool.currentState.GetBalance(provider)
+		for {
+			slots, fees := pool.all.Sponsorship(provider)
+			if uint64(slots) <= pool.config.ProviderSlots && fees.Cmp(balance) <= 0 {
+				break
+			}
+			victim := pool.lastSponsored(provider, pool.queue)
+			if victim == nil {
+				victim = pool.lastSponsored(provider, pool.pending)
+			}
+			if victim == nil {
+				log.Error("Inconsistent provider sponsorship", "provider", provider, "slots", slots, "fees", fees)
+				break
+			}
+			log.Trace("Removed over-committed sponsored transaction", "hash", victim.Hash(), "provider", provider)
+			pool.removeTx(victim.Hash(), true)
+			overcommitMeter.Mark(1)
+		}
+	}
+}
+
+// lastSponsored returns the transaction with the highest nonce sponsored by the provider of the sender with
+// the most sponsored transactions in the lists, or nil if the provider sponsors none of them.
+func (pool *TxPool) lastSponsored(provider common.Address, lists map[common.Address]*txList) *types.Transaction {
+	var (
+		last      *types.Transaction
+		lastFrom  common.Address
+		lastCount int
+	)
+	for from, list := range lists {
+		var (
+			sponsored *types.Transaction
+			count     int
+		)
+		for _, tx := range list.Flatten() {
+			if p := tx.SignedProvider(pool.signer); p != nil && *p == provider {
+				sponsored = tx
+				count++
+			}
+		}
+		// ties are broken by the sender address to keep the eviction deterministic
+		if count > lastCount || (count > 0 && count == lastCount && bytes.Compare(from.Bytes(), lastFrom.Bytes()) < 0) {
+			last, lastFrom, lastCount = sponsored, from, count
+		}
+	}
+	return last
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -1400,15 +1563,28 @@ func (as *accountSet) flatten() []common.Address {
 // internal mechanisms. The sole purpose of the type is to permit out-of-bound
 // peeking into the pool in TxPool.Get without having to acquire the widely scoped
 // TxPool.mu mutex.
+//
+// The lookup also accounts for the transactions sponsored by each provider, as all
+// the transactions enter and leave the pool through it.
 type txLookup struct {
-	all  map[common.Hash]*types.Transaction
-	lock sync.RWMutex
+	all       map[common.Hash]*types.Transaction
+	sponsored map[common.Address]*sponsorship // Pooled transactions sponsored by each provider
+	signer    types.Signer                    // Signer to derive the providers of the transactions
+	lock      sync.RWMutex
+}
+
+// sponsorship is the number of pooled transactions sponsored by a provider and the sum of their fees.
+type sponsorship struct {
+	slots int
+	fees  *big.Int
 }
 
 // newTxLookup returns a new txLookup structure.
-func newTxLookup() *txLookup {
+func newTxLookup(signer types.Signer) *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:       make(map[common.Hash]*types.Transaction),
+		sponsored: make(map[common.Address]*sponsorship),
+		signer:    signer,
 	}
 }
 
@@ -1445,7 +1621,20 @@ func (t *txLookup) Add(tx *types.Transaction) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.all[tx.Hash()] = tx
+	hash := tx.Hash()
+	if _, ok := t.all[hash]; ok {
+		return
+	}
+	t.all[hash] = tx
+	if provider := tx.SignedProvider(t.signer); provider != nil {
+		s := t.sponsored[*provider]
+		if s == nil {
+			s = &sponsorship{fees: new(big.Int)}
+			t.sponsored[*provider] = s
+		}
+		s.slots++
+		s.fees.Add(s.fees, tx.TransactionFee())
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1453,5 +1642,40 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	tx, ok := t.all[hash]
+	if !ok {
+		return
+	}
 	delete(t.all, hash)
+	if provider := tx.SignedProvider(t.signer); provider != nil {
+		s := t.sponsored[*provider]
+		if s.slots--; s.slots == 0 {
+			delete(t.sponsored, *provider)
+		} else {
+			s.fees.Sub(s.fees, tx.TransactionFee())
+		}
+	}
+}
+
+// Sponsorship returns the number of transactions sponsored by the provider and the sum of their fees.
+func (t *txLookup) Sponsorship(provider common.Address) (int, *big.Int) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if s := t.sponsored[provider]; s != nil {
+		return s.slots, new(big.Int).Set(s.fees)
+	}
+	return 0, new(big.Int)
+}
+
+// Providers returns the providers sponsoring transactions in the lookup.
+func (t *txLookup) Providers() []common.Address {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	providers := make([]common.Address, 0, len(t.sponsored))
+	for provider := range t.sponsored {
+		providers = append(providers, provider)
+	}
+	return providers
 }
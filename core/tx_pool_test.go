@@ -64,6 +64,10 @@ func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
 	return bc.statedb, nil
 }
 
+func (bc *testBlockChain) MinGasPrice(*types.Header) *big.Int {
+	return big.NewInt(params.GasPriceConfig)
+}
+
 func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
 	return bc.chainHeadFeed.Subscribe(ch)
 }
@@ -258,6 +262,222 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that the gas price of a transaction may exceed the gas price of the chain
+// config once the fee market is active, but not go below it.
+func TestFeeMarketTransactionGasPrice(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+	config := *params.TestChainConfig
+	config.FeeMarketBlock = common.Big1
+
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(from, big.NewInt(0xffffffffffffff))
+
+	tx := pricedTransaction(0, 100000, big.NewInt(params.GasPriceConfig-1), key)
+	if err := pool.AddRemote(tx); err != ErrGasPriceBelowBaseFee {
+		t.Error("expected", ErrGasPriceBelowBaseFee, "got", err)
+	}
+	tx = pricedTransaction(0, 100000, big.NewInt(2*params.GasPriceConfig), key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected no error, got", err)
+	}
+}
+
+func TestOwnershipTransactions(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+	config := *params.TestChainConfig
+	config.OwnershipTransferBlock = common.Big2
+
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	var (
+		ownerKey, _    = crypto.GenerateKey()
+		newOwnerKey, _ = crypto.GenerateKey()
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		newOwner       = crypto.PubkeyToAddress(newOwnerKey.PublicKey)
+		contract       = common.Address{0xe}
+		gasPrice       = big.NewInt(params.GasPriceConfig)
+	)
+	pool.currentState.AddBalance(owner, big.NewInt(0xffffffffffffff))
+	pool.currentState.AddBalance(newOwner, big.NewInt(0xffffffffffffff))
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner})
+
+	newTx := func(tx *types.Transaction, err error) *types.Transaction {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	// the transactions are rejected until the pending block is forked
+	tx, _ := types.SignTx(newTx(types.NewTransferOwnershipTransaction(0, contract, 100000, gasPrice, newOwner)), types.BaseSigner{}, ownerKey)
+	if err := pool.AddRemote(tx); err != ErrTxTypeNotActive {
+		t.Error("expected", ErrTxTypeNotActive, "got", err)
+	}
+	pool.pendingNumber = common.Big2
+
+	tx, _ = types.SignTx(newTx(types.NewTransferOwnershipTransaction(0, common.Address{0xf}, 100000, gasPrice, newOwner)), types.BaseSigner{}, ownerKey)
+	if err := pool.AddRemote(tx); err != ErrInvalidAddressToTransferOwnership {
+		t.Error("expected", ErrInvalidAddressToTransferOwnership, "got", err)
+	}
+	tx, _ = types.SignTx(newTx(types.NewTransferOwnershipTransaction(0, contract, 100000, gasPrice, newOwner)), types.BaseSigner{}, newOwnerKey)
+	if err := pool.AddRemote(tx); err != ErrOnlyOwnerTransferOwnership {
+		t.Error("expected", ErrOnlyOwnerTransferOwnership, "got", err)
+	}
+	tx, _ = types.SignTx(newTx(types.NewAcceptOwnershipTransaction(0, contract, 100000, gasPrice)), types.BaseSigner{}, newOwnerKey)
+	if err := pool.AddRemote(tx); err != ErrOnlyPendingOwner {
+		t.Error("expected", ErrOnlyPendingOwner, "got", err)
+	}
+	tx, _ = types.SignTx(newTx(types.NewTransferOwnershipTransaction(0, contract, 100000, gasPrice, newOwner)), types.BaseSigner{}, ownerKey)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected no error, got", err)
+	}
+
+	pool.currentState.TransferOwnership(contract, owner, newOwner)
+	tx, _ = types.SignTx(newTx(types.NewAcceptOwnershipTransaction(0, contract, 100000, gasPrice)), types.BaseSigner{}, newOwnerKey)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected no error, got", err)
+	}
+}
+
+func TestTransactionGasBudgets(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+	config := *params.TestChainConfig
+	config.GasBudgetBlock = common.Big2
+
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	var (
+		senderKey, _   = crypto.GenerateKey()
+		providerKey, _ = crypto.GenerateKey()
+		ownerKey, _    = crypto.GenerateKey()
+		sender         = crypto.PubkeyToAddress(senderKey.PublicKey)
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		owner          = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		contract       = common.Address{0xe}
+	)
+	pool.currentState.AddBalance(owner, big.NewInt(0xffffffffffffff))
+	pool.currentState.AddBalance(provider, big.NewInt(0xffffffffffffff))
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &owner, ProviderAddress: &provider})
+
+	// the transactions setting the limits are rejected until the pending block is forked
+	limitTx, err := types.NewSetGasLimitTransaction(0, contract, params.TxGas, big.NewInt(params.GasPriceConfig), provider, 50000, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limitTx, _ = types.SignTx(limitTx, types.BaseSigner{}, ownerKey)
+	if err := pool.AddRemote(limitTx); err != ErrTxTypeNotActive {
+		t.Error("expected", ErrTxTypeNotActive, "got", err)
+	}
+	pool.pendingNumber = common.Big2
+	if err := pool.AddRemote(limitTx); err != nil {
+		t.Error("expected no error, got", err)
+	}
+
+	providerTx := func(gas uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(0, contract, common.Big0, gas, big.NewInt(params.GasPriceConfig), nil), types.BaseSigner{}, senderKey)
+		tx, _ = types.ProviderSignTx(tx, types.BaseSigner{}, providerKey)
+		return tx
+	}
+	pool.currentState.SetProviderBudget(contract, owner, provider, 50000)
+	if err := pool.AddRemote(providerTx(50001)); err != ErrProviderBudgetExceeded {
+		t.Error("expected", ErrProviderBudgetExceeded, "got", err)
+	}
+	pool.currentState.SetSenderAllowance(contract, owner, sender, 30000)
+	if err := pool.AddRemote(providerTx(30001)); err != ErrSenderAllowanceExceeded {
+		t.Error("expected", ErrSenderAllowanceExceeded, "got", err)
+	}
+	if err := pool.AddRemote(providerTx(30000)); err != nil {
+		t.Error("expected no error, got", err)
+	}
+}
+
+// Tests that the transactions sponsored by a provider are limited by its balance and slots, and that the
+// ones it can't afford anymore are evicted.
+func TestTransactionProviderSponsorship(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupTxPool()
+	defer pool.Stop()
+
+	var (
+		providerKey, _ = crypto.GenerateKey()
+		provider       = crypto.PubkeyToAddress(providerKey.PublicKey)
+		contract       = common.Address{0xe}
+		fee            = new(big.Int).Mul(big.NewInt(int64(params.TxGas)), big.NewInt(params.GasPriceConfig))
+		keys           = make([]*ecdsa.PrivateKey, 4)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	pool.currentState.AddBalance(provider, new(big.Int).Mul(fee, big.NewInt(3)))
+	pool.currentState.CreateAccount(contract, types.CreateAccountOption{OwnerAddress: &common.Address{0xd}, ProviderAddress: &provider})
+
+	providerTx := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, contract, common.Big0, params.TxGas, big.NewInt(params.GasPriceConfig), nil), types.BaseSigner{}, key)
+		tx, _ = types.ProviderSignTx(tx, types.BaseSigner{}, providerKey)
+		return tx
+	}
+	// the provider can afford the fees of three transactions, the queued ones included
+	for i, tx := range []*types.Transaction{providerTx(0, keys[0]), providerTx(1, keys[0]), providerTx(5, keys[1])} {
+		if err := pool.AddRemote(tx); err != nil {
+			t.Fatalf("transaction %d: failed to add sponsored transaction: %v", i, err)
+		}
+	}
+	if err := pool.AddRemote(providerTx(0, keys[2])); err != ErrProviderOvercommitted {
+		t.Error("expected", ErrProviderOvercommitted, "got", err)
+	}
+	if slots, fees := pool.all.Sponsorship(provider); slots != 3 || fees.Cmp(new(big.Int).Mul(fee, big.NewInt(3))) != 0 {
+		t.Errorf("sponsorship mismatch: have %d slots and %v fees, want %d slots and %v fees", slots, fees, 3, new(big.Int).Mul(fee, big.NewInt(3)))
+	}
+	// the slots of the provider are limited as well
+	pool.currentState.AddBalance(provider, fee)
+	pool.config.ProviderSlots = 3
+	if err := pool.AddRemote(providerTx(0, keys[2])); err != ErrProviderSlotsExceeded {
+		t.Error("expected", ErrProviderSlotsExceeded, "got", err)
+	}
+	pool.config.ProviderSlots = 4
+	if err := pool.AddRemote(providerTx(0, keys[2])); err != nil {
+		t.Error("expected no error, got", err)
+	}
+	// the transactions of the biggest sponsored sender are evicted first, from the highest nonce
+	pool.currentState.SubBalance(provider, new(big.Int).Mul(fee, big.NewInt(2)))
+	pool.lockedReset(nil, nil)
+
+	pending, queued := pool.Stats()
+	if pending != 2 || queued != 0 {
+		t.Fatalf("pool size mismatch: have %d pending and %d queued, want 2 pending and 0 queued", pending, queued)
+	}
+	if pool.Get(providerTx(0, keys[0]).Hash()) == nil || pool.Get(providerTx(0, keys[2]).Hash()) == nil {
+		t.Error("expected the first transactions of the senders to be kept")
+	}
+	if slots, _ := pool.all.Sponsorship(provider); slots != 2 {
+		t.Errorf("sponsored slots mismatch: have %d, want %d", slots, 2)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// a provider without sponsored transactions is forgotten
+	pool.currentState.SubBalance(provider, pool.currentState.GetBalance(provider))
+	pool.lockedReset(nil, nil)
+	if providers := pool.all.Providers(); len(providers) != 0 {
+		t.Errorf("expected no provider, got %v", providers)
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	t.Parallel()
 
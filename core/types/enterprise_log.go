@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/crypto"
+)
+
+// The logs emitted by the changes of the providers and owner of enterprise contracts from the EnterpriseLogs fork on.
+// They are emitted by the contract itself and encoded like the events of a Solidity contract, the addresses
+// are indexed topics and there is no data, so they can be filtered like any other event.
+var (
+	// ProviderAddedTopic is the topic of the ProviderAdded(address indexed provider) log, emitted when a provider
+	// is added by the owner or set at the creation of the contract
+	ProviderAddedTopic = crypto.Keccak256Hash([]byte("ProviderAdded(address)"))
+	// ProviderRemovedTopic is the topic of the ProviderRemoved(address indexed provider) log
+	ProviderRemovedTopic = crypto.Keccak256Hash([]byte("ProviderRemoved(address)"))
+	// OwnershipTransferRequestedTopic is the topic of the
+	// OwnershipTransferRequested(address indexed owner, address indexed pendingOwner) log,
+	// emitted when the owner starts an ownership transfer
+	OwnershipTransferRequestedTopic = crypto.Keccak256Hash([]byte("OwnershipTransferRequested(address,address)"))
+	// OwnershipTransferredTopic is the topic of the OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+	// log, emitted when the pending owner accepts the ownership or with a zero previous owner at the creation of the contract
+	OwnershipTransferredTopic = crypto.Keccak256Hash([]byte("OwnershipTransferred(address,address)"))
+)
+
+// AddressTopic returns the topic of an indexed address, left padded with zeros like the Solidity events.
+func AddressTopic(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
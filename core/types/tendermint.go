@@ -16,8 +16,10 @@
 package types
 
 import (
+	"bytes"
 	"errors"
 	"io"
+	"math/big"
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/rlp"
@@ -47,29 +49,115 @@ type TendermintExtra struct {
 	CommittedSeal [][]byte
 	// Set of authorized validators at this moment
 	ValidatorAdds []byte
+	// ValidatorPowers is the voting power of each validator in ValidatorAdds, it is empty if the validators are not weighted
+	ValidatorPowers []*big.Int
+	// Evidences of misbehaving validators which the proposer included in this block
+	Evidences []*DuplicateVoteEvidence
+	// ParentCommittedSeal is the committed seals of the parent block known by the proposer.
+	// Unlike CommittedSeal, it is covered by the block hash, so every node tracks the liveness of validators alike
+	ParentCommittedSeal [][]byte
+	// GovernedParams are the chain parameters approved by the governance contract, recorded in the checkpoint
+	// headers and applied from the next epoch. They are sorted by Param.
+	GovernedParams []*GovernedParam
+	// ValidatorSetHash is the hash of ValidatorAdds and ValidatorPowers, set from the ValidatorSetHash fork on.
+	// Unlike them it is covered by the block hash, so the committed seals authenticate the validator set handed off
+	// by a checkpoint header
+	ValidatorSetHash common.Hash
+}
+
+// GovernedParam is a chain parameter and its value approved by the governance contract
+type GovernedParam struct {
+	Param uint64
+	Value *big.Int
 }
 
 // EncodeRLP serializes ist into the NeuralChain RLP format.
+// The optional fields are only appended if they are set, so headers without them keep their original encoding.
 func (te *TendermintExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		te.Seal,
 		te.CommittedSeal,
 		te.ValidatorAdds,
-	})
+	}
+	optionals := []interface{}{te.ValidatorPowers, te.Evidences, te.ParentCommittedSeal, te.GovernedParams, te.ValidatorSetHash}
+	// an optional field is required to be encoded if any field after it is set
+	last := -1
+	if len(te.ValidatorPowers) > 0 {
+		last = 0
+	}
+	if len(te.Evidences) > 0 {
+		last = 1
+	}
+	if len(te.ParentCommittedSeal) > 0 {
+		last = 2
+	}
+	if len(te.GovernedParams) > 0 {
+		last = 3
+	}
+	if te.ValidatorSetHash != (common.Hash{}) {
+		last = 4
+	}
+	fields = append(fields, optionals[:last+1]...)
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the tendermint fields from a RLP stream.
 func (te *TendermintExtra) DecodeRLP(s *rlp.Stream) error {
-	var tendermintExtra struct {
-		Seal          []byte
-		CommittedSeal [][]byte
-		ValidatorAdds []byte
+	if _, err := s.List(); err != nil {
+		return err
+	}
+	if err := s.Decode(&te.Seal); err != nil {
+		return err
+	}
+	if err := s.Decode(&te.CommittedSeal); err != nil {
+		return err
 	}
-	if err := s.Decode(&tendermintExtra); err != nil {
+	if err := s.Decode(&te.ValidatorAdds); err != nil {
 		return err
 	}
-	te.Seal, te.CommittedSeal, te.ValidatorAdds = tendermintExtra.Seal, tendermintExtra.CommittedSeal, tendermintExtra.ValidatorAdds
-	return nil
+	// optional fields, which are absent in headers created before they were introduced
+	for _, field := range []interface{}{&te.ValidatorPowers, &te.Evidences, &te.ParentCommittedSeal, &te.GovernedParams, &te.ValidatorSetHash} {
+		if err := s.Decode(field); err == rlp.EOL {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return s.ListEnd()
+}
+
+// HashValidatorSet returns the hash of the validator set handed off by a checkpoint header and of their voting powers,
+// or the zero hash if the header hands off no validator set
+func (te *TendermintExtra) HashValidatorSet() common.Hash {
+	if len(te.ValidatorAdds) == 0 {
+		return common.Hash{}
+	}
+	return rlpHash([]interface{}{te.ValidatorAdds, te.ValidatorPowers})
+}
+
+// DuplicateVoteEvidence is the proof that a validator signed two conflicting votes
+// (same type, block number and round but different block hash).
+// VoteA and VoteB are the rlp encoded signed consensus messages.
+type DuplicateVoteEvidence struct {
+	VoteA []byte
+	VoteB []byte
+}
+
+// NewDuplicateVoteEvidence returns the evidence of two conflicting votes.
+// The votes are ordered so the evidence is the same regardless of which vote was received first.
+func NewDuplicateVoteEvidence(voteA, voteB []byte) *DuplicateVoteEvidence {
+	if bytes.Compare(voteA, voteB) > 0 {
+		voteA, voteB = voteB, voteA
+	}
+	return &DuplicateVoteEvidence{
+		VoteA: common.CopyBytes(voteA),
+		VoteB: common.CopyBytes(voteB),
+	}
+}
+
+// Hash returns the keccak256 hash of the rlp encoded evidence
+func (ev *DuplicateVoteEvidence) Hash() common.Hash {
+	return rlpHash(ev)
 }
 
 // ExtractTendermintExtra extracts all values of the TendermintExtra from the header. It returns an
@@ -89,7 +177,8 @@ func ExtractTendermintExtra(h *Header) (*TendermintExtra, error) {
 }
 
 // TendermintFilteredHeader returns a filtered header which some information (like seal, committed seals)
-// are clean to fulfill the Tendermint hash rules. It returns nil if the extra-data cannot be
+// are clean to fulfill the Tendermint hash rules. The validator set handed off by a checkpoint header is
+// cleaned as well, only its ValidatorSetHash is kept. It returns nil if the extra-data cannot be
 // decoded/encoded by rlp.
 func TendermintFilteredHeader(h *Header, keepSeal bool) *Header {
 	newHeader := CopyHeader(h)
@@ -103,6 +192,7 @@ func TendermintFilteredHeader(h *Header, keepSeal bool) *Header {
 	}
 	tendermintExtra.CommittedSeal = [][]byte{}
 	tendermintExtra.ValidatorAdds = []byte{}
+	tendermintExtra.ValidatorPowers = nil
 
 	payload, err := rlp.EncodeToBytes(&tendermintExtra)
 	if err != nil {
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+)
+
+// EpochReward is the distribution of the rewards credited at the last block of a Tendermint epoch
+type EpochReward struct {
+	BlockNumber uint64
+	Validators  []*ValidatorReward // sorted by validator's address
+}
+
+// ValidatorReward is the reward a validator earned by proposing blocks in an epoch and how it was shared
+type ValidatorReward struct {
+	Validator   common.Address
+	BlockReward *big.Int // sum of the block rewards of the proposed blocks
+	TxFee       *big.Int // sum of the transaction fees of the proposed blocks
+	Owner       common.Address
+	OwnerReward *big.Int
+	Voters      []*VoterReward // sorted by voter's address
+	Jailed      bool           // voters of a jailed validator are not paid
+}
+
+// VoterReward is the share of a validator's reward paid to one of its voters
+type VoterReward struct {
+	Voter  common.Address
+	Reward *big.Int
+}
+
+// Total returns the reward the validator earned in the epoch
+func (r *ValidatorReward) Total() *big.Int {
+	return new(big.Int).Add(r.BlockReward, r.TxFee)
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+func TestTendermintExtra_EncodeDecodeRLP(t *testing.T) {
+	legacy := &TendermintExtra{
+		Seal:          []byte("seal"),
+		CommittedSeal: [][]byte{[]byte("committed seal")},
+		ValidatorAdds: []byte("validators"),
+	}
+	// headers without evidences keep the encoding of the previous fields
+	legacyPayload, err := rlp.EncodeToBytes([]interface{}{legacy.Seal, legacy.CommittedSeal, legacy.ValidatorAdds})
+	require.NoError(t, err)
+	payload, err := rlp.EncodeToBytes(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, legacyPayload, payload)
+
+	var decoded TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Equal(t, legacy.Seal, decoded.Seal)
+	assert.Len(t, decoded.Evidences, 0)
+
+	withEvidences := *legacy
+	withEvidences.Evidences = []*DuplicateVoteEvidence{NewDuplicateVoteEvidence([]byte("vote b"), []byte("vote a"))}
+	payload, err = rlp.EncodeToBytes(&withEvidences)
+	require.NoError(t, err)
+	decoded = TendermintExtra{}
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Len(t, decoded.ValidatorPowers, 0)
+	require.Len(t, decoded.Evidences, 1)
+	assert.Equal(t, []byte("vote a"), decoded.Evidences[0].VoteA)
+	assert.Equal(t, withEvidences.Evidences[0].Hash(), decoded.Evidences[0].Hash())
+}
+
+func TestTendermintExtra_ValidatorPowers(t *testing.T) {
+	extra := &TendermintExtra{
+		Seal:            []byte("seal"),
+		ValidatorAdds:   []byte("validators"),
+		ValidatorPowers: []*big.Int{big.NewInt(10), big.NewInt(1)},
+	}
+	payload, err := rlp.EncodeToBytes(extra)
+	require.NoError(t, err)
+
+	var decoded TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Equal(t, extra.ValidatorPowers, decoded.ValidatorPowers)
+	assert.Len(t, decoded.Evidences, 0)
+
+	// the validator set and its powers are not covered by the block hash
+	header := &Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}
+	filtered, err := ExtractTendermintExtra(TendermintFilteredHeader(header, false))
+	require.NoError(t, err)
+	assert.Len(t, filtered.ValidatorAdds, 0)
+	assert.Len(t, filtered.ValidatorPowers, 0)
+}
+
+func TestTendermintExtra_ValidatorSetHash(t *testing.T) {
+	extra := &TendermintExtra{
+		Seal:            []byte("seal"),
+		ValidatorAdds:   []byte("validators"),
+		ValidatorPowers: []*big.Int{big.NewInt(10), big.NewInt(1)},
+	}
+	payload, err := rlp.EncodeToBytes(extra)
+	require.NoError(t, err)
+	legacy := &Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}
+
+	extra.ValidatorSetHash = extra.HashValidatorSet()
+	payload, err = rlp.EncodeToBytes(extra)
+	require.NoError(t, err)
+	var decoded TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Equal(t, extra.ValidatorSetHash, decoded.ValidatorSetHash)
+	assert.Len(t, decoded.GovernedParams, 0)
+	hashed := &Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}
+
+	// the headers without the hash of their validator set keep their hash whatever their validator set
+	forged := *extra
+	forged.ValidatorAdds = []byte("forged validators")
+	forged.ValidatorSetHash = common.Hash{}
+	payload, err = rlp.EncodeToBytes(&forged)
+	require.NoError(t, err)
+	assert.Equal(t, legacy.Hash(), (&Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}).Hash())
+
+	// the hash of the validator set is covered by the block hash
+	assert.NotEqual(t, legacy.Hash(), hashed.Hash())
+	filtered, err := ExtractTendermintExtra(TendermintFilteredHeader(hashed, false))
+	require.NoError(t, err)
+	assert.Len(t, filtered.ValidatorAdds, 0)
+	assert.Equal(t, extra.ValidatorSetHash, filtered.ValidatorSetHash)
+	forged.ValidatorPowers = []*big.Int{big.NewInt(10), big.NewInt(2)}
+	assert.NotEqual(t, extra.ValidatorSetHash, forged.HashValidatorSet())
+	assert.Equal(t, common.Hash{}, (&TendermintExtra{}).HashValidatorSet())
+}
+
+func TestTendermintExtra_ParentCommittedSeal(t *testing.T) {
+	extra := &TendermintExtra{
+		Seal:                []byte("seal"),
+		ValidatorAdds:       []byte("validators"),
+		ParentCommittedSeal: [][]byte{[]byte("parent seal")},
+	}
+	payload, err := rlp.EncodeToBytes(extra)
+	require.NoError(t, err)
+
+	var decoded TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Len(t, decoded.ValidatorPowers, 0)
+	assert.Len(t, decoded.Evidences, 0)
+	assert.Equal(t, extra.ParentCommittedSeal, decoded.ParentCommittedSeal)
+
+	// the parent committed seals are covered by the block hash
+	header := &Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}
+	filtered, err := ExtractTendermintExtra(TendermintFilteredHeader(header, false))
+	require.NoError(t, err)
+	assert.Equal(t, extra.ParentCommittedSeal, filtered.ParentCommittedSeal)
+}
+
+func TestTendermintExtra_GovernedParams(t *testing.T) {
+	extra := &TendermintExtra{
+		Seal:           []byte("seal"),
+		ValidatorAdds:  []byte("validators"),
+		GovernedParams: []*GovernedParam{{Param: 0, Value: big.NewInt(1000)}, {Param: 4, Value: big.NewInt(500)}},
+	}
+	payload, err := rlp.EncodeToBytes(extra)
+	require.NoError(t, err)
+
+	var decoded TendermintExtra
+	require.NoError(t, rlp.DecodeBytes(payload, &decoded))
+	assert.Len(t, decoded.Evidences, 0)
+	assert.Len(t, decoded.ParentCommittedSeal, 0)
+	assert.Equal(t, extra.GovernedParams, decoded.GovernedParams)
+
+	// the governed parameters are covered by the block hash
+	header := &Header{Extra: append(make([]byte, TendermintExtraVanity), payload...)}
+	filtered, err := ExtractTendermintExtra(TendermintFilteredHeader(header, false))
+	require.NoError(t, err)
+	assert.Equal(t, extra.GovernedParams, filtered.GovernedParams)
+}
@@ -37,6 +37,14 @@ const (
 	NormalTxType TransactionType = iota
 	AddProviderTxType
 	RemoveProviderTxType
+	// TransferOwnershipTxType proposes a new owner for an enterprise contract, who becomes the owner once it accepts
+	TransferOwnershipTxType
+	// AcceptOwnershipTxType makes the pending owner of an enterprise contract its owner
+	AcceptOwnershipTxType
+	// SetProviderBudgetTxType limits the gas a provider of an enterprise contract pays per epoch
+	SetProviderBudgetTxType
+	// SetSenderAllowanceTxType limits the gas the providers of an enterprise contract pay for a sender per epoch
+	SetSenderAllowanceTxType
 )
 
 var (
@@ -62,9 +70,10 @@ type CreateAccountOption struct {
 type Transaction struct {
 	data txdata
 	// caches
-	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	hash     atomic.Value
+	size     atomic.Value
+	from     atomic.Value
+	provider atomic.Value
 }
 
 //ethTxData is the original ethTxData format. It is kept for backward compatibility purpose.
@@ -169,6 +178,18 @@ type ModifyProvidersMsg struct {
 	Provider common.Address
 }
 
+// TransferOwnershipMsg is info about the new owner proposed for enterprise contract
+type TransferOwnershipMsg struct {
+	NewOwner common.Address
+}
+
+// GasLimitMsg is info about the gas budget of a provider or the gas allowance of a sender of enterprise contract.
+// A limit of 0 removes the limit.
+type GasLimitMsg struct {
+	Address common.Address
+	Limit   uint64
+}
+
 type txdataMarshaling struct {
 	AccountNonce hexutil.Uint64
 	Price        *hexutil.Big
@@ -217,6 +238,46 @@ func NewModifyProvidersTransaction(nonce uint64, to common.Address, gasLimit uin
 	return newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra), nil
 }
 
+// NewTransferOwnershipTransaction create a new transaction to propose a new owner for an enterprise contract
+func NewTransferOwnershipTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, newOwner common.Address) (*Transaction, error) {
+	msg, err := rlp.EncodeToBytes(&TransferOwnershipMsg{NewOwner: newOwner})
+	if err != nil {
+		return nil, err
+	}
+	extra, err := rlp.EncodeToBytes(&TransactionExtraData{Type: TransferOwnershipTxType, Msg: msg})
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra), nil
+}
+
+// NewSetGasLimitTransaction create a new transaction to set the gas budget per epoch of a provider of an
+// enterprise contract if isProvider, or the gas allowance per epoch of a sender otherwise
+func NewSetGasLimitTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int, addr common.Address, limit uint64, isProvider bool) (*Transaction, error) {
+	msg, err := rlp.EncodeToBytes(&GasLimitMsg{Address: addr, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	txType := SetProviderBudgetTxType
+	if !isProvider {
+		txType = SetSenderAllowanceTxType
+	}
+	extra, err := rlp.EncodeToBytes(&TransactionExtraData{Type: txType, Msg: msg})
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra), nil
+}
+
+// NewAcceptOwnershipTransaction create a new transaction to accept the ownership of an enterprise contract
+func NewAcceptOwnershipTransaction(nonce uint64, to common.Address, gasLimit uint64, gasPrice *big.Int) (*Transaction, error) {
+	extra, err := rlp.EncodeToBytes(&TransactionExtraData{Type: AcceptOwnershipTxType})
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(nonce, &to, big.NewInt(0), gasLimit, gasPrice, nil, extra), nil
+}
+
 func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, extra []byte) *Transaction {
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
@@ -453,6 +514,15 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 	return msg, nil
 }
 
+// DecodeExtraData returns the type of the transaction and its message, like the ModifyProvidersMsg of a
+// transaction modifying the providers of an enterprise contract.
+func (tx *Transaction) DecodeExtraData() (TransactionType, interface{}, error) {
+	if len(tx.data.Extra) == 0 {
+		return NormalTxType, nil, nil
+	}
+	return decodeTransactionExtraData(tx.data)
+}
+
 func decodeTransactionExtraData(data txdata) (TransactionType, interface{}, error) {
 	var extraData TransactionExtraData
 	if err := rlp.DecodeBytes(data.Extra, &extraData); err != nil {
@@ -465,6 +535,23 @@ func decodeTransactionExtraData(data txdata) (TransactionType, interface{}, erro
 			return NormalTxType, nil, err
 		}
 		return extraData.Type, providerData, nil
+	case TransferOwnershipTxType:
+		var ownershipData TransferOwnershipMsg
+		if err := rlp.DecodeBytes(extraData.Msg, &ownershipData); err != nil {
+			return NormalTxType, nil, err
+		}
+		if ownershipData.NewOwner == (common.Address{}) {
+			return NormalTxType, nil, ErrEmptyOwner
+		}
+		return extraData.Type, ownershipData, nil
+	case AcceptOwnershipTxType:
+		return extraData.Type, nil, nil
+	case SetProviderBudgetTxType, SetSenderAllowanceTxType:
+		var limitData GasLimitMsg
+		if err := rlp.DecodeBytes(extraData.Msg, &limitData); err != nil {
+			return NormalTxType, nil, err
+		}
+		return extraData.Type, limitData, nil
 	default:
 		return extraData.Type, nil, ErrInvalidExtraDataType
 	}
@@ -618,35 +705,44 @@ func (s *TxByPrice) Pop() interface{} {
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type TransactionsByPriceAndNonce struct {
-	txs    map[common.Address]Transactions // Per account nonce-sorted list of transactions
-	heads  TxByPrice                       // Next transaction for each unique account (price heap)
-	signer Signer                          // Signer for the set of transactions
+	txs     map[common.Address]Transactions // Per account nonce-sorted list of transactions
+	heads   TxByPrice                       // Next transaction for each unique account (price heap)
+	signer  Signer                          // Signer for the set of transactions
+	baseFee *big.Int                        // Base fee of the block, nil before the fee market
 }
 
 // NewTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// price sorted transactions in a nonce-honouring way. If the base fee is not nil,
+// the transactions are sorted by their tip above it, and an account is left
+// out from its first transaction below the base fee.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions) *TransactionsByPriceAndNonce {
+func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions, baseFee *big.Int) *TransactionsByPriceAndNonce {
 	// Initialize a price based heap with the head transactions
 	heads := make(TxByPrice, 0, len(txs))
 	for from, accTxs := range txs {
-		heads = append(heads, accTxs[0])
 		// Ensure the sender address is from the signer
 		acc, _ := Sender(signer, accTxs[0])
-		txs[acc] = accTxs[1:]
 		if from != acc {
 			delete(txs, from)
 		}
+		// Leave out the accounts whose next transaction can't pay the base fee
+		if baseFee != nil && accTxs[0].GasPrice().Cmp(baseFee) < 0 {
+			delete(txs, acc)
+			continue
+		}
+		heads = append(heads, accTxs[0])
+		txs[acc] = accTxs[1:]
 	}
 	heap.Init(&heads)
 
 	// Assemble and return the transaction set
 	return &TransactionsByPriceAndNonce{
-		txs:    txs,
-		heads:  heads,
-		signer: signer,
+		txs:     txs,
+		heads:   heads,
+		signer:  signer,
+		baseFee: baseFee,
 	}
 }
 
@@ -661,7 +757,7 @@ func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
 // Shift replaces the current best head with the next one from the same account.
 func (t *TransactionsByPriceAndNonce) Shift() {
 	acc, _ := Sender(t.signer, t.heads[0])
-	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 && (t.baseFee == nil || txs[0].GasPrice().Cmp(t.baseFee) >= 0) {
 		t.heads[0], t.txs[acc] = txs[0], txs[1:]
 		heap.Fix(&t.heads, 0)
 	} else {
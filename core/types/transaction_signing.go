@@ -31,7 +31,7 @@ var (
 	ErrInvalidChainId = errors.New("invalid chain id for signer")
 )
 
-// sigCache is used to cache the derived sender and contains
+// sigCache is used to cache the derived sender or provider and contains
 // the signer used to derive it.
 type sigCache struct {
 	signer Signer
@@ -95,13 +95,19 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 // Provider returns the address derived from the signature (V, R, S) using secp256k1
 // If there is no provider signature, it will return nil address pointer and nill error.
 func Provider(signer Signer, tx *Transaction) (*common.Address, error) {
-	// Not caching provider for now
 	// Short circuit
 	if (tx.data.PV == nil || tx.data.PV.Cmp(big.NewInt(0)) == 0) &&
 		(tx.data.PR == nil || tx.data.PR.Cmp(big.NewInt(0)) == 0) &&
 		(tx.data.PS == nil || tx.data.PS.Cmp(big.NewInt(0)) == 0) {
 		return nil, nil
 	}
+	// The provider is cached like the sender, the cache is invalidated by another signer
+	if pc := tx.provider.Load(); pc != nil {
+		if sigCache := pc.(sigCache); sigCache.signer.Equal(signer) {
+			provider := sigCache.from
+			return &provider, nil
+		}
+	}
 	provider, err := signer.Provider(tx)
 	if err != nil {
 		return nil, err
@@ -110,6 +116,7 @@ func Provider(signer Signer, tx *Transaction) (*common.Address, error) {
 	if provider == (common.Address{}) {
 		return nil, nil
 	}
+	tx.provider.Store(sigCache{signer: signer, from: provider})
 	return &provider, nil
 }
 
@@ -171,7 +171,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 		}
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -204,6 +204,34 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 	}
 }
 
+// Tests that transactions below the base fee are left out together with the
+// following transactions of the same account.
+func TestTransactionPriceNonceSortBaseFee(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := BaseSigner{}
+	// The prices of the transactions of each account by nonce
+	prices := [][]int64{{10, 12, 15}, {20, 5, 30}, {8, 40}}
+	groups := map[common.Address]Transactions{}
+	for i, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		for nonce, price := range prices[i] {
+			tx, _ := SignTx(NewTransaction(uint64(nonce), common.Address{}, big.NewInt(100), 100, big.NewInt(price), nil), signer, key)
+			groups[addr] = append(groups[addr], tx)
+		}
+	}
+	txset := NewTransactionsByPriceAndNonce(signer, groups, big.NewInt(10))
+
+	var have []int64
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		have = append(have, tx.GasPrice().Int64())
+		txset.Shift()
+	}
+	require.Equal(t, []int64{20, 10, 12, 15}, have)
+}
+
 // TestTransactionJSON tests serializing/de-serializing to/from JSON.
 func TestTransactionJSON(t *testing.T) {
 	key, err := crypto.GenerateKey()
@@ -302,6 +330,24 @@ func TestTransaction_AsMessage(t *testing.T) {
 	invalidAddProviderTx, err = ProviderSignTx(invalidAddProviderTx, signer, testKey2)
 	require.NoError(t, err)
 
+	transferOwnershipTx, err := NewTransferOwnershipTransaction(uint64(3), contractAddr, 1000000,
+		big.NewInt(params.GasPriceConfig), testAddr2)
+	require.NoError(t, err)
+	transferOwnershipTx, err = SignTx(transferOwnershipTx, signer, testKey)
+	require.NoError(t, err)
+
+	emptyOwnerTx, err := NewTransferOwnershipTransaction(uint64(3), contractAddr, 1000000,
+		big.NewInt(params.GasPriceConfig), common.Address{})
+	require.NoError(t, err)
+	emptyOwnerTx, err = SignTx(emptyOwnerTx, signer, testKey)
+	require.NoError(t, err)
+
+	acceptOwnershipTx, err := NewAcceptOwnershipTransaction(uint64(0), contractAddr, 1000000,
+		big.NewInt(params.GasPriceConfig))
+	require.NoError(t, err)
+	acceptOwnershipTx, err = SignTx(acceptOwnershipTx, signer, testKey2)
+	require.NoError(t, err)
+
 	var testCases = []struct {
 		tx                      *Transaction
 		expectedErr             error
@@ -345,6 +391,33 @@ func TestTransaction_AsMessage(t *testing.T) {
 				require.Equal(t, extraData.Provider, testAddr2)
 			},
 		},
+		{
+			tx:          invalidAddProviderTx,
+			expectedErr: ErrRedundantProviderSignature,
+		}, {
+			tx:                      transferOwnershipTx,
+			expectedErr:             nil,
+			expectedFromAddress:     testAddr,
+			expectedGasPayerAddress: testAddr,
+			assertFn: func(msg Message) {
+				require.Equal(t, msg.txType, TransferOwnershipTxType)
+				extraData, ok := msg.extraData.(TransferOwnershipMsg)
+				require.True(t, ok)
+				require.Equal(t, extraData.NewOwner, testAddr2)
+			},
+		}, {
+			tx:          emptyOwnerTx,
+			expectedErr: ErrEmptyOwner,
+		}, {
+			tx:                      acceptOwnershipTx,
+			expectedErr:             nil,
+			expectedFromAddress:     testAddr2,
+			expectedGasPayerAddress: testAddr2,
+			assertFn: func(msg Message) {
+				require.Equal(t, msg.txType, AcceptOwnershipTxType)
+				require.Nil(t, msg.extraData)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
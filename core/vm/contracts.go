@@ -49,6 +49,24 @@ var PrecompiledContractsOmaha = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
 }
 
+// PrecompiledContractsValidatorSet contains the pre-compiled NeuralChain contracts
+// added by the ValidatorSet fork.
+var PrecompiledContractsValidatorSet = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{9}): &validatorSet{},
+}
+
+// PrecompiledContractsGasPayer contains the pre-compiled NeuralChain contracts
+// added by the GasPayer fork.
+var PrecompiledContractsGasPayer = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{10}): &gasPayer{},
+}
+
+// contextualContract is a precompiled contract reading the block or message information of the EVM context.
+type contextualContract interface {
+	PrecompiledContract
+	withContext(ctx *Context) PrecompiledContract
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -347,3 +365,83 @@ func (c *bn256Pairing) Run(input []byte) ([]byte, error) {
 	}
 	return false32Byte, nil
 }
+
+var errValidatorSetUnavailable = errors.New("validator set unavailable")
+
+// validatorSet implemented as a native contract, returning the ABI encoding of
+// (address[] validators, address proposer, uint256 parentSeals, uint256[] powers) for the current block,
+// where the voting powers are empty unless the votes of the validators are weighted.
+type validatorSet struct {
+	ctx *Context
+}
+
+func (c *validatorSet) withContext(ctx *Context) PrecompiledContract {
+	return &validatorSet{ctx: ctx}
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract,
+// which grows with the number of validators returned.
+func (c *validatorSet) RequiredGas(input []byte) uint64 {
+	if c.ctx == nil || c.ctx.GetValidators == nil {
+		return params.ValidatorSetGas
+	}
+	validators, _, _, err := c.ctx.GetValidators()
+	if err != nil {
+		return params.ValidatorSetGas
+	}
+	return params.ValidatorSetGas + uint64(len(validators))*params.ValidatorSetPerValGas
+}
+
+func (c *validatorSet) Run(input []byte) ([]byte, error) {
+	if c.ctx == nil || c.ctx.GetValidators == nil {
+		return nil, errValidatorSetUnavailable
+	}
+	validators, powers, parentSeals, err := c.ctx.GetValidators()
+	if err != nil {
+		return nil, errValidatorSetUnavailable
+	}
+	// The dynamic validators and powers arrays are encoded after the four head words
+	ret := make([]byte, 0, 32*(6+len(validators)+len(powers)))
+	ret = append(ret, common.LeftPadBytes(big.NewInt(4*32).Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(c.ctx.Coinbase.Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(new(big.Int).SetUint64(parentSeals).Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(big.NewInt(int64(32*(5+len(validators)))).Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(big.NewInt(int64(len(validators))).Bytes(), 32)...)
+	for _, validator := range validators {
+		ret = append(ret, common.LeftPadBytes(validator.Bytes(), 32)...)
+	}
+	ret = append(ret, common.LeftPadBytes(big.NewInt(int64(len(powers))).Bytes(), 32)...)
+	for _, power := range powers {
+		ret = append(ret, common.LeftPadBytes(power.Bytes(), 32)...)
+	}
+	return ret, nil
+}
+
+var errGasPayerUnavailable = errors.New("gas payer unavailable")
+
+// gasPayer implemented as a native contract, returning the ABI encoding of
+// (address gasPayer, bool sponsored) for the current transaction.
+type gasPayer struct {
+	ctx *Context
+}
+
+func (c *gasPayer) withContext(ctx *Context) PrecompiledContract {
+	return &gasPayer{ctx: ctx}
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *gasPayer) RequiredGas(input []byte) uint64 {
+	return params.GasPayerGas
+}
+
+func (c *gasPayer) Run(input []byte) ([]byte, error) {
+	if c.ctx == nil {
+		return nil, errGasPayerUnavailable
+	}
+	ret := make([]byte, 64)
+	copy(ret[12:32], c.ctx.GasPayer.Bytes())
+	if c.ctx.Sponsored {
+		ret[63] = 1
+	}
+	return ret, nil
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/params"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -485,3 +486,116 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("NKuyBkoGdZZSLyPbJEetheRhMjf1gRLyCn", test, bench)
 	}
 }
+
+func TestPrecompiledValidatorSet(t *testing.T) {
+	var (
+		addr       = common.BytesToAddress([]byte{9})
+		proposer   = common.HexToAddress("0x0000000000000000000000000000000000000abc")
+		validators = []common.Address{proposer, common.HexToAddress("0x0000000000000000000000000000000000000def")}
+		forked     = *params.TestChainConfig
+	)
+	forked.ValidatorSetBlock = big.NewInt(0)
+	getValidators := func() ([]common.Address, []*big.Int, uint64, error) {
+		return validators, nil, 2, nil
+	}
+	getWeightedValidators := func() ([]common.Address, []*big.Int, uint64, error) {
+		return validators, []*big.Int{big.NewInt(10), big.NewInt(3)}, 2, nil
+	}
+
+	tests := []struct {
+		config        *params.ChainConfig
+		getValidators GetValidatorsFunc
+		gas           uint64
+		expected      string
+		err           error
+	}{
+		{
+			config:        &forked,
+			getValidators: getValidators,
+			gas:           params.ValidatorSetGas + 2*params.ValidatorSetPerValGas,
+			expected: "0000000000000000000000000000000000000000000000000000000000000080" +
+				"0000000000000000000000000000000000000000000000000000000000000abc" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"00000000000000000000000000000000000000000000000000000000000000e0" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000abc" +
+				"0000000000000000000000000000000000000000000000000000000000000def" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			config:        &forked,
+			getValidators: getWeightedValidators,
+			gas:           params.ValidatorSetGas + 2*params.ValidatorSetPerValGas,
+			expected: "0000000000000000000000000000000000000000000000000000000000000080" +
+				"0000000000000000000000000000000000000000000000000000000000000abc" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"00000000000000000000000000000000000000000000000000000000000000e0" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000abc" +
+				"0000000000000000000000000000000000000000000000000000000000000def" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"000000000000000000000000000000000000000000000000000000000000000a" +
+				"0000000000000000000000000000000000000000000000000000000000000003",
+		},
+		// the gas grows with the number of validators
+		{config: &forked, getValidators: getValidators, gas: params.ValidatorSetGas + params.ValidatorSetPerValGas, err: ErrOutOfGas},
+		// engines without validators
+		{config: &forked, gas: params.ValidatorSetGas, err: errValidatorSetUnavailable},
+		// not pre-compiled before the fork
+		{config: params.TestChainConfig, getValidators: getValidators},
+	}
+	for i, tt := range tests {
+		env := NewEVM(Context{GetValidators: tt.getValidators, Coinbase: proposer, BlockNumber: big.NewInt(1)}, nil, tt.config, Config{})
+		contract := NewContract(AccountRef(common.Address{}), AccountRef(addr), new(big.Int), tt.gas)
+		contract.SetCallCode(&addr, common.Hash{}, nil)
+
+		ret, err := run(env, contract, nil, true)
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if common.Bytes2Hex(ret) != tt.expected {
+			t.Errorf("test %d: output mismatch: have %x, want %s", i, ret, tt.expected)
+		}
+	}
+}
+
+func TestPrecompiledGasPayer(t *testing.T) {
+	var (
+		addr     = common.BytesToAddress([]byte{10})
+		provider = common.HexToAddress("0x0000000000000000000000000000000000000abc")
+		forked   = *params.TestChainConfig
+	)
+	forked.GasPayerBlock = big.NewInt(0)
+
+	tests := []struct {
+		config   *params.ChainConfig
+		expected string
+	}{
+		{
+			config: &forked,
+			expected: "0000000000000000000000000000000000000000000000000000000000000abc" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		// not pre-compiled before the fork
+		{config: params.TestChainConfig},
+	}
+	for i, tt := range tests {
+		env := NewEVM(Context{GasPayer: provider, Sponsored: true, BlockNumber: big.NewInt(1)}, nil, tt.config, Config{})
+		contract := NewContract(AccountRef(common.Address{}), AccountRef(addr), new(big.Int), params.GasPayerGas)
+		contract.SetCallCode(&addr, common.Hash{}, nil)
+
+		ret, err := run(env, contract, nil, true)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if common.Bytes2Hex(ret) != tt.expected {
+			t.Errorf("test %d: output mismatch: have %x, want %s", i, ret, tt.expected)
+		}
+	}
+}
+
+func TestPrecompiledGasPayerWithoutContext(t *testing.T) {
+	if _, err := new(gasPayer).Run(nil); err != errGasPayerUnavailable {
+		t.Errorf("error mismatch: have %v, want %v", err, errGasPayerUnavailable)
+	}
+}
@@ -39,13 +39,19 @@ type (
 	// GetHashFunc returns the nth block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
 	GetHashFunc func(uint64) common.Hash
+	// GetValidatorsFunc returns the validators of the current block with their voting
+	// powers, empty if the votes are not weighted, and the number of committed seals
+	// on its parent, it is used by the validator set contract.
+	GetValidatorsFunc func() ([]common.Address, []*big.Int, uint64, error)
 )
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContractsOmaha
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
+			if c, ok := p.(contextualContract); ok {
+				p = c.withContext(&evm.Context)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -65,6 +71,25 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 	return nil, ErrNoCompatibleInterpreter
 }
 
+// precompile returns the pre-compiled contract at addr under the chain rules of the evm, nil if there is none.
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	if p := PrecompiledContractsOmaha[addr]; p != nil {
+		return p
+	}
+	if p := PrecompiledContractsValidatorSet[addr]; p != nil && evm.chainRules.IsValidatorSet {
+		return p
+	}
+	if p := PrecompiledContractsGasPayer[addr]; p != nil && evm.chainRules.IsGasPayer {
+		return p
+	}
+	return nil
+}
+
+// IsPrecompiled returns whether a contract is pre-compiled at addr under the chain rules of the evm.
+func (evm *EVM) IsPrecompiled(addr common.Address) bool {
+	return evm.precompile(addr) != nil
+}
+
 // Context provides the EVM with auxiliary information. Once provided
 // it shouldn't be modified.
 type Context struct {
@@ -75,10 +100,14 @@ type Context struct {
 	Transfer TransferFunc
 	// GetHash returns the hash corresponding to n
 	GetHash GetHashFunc
+	// GetValidators returns the validators of the block, nil if the consensus does not elect them
+	GetValidators GetValidatorsFunc
 
 	// Message information
-	Origin   common.Address // Provides information for ORIGIN
-	GasPrice *big.Int       // Provides information for GASPRICE
+	Origin    common.Address // Provides information for ORIGIN
+	GasPrice  *big.Int       // Provides information for GASPRICE
+	GasPayer  common.Address // Provides the account paying the gas, the origin unless the transaction is sponsored
+	Sponsored bool           // Provides whether the gas is paid by the provider of the called enterprise contract
 
 	// Block information
 	Coinbase    common.Address // Provides information for COINBASE
@@ -86,6 +115,7 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	BaseFee     *big.Int       // Base fee of the block, nil before the fee market
 }
 
 // EVM is the NeuralChain Virtual Machine base object and provides
@@ -195,8 +225,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContractsOmaha
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -31,6 +31,14 @@ type StateDB interface {
 	GetProviders(common.Address) []common.Address
 	AddProvider(addr common.Address, from common.Address, providerAddress common.Address) error
 	RemoveProvider(addr common.Address, from common.Address, providerAddress common.Address) error
+	GetPendingOwner(common.Address) *common.Address
+	TransferOwnership(addr common.Address, from common.Address, newOwner common.Address) error
+	AcceptOwnership(addr common.Address, from common.Address) error
+	GetProviderBudget(addr common.Address, provider common.Address, epoch uint64) (uint64, bool)
+	GetSenderAllowance(addr common.Address, sender common.Address, epoch uint64) (uint64, bool)
+	SetProviderBudget(addr common.Address, from common.Address, provider common.Address, budget uint64) error
+	SetSenderAllowance(addr common.Address, from common.Address, sender common.Address, allowance uint64) error
+	UseEnterpriseGas(addr common.Address, provider common.Address, sender common.Address, epoch uint64, gas uint64)
 
 	SubBalance(common.Address, *big.Int)
 	AddBalance(common.Address, *big.Int)
@@ -35,6 +35,8 @@ func NewEnv(cfg *Config) *vm.EVM {
 		Difficulty:  cfg.Difficulty,
 		GasLimit:    cfg.GasLimit,
 		GasPrice:    cfg.GasPrice,
+		GasPayer:    cfg.GasPayer,
+		Sponsored:   cfg.GasPayer != cfg.Origin,
 	}
 
 	return vm.NewEVM(context, cfg.State, cfg.ChainConfig, cfg.EVMConfig)
@@ -40,6 +40,7 @@ type Config struct {
 	Time        *big.Int
 	GasLimit    uint64
 	GasPrice    *big.Int
+	GasPayer    common.Address // The account paying the gas, a provider other than the origin sponsors the call
 	Value       *big.Int
 	Debug       bool
 	EVMConfig   vm.Config
@@ -68,6 +69,9 @@ func setDefaults(cfg *Config) {
 	if cfg.GasPrice == nil {
 		cfg.GasPrice = new(big.Int)
 	}
+	if cfg.GasPayer == (common.Address{}) {
+		cfg.GasPayer = cfg.Origin
+	}
 	if cfg.Value == nil {
 		cfg.Value = new(big.Int)
 	}
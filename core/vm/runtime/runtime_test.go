@@ -117,6 +117,52 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestCallGasPayer(t *testing.T) {
+	state, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	address := common.BytesToAddress([]byte("contract"))
+	// return the output of the gas payer contract
+	state.SetCode(address, []byte{
+		byte(vm.PUSH1), 64,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 10,
+		byte(vm.GAS),
+		byte(vm.STATICCALL),
+		byte(vm.POP),
+		byte(vm.PUSH1), 64,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	})
+	var (
+		origin   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		provider = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+		config   = &params.ChainConfig{ChainID: big.NewInt(1), GasPayerBlock: big.NewInt(0)}
+	)
+	tests := []struct {
+		gasPayer  common.Address
+		sponsored bool
+	}{
+		{gasPayer: origin},
+		{gasPayer: provider, sponsored: true},
+	}
+	for i, tt := range tests {
+		ret, _, err := Call(address, nil, &Config{State: state, ChainConfig: config, Origin: origin, GasPayer: tt.gasPayer})
+		if err != nil {
+			t.Fatalf("test %d: didn't expect error %v", i, err)
+		}
+		if len(ret) != 64 {
+			t.Fatalf("test %d: expected 64 bytes, got %x", i, ret)
+		}
+		if have := common.BytesToAddress(ret[:32]); have != tt.gasPayer {
+			t.Errorf("test %d: gas payer mismatch: have %x, want %x", i, have, tt.gasPayer)
+		}
+		if have := ret[63] == 1; have != tt.sponsored {
+			t.Errorf("test %d: sponsored mismatch: have %v, want %v", i, have, tt.sponsored)
+		}
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
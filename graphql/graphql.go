@@ -18,18 +18,23 @@
 package graphql
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"net/http"
+	"sort"
 	"time"
 
 	neuralChain "github.com/lvbin2012/NeuralChain"
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/core/vm"
 	"github.com/lvbin2012/NeuralChain/internal/neutapi"
@@ -46,6 +51,7 @@ import (
 
 var OnlyOnMainChainError = errors.New("This operation is only available for blocks on the canonical chain.")
 var BlockInvariantError = errors.New("Block objects must be instantiated with at least one of num or hash.")
+var NoStakingContractError = errors.New("This operation is only available on chains with a staking contract.")
 
 // Account represents an NeuralChain account at a particular block.
 type Account struct {
@@ -100,6 +106,28 @@ func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash })
 	return state.GetState(a.address, args.Slot), nil
 }
 
+func (a *Account) Owner(ctx context.Context) (*common.Address, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.GetOwner(a.address), nil
+}
+
+func (a *Account) Providers(ctx context.Context) ([]common.Address, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := state.GetProviders(a.address)
+	if providers == nil {
+		providers = []common.Address{}
+	}
+	return providers, nil
+}
+
 // Log represents an individual log message. All arguments are mandatory.
 type Log struct {
 	backend     *neut.NeutAPIBackend
@@ -476,6 +504,27 @@ func (b *Block) GasUsed(ctx context.Context) (hexutil.Uint64, error) {
 	return hexutil.Uint64(header.GasUsed), nil
 }
 
+// Finalized returns whether the block is on the canonical chain and can no longer be reverted according to the
+// consensus engine.
+func (b *Block) Finalized(ctx context.Context) (bool, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return false, err
+	}
+	finalized, err := b.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil || finalized == nil {
+		return false, err
+	}
+	if header.Number.Cmp(finalized.Number) > 0 {
+		return false, nil
+	}
+	canonical, err := b.backend.HeaderByNumber(ctx, rpc.BlockNumber(header.Number.Uint64()))
+	if err != nil || canonical == nil {
+		return false, err
+	}
+	return canonical.Hash() == header.Hash(), nil
+}
+
 func (b *Block) Parent(ctx context.Context) (*Block, error) {
 	// If the block hasn't been fetched, and we'll need it, fetch it.
 	if b.num == nil && b.hash != (common.Hash{}) && b.header == nil {
@@ -653,6 +702,112 @@ func (b *Block) Miner(ctx context.Context, args BlockNumberArgs) (*Account, erro
 	}, nil
 }
 
+// tendermintHeader returns the header of the block and its Tendermint extra data,
+// or nil if the chain does not run the Tendermint consensus.
+func (b *Block) tendermintHeader(ctx context.Context) (*types.Header, *types.TendermintExtra, error) {
+	if b.backend.ChainConfig().Tendermint == nil {
+		return nil, nil, nil
+	}
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, nil, err
+	}
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, extra, nil
+}
+
+// blockProposer recovers the proposer of a block from its seal, the genesis block has no proposer.
+func blockProposer(header *types.Header, extra *types.TendermintExtra) (*common.Address, error) {
+	if len(extra.Seal) == 0 {
+		return nil, nil
+	}
+	addr, err := utils.GetSignatureAddress(utils.SigHash(header).Bytes(), extra.Seal)
+	if err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+// blockSigners recovers the validators which committed a block from its committed seals.
+func blockSigners(header *types.Header, extra *types.TendermintExtra) ([]common.Address, error) {
+	proposalSeal := utils.PrepareCommittedSeal(header.Hash())
+	signers := make([]common.Address, 0, len(extra.CommittedSeal))
+	for _, seal := range extra.CommittedSeal {
+		addr, err := utils.GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, addr)
+	}
+	return signers, nil
+}
+
+// blockValidators decodes the validator set stored in the extra data of a checkpoint block.
+func blockValidators(extra *types.TendermintExtra) ([]*Validator, error) {
+	validators := []*Validator{}
+	if len(extra.ValidatorAdds) == 0 {
+		return validators, nil
+	}
+	var addresses []common.Address
+	if err := rlp.DecodeBytes(extra.ValidatorAdds, &addresses); err != nil {
+		return nil, err
+	}
+	if len(extra.ValidatorPowers) != 0 && len(extra.ValidatorPowers) != len(addresses) {
+		return nil, errors.New("length of validator powers is not equal to length of validators")
+	}
+	for i, addr := range addresses {
+		validator := &Validator{address: addr}
+		if len(extra.ValidatorPowers) != 0 {
+			validator.votingPower = extra.ValidatorPowers[i]
+		}
+		validators = append(validators, validator)
+	}
+	return validators, nil
+}
+
+func (b *Block) Proposer(ctx context.Context, args BlockNumberArgs) (*Account, error) {
+	header, extra, err := b.tendermintHeader(ctx)
+	if err != nil || extra == nil {
+		return nil, err
+	}
+	proposer, err := blockProposer(header, extra)
+	if err != nil || proposer == nil {
+		return nil, err
+	}
+	return &Account{
+		backend:     b.backend,
+		address:     *proposer,
+		blockNumber: args.Number(),
+	}, nil
+}
+
+func (b *Block) Signers(ctx context.Context) (*[]common.Address, error) {
+	header, extra, err := b.tendermintHeader(ctx)
+	if err != nil || extra == nil {
+		return nil, err
+	}
+	signers, err := blockSigners(header, extra)
+	if err != nil {
+		return nil, err
+	}
+	return &signers, nil
+}
+
+func (b *Block) Validators(ctx context.Context) (*[]*Validator, error) {
+	_, extra, err := b.tendermintHeader(ctx)
+	if err != nil || extra == nil {
+		return nil, err
+	}
+	validators, err := blockValidators(extra)
+	if err != nil {
+		return nil, err
+	}
+	return &validators, nil
+}
+
 func (b *Block) TransactionCount(ctx context.Context) (*int32, error) {
 	block, err := b.resolve(ctx)
 	if err != nil || block == nil {
@@ -724,6 +879,66 @@ func (b *Block) OmmerAt(ctx context.Context, args struct{ Index int32 }) (*Block
 	}, nil
 }
 
+// Validator represents a validator of the set stored in the extra data of a checkpoint block.
+type Validator struct {
+	address     common.Address
+	votingPower *big.Int
+}
+
+func (v *Validator) Address(ctx context.Context) common.Address {
+	return v.address
+}
+
+func (v *Validator) VotingPower(ctx context.Context) *hexutil.Big {
+	return (*hexutil.Big)(v.votingPower)
+}
+
+// Candidate represents a candidate of the staking contract at a particular block.
+type Candidate struct {
+	address common.Address
+	data    staking.CandidateData
+}
+
+func (c *Candidate) Address(ctx context.Context) common.Address {
+	return c.address
+}
+
+func (c *Candidate) Owner(ctx context.Context) common.Address {
+	return c.data.Owner
+}
+
+func (c *Candidate) TotalStake(ctx context.Context) hexutil.Big {
+	if c.data.TotalStake == nil {
+		return hexutil.Big{}
+	}
+	return hexutil.Big(*c.data.TotalStake)
+}
+
+func (c *Candidate) Voters(ctx context.Context) []*Voter {
+	voters := make([]*Voter, 0, len(c.data.VoterStakes))
+	for voter, stake := range c.data.VoterStakes {
+		voters = append(voters, &Voter{address: voter, stake: stake})
+	}
+	sort.Slice(voters, func(i, j int) bool {
+		return bytes.Compare(voters[i].address.Bytes(), voters[j].address.Bytes()) < 0
+	})
+	return voters
+}
+
+// Voter represents the stake a voter put on a candidate.
+type Voter struct {
+	address common.Address
+	stake   *big.Int
+}
+
+func (v *Voter) Address(ctx context.Context) common.Address {
+	return v.address
+}
+
+func (v *Voter) Stake(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*v.stake)
+}
+
 // BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside
 // a block.
 type BlockFilterCriteria struct {
@@ -987,6 +1202,22 @@ func (r *Resolver) Block(ctx context.Context, args struct {
 	return block, nil
 }
 
+// FinalizedBlock returns the latest block which can no longer be reverted according to the consensus engine.
+func (r *Resolver) FinalizedBlock(ctx context.Context) (*Block, error) {
+	header, err := r.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	num := rpc.BlockNumber(header.Number.Uint64())
+	return &Block{
+		backend:   r.backend,
+		num:       &num,
+		hash:      header.Hash(),
+		header:    header,
+		canonical: isCanonical,
+	}, nil
+}
+
 func (r *Resolver) Blocks(ctx context.Context, args struct {
 	From hexutil.Uint64
 	To   *hexutil.Uint64
@@ -1016,6 +1247,51 @@ func (r *Resolver) Blocks(ctx context.Context, args struct {
 	return ret, nil
 }
 
+// candidates returns the candidates of the staking contract at the state of the given block,
+// or only the ones elected as validators.
+func (r *Resolver) candidates(ctx context.Context, number rpc.BlockNumber, elected bool) ([]*Candidate, error) {
+	config := r.backend.ChainConfig().Tendermint
+	if config == nil || config.StakingSCAddress == nil {
+		return nil, NoStakingContractError
+	}
+	state, header, err := r.backend.StateAndHeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		caller    = r.backend.StakingCaller(state, header)
+		addresses []common.Address
+	)
+	if elected {
+		addresses, err = caller.GetValidators(*config.StakingSCAddress)
+	} else {
+		addresses, err = caller.GetCandidates(*config.StakingSCAddress)
+	}
+	if err == staking.ErrEmptyValidatorSet {
+		return []*Candidate{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := caller.GetValidatorsData(*config.StakingSCAddress, addresses)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*Candidate, 0, len(addresses))
+	for _, addr := range addresses {
+		candidates = append(candidates, &Candidate{address: addr, data: data[addr]})
+	}
+	return candidates, nil
+}
+
+func (r *Resolver) Validators(ctx context.Context, args BlockNumberArgs) ([]*Candidate, error) {
+	return r.candidates(ctx, args.Number(), true)
+}
+
+func (r *Resolver) StakingCandidates(ctx context.Context, args BlockNumberArgs) ([]*Candidate, error) {
+	return r.candidates(ctx, args.Number(), false)
+}
+
 func (r *Resolver) Pending(ctx context.Context) *Pending {
 	return &Pending{r.backend}
 }
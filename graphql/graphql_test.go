@@ -17,7 +17,17 @@
 package graphql
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/rlp"
 )
 
 func TestBuildSchema(t *testing.T) {
@@ -27,3 +37,62 @@ func TestBuildSchema(t *testing.T) {
 		t.Errorf("Could not construct GraphQL handler: %v", err)
 	}
 }
+
+func TestTendermintExtra(t *testing.T) {
+	var (
+		proposerKey, _ = crypto.GenerateKey()
+		signerKey, _   = crypto.GenerateKey()
+		proposer       = crypto.PubkeyToAddress(proposerKey.PublicKey)
+		signer         = crypto.PubkeyToAddress(signerKey.PublicKey)
+		validators     = []common.Address{proposer, signer}
+		powers         = []*big.Int{big.NewInt(1), big.NewInt(2)}
+	)
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	require.NoError(t, err)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		MixDigest:  types.TendermintDigest,
+		Extra:      append(make([]byte, types.TendermintExtraVanity), payload...),
+	}
+	require.NoError(t, utils.WriteValSet(header, validators))
+	require.NoError(t, utils.WriteValSetPowers(header, powers))
+	seal, err := crypto.Sign(crypto.Keccak256(utils.SigHash(header).Bytes()), proposerKey)
+	require.NoError(t, err)
+	require.NoError(t, utils.WriteSeal(header, seal))
+	var committedSeals [][]byte
+	for _, key := range []*ecdsa.PrivateKey{proposerKey, signerKey} {
+		committedSeal, err := crypto.Sign(crypto.Keccak256(utils.PrepareCommittedSeal(header.Hash())), key)
+		require.NoError(t, err)
+		committedSeals = append(committedSeals, committedSeal)
+	}
+	require.NoError(t, utils.WriteCommittedSeals(header, committedSeals))
+
+	extra, err := types.ExtractTendermintExtra(header)
+	require.NoError(t, err)
+	recovered, err := blockProposer(header, extra)
+	require.NoError(t, err)
+	require.Equal(t, proposer, *recovered)
+	signers, err := blockSigners(header, extra)
+	require.NoError(t, err)
+	require.Equal(t, validators, signers)
+	valSet, err := blockValidators(extra)
+	require.NoError(t, err)
+	require.Len(t, valSet, len(validators))
+	for i, validator := range valSet {
+		require.Equal(t, validators[i], validator.address)
+		require.Equal(t, powers[i], validator.votingPower)
+	}
+
+	// the genesis block has neither a proposer nor signers, and other blocks than the checkpoints have no validators
+	extra = &types.TendermintExtra{}
+	recovered, err = blockProposer(header, extra)
+	require.NoError(t, err)
+	require.Nil(t, recovered)
+	signers, err = blockSigners(header, extra)
+	require.NoError(t, err)
+	require.Empty(t, signers)
+	valSet, err = blockValidators(extra)
+	require.NoError(t, err)
+	require.Empty(t, valSet)
+}
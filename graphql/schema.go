@@ -52,6 +52,12 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # Owner is the owner of an enterprise contract, or null if the account
+        # is not an enterprise contract.
+        owner: Address
+        # Providers is the list of providers paying the gas of the transactions
+        # to an enterprise contract.
+        providers: [Address!]!
     }
 
     # Log is an NeuralChain event log.
@@ -145,6 +151,9 @@ const schema string = `
         hash: Bytes32!
         # Parent is the parent block of this block.
         parent: Block
+        # Finalized is true if this block is on the canonical chain and can no
+        # longer be reverted according to the consensus engine.
+        finalized: Boolean!
         # Nonce is the block nonce, an 8 byte sequence determined by the miner.
         nonce: Bytes!
         # TransactionsRoot is the keccak256 hash of the root of the trie of transactions in this block.
@@ -206,6 +215,47 @@ const schema string = `
         # EstimateGas estimates the amount of gas that will be required for
         # successful execution of a transaction at the current block's state.
         estimateGas(data: CallData!): Long!
+        # Proposer is the validator that proposed this block, recovered from the
+        # seal in the extra data. It is null for the genesis block or if the chain
+        # does not run the Tendermint consensus.
+        proposer(block: Long): Account
+        # Signers is the list of validators that committed this block, recovered
+        # from the committed seals in the extra data. It is null if the chain does
+        # not run the Tendermint consensus.
+        signers: [Address!]
+        # Validators is the validator set stored in the extra data of a checkpoint
+        # block, it is empty for the other blocks. It is null if the chain does not
+        # run the Tendermint consensus.
+        validators: [Validator!]
+    }
+
+    # Validator is a validator of the set stored in a checkpoint block.
+    type Validator {
+        # Address is the address of the validator.
+        address: Address!
+        # VotingPower is the weight of the validator's votes, or null if the
+        # votes are not weighted.
+        votingPower: BigInt
+    }
+
+    # Candidate is a candidate of the staking contract.
+    type Candidate {
+        # Address is the address of the candidate.
+        address: Address!
+        # Owner is the account which registered the candidate.
+        owner: Address!
+        # TotalStake is the sum of the stakes of the voters, in wei.
+        totalStake: BigInt!
+        # Voters is the list of the voters of the candidate and their stakes.
+        voters: [Voter!]!
+    }
+
+    # Voter is a voter of a candidate of the staking contract.
+    type Voter {
+        # Address is the address of the voter.
+        address: Address!
+        # Stake is the stake of the voter on the candidate, in wei.
+        stake: BigInt!
     }
 
     # CallData represents the data associated with a local contract call.
@@ -298,6 +348,10 @@ const schema string = `
         # Blocks returns all the blocks between two numbers, inclusive. If
         # to is not supplied, it defaults to the most recent known block.
         blocks(from: Long!, to: Long): [Block!]!
+        # FinalizedBlock returns the most recent block which can no longer be
+        # reverted according to the consensus engine. Under Tendermint it is the
+        # most recent known block.
+        finalizedBlock: Block
         # Pending returns the current pending state.
         pending: Pending!
         # Transaction returns a transaction specified by its hash.
@@ -311,6 +365,12 @@ const schema string = `
         protocolVersion: Int!
         # Syncing returns information on the current synchronisation state.
         syncing: SyncState
+        # Validators returns the candidates the staking contract elects as
+        # validators at the state of the given block, the latest one by default.
+        validators(block: Long): [Candidate!]!
+        # StakingCandidates returns all the candidates of the staking contract at
+        # the state of the given block, the latest one by default.
+        stakingCandidates(block: Long): [Candidate!]!
     }
 
     type Mutation {
@@ -105,6 +105,8 @@ type P2PChainReader interface {
 	CurrentHeader() *types.Header
 	// GetHeaderByNumber retrieves a block header from the database by number.
 	GetHeaderByNumber(number uint64) *types.Header
+	// LatestCheckpoint retrieves the latest epoch checkpoint header among a header and its ancestors.
+	LatestCheckpoint(header *types.Header) *types.Header
 }
 
 // SyncProgress gives progress indications when the node is synchronising with
@@ -147,6 +149,14 @@ type SendTxArgs struct {
 	Input    *hexutil.Bytes
 	Provider *common.Address
 	Owner    *common.Address
+	// NewOwner proposes a new owner for the enterprise contract To, AcceptOwnership accepts
+	// the ownership of To by its pending owner.
+	NewOwner        *common.Address
+	AcceptOwnership bool
+	// ProviderBudget sets the gas budget per epoch of a provider of the enterprise contract To,
+	// SenderAllowance sets the gas allowance per epoch of a sender. A limit of 0 removes it.
+	ProviderBudget  *types.GasLimitMsg
+	SenderAllowance *types.GasLimitMsg
 }
 
 // A ContractCaller provides contract calls, essentially transactions that are executed by
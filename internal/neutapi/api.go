@@ -111,40 +111,106 @@ func NewPublicTxPoolAPI(b Backend) *PublicTxPoolAPI {
 	return &PublicTxPoolAPI{b}
 }
 
-// Content returns the transactions contained within the transaction pool.
-func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
-	content := map[string]map[string]map[string]*RPCTransaction{
-		"pending": make(map[string]map[string]*RPCTransaction),
-		"queued":  make(map[string]map[string]*RPCTransaction),
+// Content returns the transactions contained within the transaction pool. The transactions sponsored
+// by a provider are also listed under it, grouped the same way.
+func (s *PublicTxPoolAPI) Content() map[string]interface{} {
+	var (
+		content = map[string]map[string]map[string]*RPCTransaction{
+			"pending": make(map[string]map[string]*RPCTransaction),
+			"queued":  make(map[string]map[string]*RPCTransaction),
+		}
+		providers      = make(map[string]map[string]map[string]map[string]*RPCTransaction)
+		pending, queue = s.b.TxPoolContent()
+	)
+	// Flatten the transactions and break the sponsored ones down by provider
+	flatten := func(status string, txs map[common.Address]types.Transactions) {
+		for account, txs := range txs {
+			dump := make(map[string]*RPCTransaction)
+			for _, tx := range txs {
+				nonce := fmt.Sprintf("%d", tx.Nonce())
+				dump[nonce] = newRPCPendingTransaction(tx)
+
+				if provider := sponsorOf(tx); provider != nil {
+					if providers[provider.String()] == nil {
+						providers[provider.String()] = map[string]map[string]map[string]*RPCTransaction{
+							"pending": make(map[string]map[string]*RPCTransaction),
+							"queued":  make(map[string]map[string]*RPCTransaction),
+						}
+					}
+					sponsored := providers[provider.String()][status]
+					if sponsored[account.String()] == nil {
+						sponsored[account.String()] = make(map[string]*RPCTransaction)
+					}
+					sponsored[account.String()][nonce] = dump[nonce]
+				}
+			}
+			content[status][account.String()] = dump
+		}
 	}
-	pending, queue := s.b.TxPoolContent()
+	flatten("pending", pending)
+	flatten("queued", queue)
 
-	// Flatten the pending transactions
-	for account, txs := range pending {
-		dump := make(map[string]*RPCTransaction)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
-		}
-		content["pending"][account.String()] = dump
+	return map[string]interface{}{
+		"pending":   content["pending"],
+		"queued":    content["queued"],
+		"providers": providers,
 	}
-	// Flatten the queued transactions
-	for account, txs := range queue {
-		dump := make(map[string]*RPCTransaction)
-		for _, tx := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+}
+
+// ProviderPoolStatus is the number of pending and queued transactions sponsored by a provider in the
+// transaction pool, along with the sum of their fees.
+type ProviderPoolStatus struct {
+	Pending hexutil.Uint `json:"pending"`
+	Queued  hexutil.Uint `json:"queued"`
+	Fees    *hexutil.Big `json:"fees"`
+}
+
+// Status returns the number of pending and queued transaction in the pool, and the status of the
+// transactions sponsored by each provider.
+func (s *PublicTxPoolAPI) Status() map[string]interface{} {
+	var (
+		pending, queue       = s.b.Stats()
+		pendingTxs, queueTxs = s.b.TxPoolContent()
+		providers            = make(map[string]*ProviderPoolStatus)
+	)
+	count := func(txs map[common.Address]types.Transactions, pending bool) {
+		for _, txs := range txs {
+			for _, tx := range txs {
+				provider := sponsorOf(tx)
+				if provider == nil {
+					continue
+				}
+				status := providers[provider.String()]
+				if status == nil {
+					status = &ProviderPoolStatus{Fees: new(hexutil.Big)}
+					providers[provider.String()] = status
+				}
+				if pending {
+					status.Pending++
+				} else {
+					status.Queued++
+				}
+				status.Fees.ToInt().Add(status.Fees.ToInt(), tx.TransactionFee())
+			}
 		}
-		content["queued"][account.String()] = dump
 	}
-	return content
+	count(pendingTxs, true)
+	count(queueTxs, false)
+
+	return map[string]interface{}{
+		"pending":   hexutil.Uint(pending),
+		"queued":    hexutil.Uint(queue),
+		"providers": providers,
+	}
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
-	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+// sponsorOf returns the provider paying the gas of the transaction, nil if its sender pays for it.
+func sponsorOf(tx *types.Transaction) *common.Address {
+	var signer types.Signer = types.BaseSigner{}
+	if tx.Protected() {
+		signer = types.NewOmahaSigner(tx.ChainId())
 	}
+	return tx.SignedProvider(signer)
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -769,6 +835,44 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// GetOwner returns the owner of the enterprise contract at the given address in the state
+// of the given block number, or nil if the account is not an enterprise contract. The
+// rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block numbers are also allowed.
+func (s *PublicBlockChainAPI) GetOwner(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*common.Address, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	owner := state.GetOwner(address)
+	return owner, state.Error()
+}
+
+// GetProviders returns the providers of the enterprise contract at the given address in the
+// state of the given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
+// block numbers are also allowed.
+func (s *PublicBlockChainAPI) GetProviders(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	providers := state.GetProviders(address)
+	if providers == nil {
+		providers = []common.Address{}
+	}
+	return providers, state.Error()
+}
+
+// IsEnterprise returns whether the account at the given address is an enterprise contract,
+// which is a contract created with an owner, in the state of the given block number. The
+// rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block numbers are also allowed.
+func (s *PublicBlockChainAPI) IsEnterprise(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (bool, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return false, err
+	}
+	return state.GetOwner(address) != nil, state.Error()
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From     *common.Address `json:"from"`
@@ -1432,6 +1536,32 @@ type SendTxArgs struct {
 	Input    *hexutil.Bytes  `json:"input"`
 	Owner    *common.Address `json:"owner" rlp:"nil"`
 	Provider *common.Address `json:"provider" rlp:"nil"`
+	// NewOwner proposes a new owner for the enterprise contract args.To, AcceptOwnership accepts
+	// the ownership of args.To by its pending owner.
+	NewOwner        *common.Address `json:"newOwner" rlp:"nil"`
+	AcceptOwnership bool            `json:"acceptOwnership"`
+	// ProviderBudget sets the gas budget per epoch of a provider of the enterprise contract args.To,
+	// SenderAllowance sets the gas allowance per epoch of a sender.
+	ProviderBudget  *GasLimitArgs `json:"providerBudget" rlp:"nil"`
+	SenderAllowance *GasLimitArgs `json:"senderAllowance" rlp:"nil"`
+}
+
+// GasLimitArgs represents the gas limit per epoch of a provider or a sender of an enterprise contract,
+// a limit of 0 removes it.
+type GasLimitArgs struct {
+	Address common.Address `json:"address"`
+	Limit   hexutil.Uint64 `json:"limit"`
+}
+
+// enterpriseFields returns the number of the fields set to manage the enterprise contract args.To
+func (args *SendTxArgs) enterpriseFields() int {
+	n := 0
+	for _, set := range []bool{args.NewOwner != nil, args.AcceptOwnership, args.ProviderBudget != nil, args.SenderAllowance != nil} {
+		if set {
+			n++
+		}
+	}
+	return n
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1456,6 +1586,21 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
 		return errors.New(`Both "data" and "input" are set and not equal. Please use "input" to pass transaction call data.`)
 	}
+	if n := args.enterpriseFields(); n > 0 {
+		if n > 1 {
+			return errors.New(`more than one of "newOwner", "acceptOwnership", "providerBudget" and "senderAllowance" are set`)
+		}
+		if args.To == nil {
+			return errors.New(`enterprise contract transaction without enterprise contract provided`)
+		}
+		if args.Data != nil || args.Input != nil {
+			return errors.New(`enterprise contract transaction with data provided`)
+		}
+		if args.Gas == nil {
+			gas := hexutil.Uint64(params.TxGas)
+			args.Gas = &gas
+		}
+	}
 	if args.To == nil {
 		// Contract creation
 		var input []byte
@@ -1510,6 +1655,23 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 		}
 		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, option)
 	}
+	// encoding the fixed size messages of the enterprise contract transactions never fails
+	if args.NewOwner != nil {
+		tx, _ := types.NewTransferOwnershipTransaction(uint64(*args.Nonce), *args.To, uint64(*args.Gas), (*big.Int)(args.GasPrice), *args.NewOwner)
+		return tx
+	}
+	if args.AcceptOwnership {
+		tx, _ := types.NewAcceptOwnershipTransaction(uint64(*args.Nonce), *args.To, uint64(*args.Gas), (*big.Int)(args.GasPrice))
+		return tx
+	}
+	if args.ProviderBudget != nil {
+		tx, _ := types.NewSetGasLimitTransaction(uint64(*args.Nonce), *args.To, uint64(*args.Gas), (*big.Int)(args.GasPrice), args.ProviderBudget.Address, uint64(args.ProviderBudget.Limit), true)
+		return tx
+	}
+	if args.SenderAllowance != nil {
+		tx, _ := types.NewSetGasLimitTransaction(uint64(*args.Nonce), *args.To, uint64(*args.Gas), (*big.Int)(args.GasPrice), args.SenderAllowance.Address, uint64(args.SenderAllowance.Limit), false)
+		return tx
+	}
 	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
 }
 
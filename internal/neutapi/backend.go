@@ -71,6 +71,7 @@ type Backend interface {
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
+	MinGasPrice(parent *types.Header) *big.Int
 	CurrentBlock() *types.Block
 }
 
@@ -33,6 +33,7 @@ var Modules = map[string]string{
 	"swarmfs":    SwarmfsJs,
 	"txpool":     TxpoolJs,
 	"tendermint": TendermintJs,
+	"staking":    StakingJs,
 }
 
 const ChequebookJs = `
@@ -520,6 +521,24 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getOwner',
+			call: 'neut_getOwner',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProviders',
+			call: 'neut_getProviders',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'isEnterprise',
+			call: 'neut_isEnterprise',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -729,6 +748,11 @@ web3._extend({
 			outputFormatter: function(status) {
 				status.pending = web3._extend.utils.toDecimal(status.pending);
 				status.queued = web3._extend.utils.toDecimal(status.queued);
+				for (var provider in status.providers) {
+					status.providers[provider].pending = web3._extend.utils.toDecimal(status.providers[provider].pending);
+					status.providers[provider].queued = web3._extend.utils.toDecimal(status.providers[provider].queued);
+					status.providers[provider].fees = web3._extend.utils.toBigNumber(status.providers[provider].fees);
+				}
 				return status;
 			}
 		}),
@@ -796,6 +820,61 @@ web3._extend({
 			params: 1,
 			inputFormatter:[null]
 		}),
+		new web3._extend.Method({
+			name: 'getConsensusState',
+			call: 'tendermint_getConsensusState',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getProposer',
+			call: 'tendermint_getProposer',
+			params: 2,
+			inputFormatter:[null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochReward',
+			call: 'tendermint_getEpochReward',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRewardsByAddress',
+			call: 'tendermint_getRewardsByAddress',
+			params: 3,
+			inputFormatter:[web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+	],
+	properties: []
+});
+`
+
+const StakingJs = `
+web3._extend({
+	property: 'staking',
+	methods: [
+		new web3._extend.Method({
+			name: 'getCandidates',
+			call: 'staking_getCandidates',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCandidateData',
+			call: 'staking_getCandidateData',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getParams',
+			call: 'staking_getParams',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getNextValidators',
+			call: 'staking_getNextValidators',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 	],
 	properties: []
 });
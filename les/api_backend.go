@@ -24,6 +24,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/accounts"
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/math"
+	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/bloombits"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
@@ -49,6 +50,10 @@ func (b *LesApiBackend) ChainConfig() *params.ChainConfig {
 	return b.neut.chainConfig
 }
 
+func (b *LesApiBackend) MinGasPrice(parent *types.Header) *big.Int {
+	return b.neut.BlockChain().MinGasPrice(parent)
+}
+
 func (b *LesApiBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.neut.BlockChain().CurrentHeader())
 }
@@ -62,6 +67,10 @@ func (b *LesApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNum
 	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
 		return b.neut.blockchain.CurrentHeader(), nil
 	}
+	if blockNr == rpc.FinalizedBlockNumber {
+		head := b.neut.blockchain.CurrentHeader()
+		return b.neut.blockchain.GetHeaderByNumberOdr(ctx, consensus.FinalizedNumber(b.neut.engine, head))
+	}
 	return b.neut.blockchain.GetHeaderByNumberOdr(ctx, uint64(blockNr))
 }
 
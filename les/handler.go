@@ -84,6 +84,7 @@ type BlockChain interface {
 	GetAncestor(hash common.Hash, number, ancestor uint64, maxNonCanonical *uint64) (common.Hash, uint64)
 	Genesis() *types.Block
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	Engine() consensus.Engine
 }
 
 type txPool interface {
@@ -113,6 +114,7 @@ type ProtocolManager struct {
 	servingQueue *servingQueue
 	downloader   *downloader.Downloader
 	fetcher      *lightFetcher
+	tdmSyncer    *tendermintSyncer // follows the checkpoint headers instead of downloading every header of Tendermint chains
 	ulc          *ulc
 	peers        *peerSet
 
@@ -189,6 +191,9 @@ func NewProtocolManager(
 		manager.downloader = downloader.New(checkpoint, chainDb, nil, manager.eventMux, nil, blockchain, removePeer)
 		manager.peers.notify((*downloaderPeerNotify)(manager))
 		manager.fetcher = newLightFetcher(manager)
+		if lightChain, ok := blockchain.(*light.LightChain); ok && chainConfig.Tendermint != nil {
+			manager.tdmSyncer = newTendermintSyncer(manager, lightChain)
+		}
 	}
 	return manager, nil
 }
@@ -600,6 +605,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
 		p.fcServer.ReceivedReply(resp.ReqID, resp.BV)
+		if pm.tdmSyncer != nil && pm.tdmSyncer.deliver(resp.ReqID, resp.Headers) {
+			break
+		}
 		if pm.fetcher != nil && pm.fetcher.requestedID(resp.ReqID) {
 			pm.fetcher.deliverHeaders(p, resp.ReqID, resp.Headers)
 		} else {
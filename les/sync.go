@@ -72,6 +72,14 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		return
 	}
 
+	// Tendermint headers are final, so only the checkpoints and the head are needed
+	if pm.tdmSyncer != nil {
+		if err := pm.tdmSyncer.synchronise(peer); err != nil {
+			peer.Log().Debug("Tendermint light sync failed", "err", err)
+		}
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 	pm.blockchain.(*light.LightChain).SyncCht(ctx)
@@ -0,0 +1,145 @@
+package les
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/light"
+)
+
+var (
+	errTendermintSyncTimeout = errors.New("tendermint header request timed out")
+	errInvalidHeaderResponse = errors.New("invalid header response")
+)
+
+// tendermintSyncer follows a Tendermint chain by downloading only the checkpoint header of each epoch and the latest
+// header of a peer. The headers are final once their committed seals are verified by the light chain, so the
+// headers in between are never downloaded.
+type tendermintSyncer struct {
+	pm    *ProtocolManager
+	chain *light.LightChain
+
+	lock    sync.Mutex
+	pending map[uint64]chan []*types.Header // header requests waiting for their response by request id
+}
+
+func newTendermintSyncer(pm *ProtocolManager, chain *light.LightChain) *tendermintSyncer {
+	return &tendermintSyncer{
+		pm:      pm,
+		chain:   chain,
+		pending: make(map[uint64]chan []*types.Header),
+	}
+}
+
+// synchronise downloads and verifies the checkpoint headers up to the head of the peer, then the head itself.
+func (s *tendermintSyncer) synchronise(p *peer) error {
+	head := p.headBlockInfo()
+	for {
+		// the checkpoints are requested assuming the epoch length stays the one of the latest checkpoint,
+		// the ones following a change of the epoch length are requested again once the change is verified
+		var (
+			checkpoint = s.chain.TendermintCheckpoint()
+			number     = checkpoint.Number.Uint64()
+			epoch      = s.chain.TendermintEpochLength(checkpoint)
+		)
+		if number+epoch > head.Number {
+			break
+		}
+		amount := (head.Number - number) / epoch
+		if amount > MaxHeaderFetch {
+			amount = MaxHeaderFetch
+		}
+		headers, err := s.request(p, func(reqID, cost uint64) error {
+			return p.RequestHeadersByNumber(reqID, cost, number+epoch, int(amount), int(epoch-1), false)
+		}, amount)
+		if err != nil {
+			return err
+		}
+		if len(headers) == 0 {
+			return errInvalidHeaderResponse
+		}
+		if i, err := s.chain.InsertTendermintHeaders(headers, nil); err != nil {
+			if i > 0 && err == light.ErrNonConsecutiveCheckpoint {
+				continue
+			}
+			p.Log().Debug("Invalid tendermint checkpoint", "number", headers[i].Number, "hash", headers[i].Hash(), "err", err)
+			return err
+		}
+	}
+	if current := s.chain.CurrentHeader(); current.Number.Uint64() >= head.Number {
+		return nil
+	}
+
+	headers, err := s.request(p, func(reqID, cost uint64) error {
+		return p.RequestHeadersByHash(reqID, cost, head.Hash, 1, 0, false)
+	}, 1)
+	if err != nil {
+		return err
+	}
+	if len(headers) != 1 || headers[0].Hash() != head.Hash {
+		return errInvalidHeaderResponse
+	}
+	if _, err := s.chain.InsertTendermintHeaders(nil, headers[0]); err != nil {
+		p.Log().Debug("Invalid tendermint head", "number", head.Number, "hash", head.Hash, "err", err)
+		return err
+	}
+	return nil
+}
+
+// request sends a header request to the peer through the request distributor and waits for its response
+func (s *tendermintSyncer) request(p *peer, send func(reqID, cost uint64) error, amount uint64) ([]*types.Header, error) {
+	var (
+		reqID    = genReqID()
+		response = make(chan []*types.Header, 1)
+	)
+	s.lock.Lock()
+	s.pending[reqID] = response
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.pending, reqID)
+		s.lock.Unlock()
+	}()
+
+	rq := &distReq{
+		getCost: func(dp distPeer) uint64 {
+			return dp.(*peer).GetRequestCost(GetBlockHeadersMsg, int(amount))
+		},
+		canSend: func(dp distPeer) bool {
+			return dp.(*peer) == p
+		},
+		request: func(dp distPeer) func() {
+			cost := p.GetRequestCost(GetBlockHeadersMsg, int(amount))
+			p.fcServer.QueuedRequest(reqID, cost)
+			return func() { send(reqID, cost) }
+		},
+	}
+	if _, ok := <-s.pm.reqDist.queue(rq); !ok {
+		return nil, light.ErrNoPeers
+	}
+
+	select {
+	case headers := <-response:
+		return headers, nil
+	case <-time.After(hardRequestTimeout):
+		return nil, errTendermintSyncTimeout
+	case <-s.pm.quitSync:
+		return nil, errTendermintSyncTimeout
+	}
+}
+
+// deliver hands the headers to the pending request of the id, it returns false if there is no such request
+func (s *tendermintSyncer) deliver(reqID uint64, headers []*types.Header) bool {
+	s.lock.Lock()
+	response, ok := s.pending[reqID]
+	s.lock.Unlock()
+	if ok {
+		select {
+		case response <- headers:
+		default: // duplicated response
+		}
+	}
+	return ok
+}
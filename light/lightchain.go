@@ -28,6 +28,7 @@ import (
 
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/consensus"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/state"
@@ -60,6 +61,8 @@ type LightChain struct {
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
+	tendermintEpochs *utils.EpochSchedule // finds the epoch checkpoints of a Tendermint chain
+
 	bodyCache    *lru.Cache // Cache for the most recent block bodies
 	bodyRLPCache *lru.Cache // Cache for the most recent block bodies in RLP encoded format
 	blockCache   *lru.Cache // Cache for the most recent entire blocks
@@ -97,6 +100,9 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 	if err != nil {
 		return nil, err
 	}
+	if config.Tendermint != nil {
+		bc.tendermintEpochs = utils.NewEpochSchedule(config.Tendermint.Epoch)
+	}
 	bc.genesisBlock, _ = bc.GetBlockByNumber(NoOdr, 0)
 	if bc.genesisBlock == nil {
 		return nil, core.ErrNoGenesis
@@ -462,6 +468,12 @@ func (lc *LightChain) GetHeaderByNumberOdr(ctx context.Context, number uint64) (
 // Config retrieves the header chain's chain configuration.
 func (lc *LightChain) Config() *params.ChainConfig { return lc.hc.Config() }
 
+// MinGasPrice retrieves the minimum gas price of the block following the parent header,
+// which is governed by the consensus engine or the gas price of the chain config.
+func (lc *LightChain) MinGasPrice(parent *types.Header) *big.Int {
+	return consensus.MinGasPrice(lc.engine, lc.hc, parent)
+}
+
 func (lc *LightChain) SyncCht(ctx context.Context) bool {
 	// If we don't have a CHT indexer, abort
 	if lc.odr.ChtIndexer() == nil {
@@ -0,0 +1,157 @@
+package light
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/log"
+)
+
+var (
+	// ErrNotTendermint is returned if the Tendermint headers are inserted into a chain of another consensus engine
+	ErrNotTendermint = errors.New("chain is not a tendermint chain")
+	// ErrNonConsecutiveCheckpoint is returned if a checkpoint header is not the one of the epoch following the last verified checkpoint
+	ErrNonConsecutiveCheckpoint = errors.New("non consecutive checkpoint header")
+	// ErrInvalidTendermintHead is returned if the head header is not in the epoch following the last verified checkpoint
+	ErrInvalidTendermintHead = errors.New("head header out of the verified epoch")
+	// ErrConflictingFinalHeader is returned if a verified header conflicts with a final header of the local chain,
+	// which only happens if more than 1/3 of the voting power is byzantine
+	ErrConflictingFinalHeader = errors.New("header conflicts with a final header")
+)
+
+// TendermintCheckpoint returns the latest checkpoint header of the local chain. Its validator set signs the headers
+// of the next epoch, including the next checkpoint header.
+func (lc *LightChain) TendermintCheckpoint() *types.Header {
+	if lc.tendermintEpochs == nil {
+		return nil
+	}
+	checkpoints := lc.tendermintEpochs.CanonicalCheckpoints(lc.hc, lc.hc.CurrentHeader().Number.Uint64())
+	if header := lc.hc.GetHeaderByNumber(checkpoints[len(checkpoints)-1]); header != nil {
+		return header
+	}
+	return lc.genesisBlock.Header()
+}
+
+// TendermintEpochLength returns the number of blocks of the epoch following the checkpoint header
+func (lc *LightChain) TendermintEpochLength(checkpoint *types.Header) uint64 {
+	if lc.tendermintEpochs == nil {
+		return 0
+	}
+	return lc.tendermintEpochs.EpochLength(checkpoint)
+}
+
+// InsertTendermintHeaders follows a Tendermint chain without downloading every header. The checkpoint headers must be
+// the ones of the consecutive epochs following the latest local checkpoint, each of them is verified by the committed
+// seals of the validator set handed off by the previous one. From the ValidatorSetHash fork on, the seals cover the hash
+// of the handed off validator set, so a verified checkpoint authenticates the validators of the next epoch. The optional
+// head header is verified by the validator set of the last checkpoint and becomes the head of the chain.
+//
+// Headers with valid committed seals are final, they are never reverted. If an error is returned, the index of
+// the failing header is returned as well, len(checkpoints) refers to the head. The headers before it are inserted.
+func (lc *LightChain) InsertTendermintHeaders(checkpoints []*types.Header, head *types.Header) (int, error) {
+	config := lc.hc.Config().Tendermint
+	if config == nil {
+		return 0, ErrNotTendermint
+	}
+
+	lc.chainmu.Lock()
+	defer lc.chainmu.Unlock()
+
+	var (
+		trusted   = lc.TendermintCheckpoint()
+		policy    = tendermint.ProposerPolicy(config.ProposerPolicy)
+		genesisTd = lc.hc.GetTd(lc.genesisBlock.Hash(), 0)
+		inserted  *types.Header
+	)
+	verify := func(header *types.Header) error {
+		valSet, err := utils.GetValSet(trusted, policy, header.Number.Int64())
+		if err != nil {
+			return err
+		}
+		if err := verifyTendermintHeader(header, valSet); err != nil {
+			return err
+		}
+		if local := lc.hc.GetHeaderByNumber(header.Number.Uint64()); local != nil && local.Hash() != header.Hash() {
+			return ErrConflictingFinalHeader
+		}
+		lc.writeFinalHeader(header, genesisTd)
+		inserted = header
+		return nil
+	}
+	defer func() {
+		if inserted == nil || inserted.Number.Cmp(lc.hc.CurrentHeader().Number) <= 0 {
+			return
+		}
+		lc.hc.SetCurrentHeader(inserted)
+		log.Info("Updated latest header based on tendermint checkpoints", "number", inserted.Number, "hash", inserted.Hash())
+		lc.postChainEvents([]interface{}{core.ChainEvent{Block: types.NewBlockWithHeader(inserted), Hash: inserted.Hash()}})
+	}()
+
+	for i, header := range checkpoints {
+		if header.Number.Uint64() != lc.tendermintEpochs.NextCheckpointNumber(trusted) {
+			return i, ErrNonConsecutiveCheckpoint
+		}
+		if _, err := utils.GetValSetAddresses(header); err != nil {
+			return i, err
+		}
+		if err := utils.VerifyValSetHash(header, lc.hc.Config().IsValidatorSetHash(header.Number)); err != nil {
+			return i, err
+		}
+		if err := verify(header); err != nil {
+			return i, err
+		}
+		// the validator set of the next epoch is handed off by the verified checkpoint
+		trusted = header
+	}
+	if head == nil {
+		return len(checkpoints), nil
+	}
+	if head.Number.Uint64() <= trusted.Number.Uint64() || head.Number.Uint64() > lc.tendermintEpochs.NextCheckpointNumber(trusted) {
+		return len(checkpoints), ErrInvalidTendermintHead
+	}
+	return len(checkpoints), verify(head)
+}
+
+// verifyTendermintHeader checks whether the header is proposed by a validator of the set and committed by a quorum of it
+func verifyTendermintHeader(header *types.Header, valSet tendermint.ValidatorSet) error {
+	extra, err := types.ExtractTendermintExtra(header)
+	if err != nil {
+		return tendermint.ErrInvalidExtraDataFormat
+	}
+	if header.MixDigest != types.TendermintDigest {
+		return tendermint.ErrInvalidMixDigest
+	}
+	if header.Difficulty == nil || header.Difficulty.Cmp(common.Big1) != 0 {
+		return tendermint.ErrInvalidDifficulty
+	}
+	proposer, err := utils.GetSignatureAddress(utils.SigHash(header).Bytes(), extra.Seal)
+	if err != nil {
+		return err
+	}
+	if proposer != header.Coinbase {
+		return tendermint.ErrCoinBaseInvalid
+	}
+	if _, val := valSet.GetByAddress(proposer); val == nil {
+		return tendermint.ErrUnauthorized
+	}
+	return utils.VerifyCommittedSeals(header, valSet)
+}
+
+// writeFinalHeader stores the header as the canonical one of its number. Its ancestors may be missing,
+// so its total difficulty is derived from the constant difficulty of the Tendermint headers.
+func (lc *LightChain) writeFinalHeader(header *types.Header, genesisTd *big.Int) {
+	var (
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+		td     = new(big.Int).Add(genesisTd, new(big.Int).Mul(header.Number, header.Difficulty))
+	)
+	rawdb.WriteHeader(lc.chainDb, header)
+	rawdb.WriteTd(lc.chainDb, hash, number, td)
+	rawdb.WriteCanonicalHash(lc.chainDb, hash, number)
+}
@@ -0,0 +1,261 @@
+package light
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus/ethash"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/utils"
+	"github.com/lvbin2012/NeuralChain/core"
+	"github.com/lvbin2012/NeuralChain/core/rawdb"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/params"
+	"github.com/lvbin2012/NeuralChain/rlp"
+)
+
+const testTendermintEpoch = 3
+
+func newTestKeys(t *testing.T, n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	var (
+		keys  []*ecdsa.PrivateKey
+		addrs []common.Address
+	)
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+		addrs = append(addrs, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return keys, addrs
+}
+
+func emptyTendermintExtra(t *testing.T) []byte {
+	payload, err := rlp.EncodeToBytes(&types.TendermintExtra{})
+	require.NoError(t, err)
+	return append(make([]byte, types.TendermintExtraVanity), payload...)
+}
+
+func newTendermintExtra(t *testing.T, validators []common.Address) []byte {
+	header := &types.Header{Extra: emptyTendermintExtra(t)}
+	require.NoError(t, utils.WriteValSet(header, validators))
+	return header.Extra
+}
+
+// makeTendermintHeader returns a header of the number proposed by the first signer and committed by all signers.
+// Checkpoint headers record the next validators.
+func makeTendermintHeader(t *testing.T, number uint64, signers []*ecdsa.PrivateKey, next []common.Address) *types.Header {
+	header := newTendermintHeader(t, number, signers)
+	if number%testTendermintEpoch == 0 {
+		require.NoError(t, utils.WriteValSet(header, next))
+	}
+	return sealTendermintHeader(t, header, signers)
+}
+
+// makeHashedCheckpoint returns a checkpoint header like makeTendermintHeader, which records the hash of the next validators
+func makeHashedCheckpoint(t *testing.T, number uint64, signers []*ecdsa.PrivateKey, next []common.Address) *types.Header {
+	header := newTendermintHeader(t, number, signers)
+	require.NoError(t, utils.WriteValSet(header, next))
+	require.NoError(t, utils.WriteValSetHash(header))
+	return sealTendermintHeader(t, header, signers)
+}
+
+// makeGovernedCheckpoint returns a checkpoint header like makeTendermintHeader, which records the governed length of the next epoch
+func makeGovernedCheckpoint(t *testing.T, number uint64, signers []*ecdsa.PrivateKey, next []common.Address, epoch int64) *types.Header {
+	header := newTendermintHeader(t, number, signers)
+	require.NoError(t, utils.WriteValSet(header, next))
+	require.NoError(t, utils.WriteGovernedParams(header, []*types.GovernedParam{
+		{Param: staking.GovernedEpoch, Value: big.NewInt(epoch)},
+	}))
+	return sealTendermintHeader(t, header, signers)
+}
+
+func newTendermintHeader(t *testing.T, number uint64, signers []*ecdsa.PrivateKey) *types.Header {
+	return &types.Header{
+		ParentHash: common.BigToHash(new(big.Int).SetUint64(number)),
+		Coinbase:   crypto.PubkeyToAddress(signers[0].PublicKey),
+		Number:     new(big.Int).SetUint64(number),
+		Difficulty: big.NewInt(1),
+		GasLimit:   params.GenesisGasLimit,
+		MixDigest:  types.TendermintDigest,
+		Extra:      emptyTendermintExtra(t),
+	}
+}
+
+// sealTendermintHeader signs the header by the first signer and commits it by all signers
+func sealTendermintHeader(t *testing.T, header *types.Header, signers []*ecdsa.PrivateKey) *types.Header {
+	seal, err := crypto.Sign(crypto.Keccak256(utils.SigHash(header).Bytes()), signers[0])
+	require.NoError(t, err)
+	require.NoError(t, utils.WriteSeal(header, seal))
+
+	var committedSeals [][]byte
+	for _, signer := range signers {
+		committedSeal, err := crypto.Sign(crypto.Keccak256(utils.PrepareCommittedSeal(header.Hash())), signer)
+		require.NoError(t, err)
+		committedSeals = append(committedSeals, committedSeal)
+	}
+	require.NoError(t, utils.WriteCommittedSeals(header, committedSeals))
+	return header
+}
+
+func newTendermintLightChain(t *testing.T, validators []common.Address) *LightChain {
+	return newForkedTendermintLightChain(t, validators, nil)
+}
+
+// newForkedTendermintLightChain returns a light chain like newTendermintLightChain, with the ValidatorSetHash fork at the given block
+func newForkedTendermintLightChain(t *testing.T, validators []common.Address, validatorSetHashBlock *big.Int) *LightChain {
+	db := rawdb.NewMemoryDatabase()
+	config := *params.TestChainConfig
+	config.Tendermint = &params.TendermintConfig{Epoch: testTendermintEpoch}
+	config.ValidatorSetHashBlock = validatorSetHashBlock
+	gspec := &core.Genesis{
+		Config:     &config,
+		ExtraData:  newTendermintExtra(t, validators),
+		Difficulty: big.NewInt(1),
+		Mixhash:    types.TendermintDigest,
+	}
+	gspec.MustCommit(db)
+	lc, err := NewLightChain(&dummyOdr{db: db}, gspec.Config, ethash.NewFullFaker())
+	require.NoError(t, err)
+	return lc
+}
+
+func TestInsertTendermintHeaders(t *testing.T) {
+	var (
+		genesisKeys, genesisVals = newTestKeys(t, 4)
+		nextKeys, nextVals       = newTestKeys(t, 4)
+	)
+
+	t.Run("validator set hand-off", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		checkpoints := []*types.Header{
+			makeTendermintHeader(t, 3, genesisKeys[:3], genesisVals),
+			// the validators of the second epoch hand off to the next validators
+			makeTendermintHeader(t, 6, genesisKeys, nextVals),
+			makeTendermintHeader(t, 9, nextKeys, nextVals),
+		}
+		head := makeTendermintHeader(t, 11, nextKeys[1:], nil)
+
+		_, err := lc.InsertTendermintHeaders(checkpoints, head)
+		require.NoError(t, err)
+		require.Equal(t, head.Hash(), lc.CurrentHeader().Hash())
+		require.Equal(t, checkpoints[2].Hash(), lc.TendermintCheckpoint().Hash())
+		require.Equal(t, checkpoints[1].Hash(), lc.GetHeaderByNumber(6).Hash())
+		require.Nil(t, lc.GetHeaderByNumber(10))
+		require.Equal(t, big.NewInt(12), lc.GetTd(head.Hash(), 11))
+
+		// the chain is followed from the new head
+		next := makeTendermintHeader(t, 12, nextKeys, nextVals)
+		_, err = lc.InsertTendermintHeaders([]*types.Header{next}, nil)
+		require.NoError(t, err)
+		require.Equal(t, next.Hash(), lc.CurrentHeader().Hash())
+	})
+
+	t.Run("no quorum", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		checkpoints := []*types.Header{
+			makeTendermintHeader(t, 3, genesisKeys, genesisVals),
+			makeTendermintHeader(t, 6, genesisKeys[:2], nextVals),
+		}
+		i, err := lc.InsertTendermintHeaders(checkpoints, nil)
+		require.Equal(t, tendermint.ErrInvalidCommittedSeals, err)
+		require.Equal(t, 1, i)
+		// the verified checkpoint is kept
+		require.Equal(t, checkpoints[0].Hash(), lc.CurrentHeader().Hash())
+	})
+
+	t.Run("signed by the previous validators", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		checkpoints := []*types.Header{
+			makeTendermintHeader(t, 3, genesisKeys, nextVals),
+		}
+		head := makeTendermintHeader(t, 4, genesisKeys, nil)
+		i, err := lc.InsertTendermintHeaders(checkpoints, head)
+		require.Equal(t, tendermint.ErrUnauthorized, err)
+		require.Equal(t, 1, i)
+	})
+
+	t.Run("validator set hash", func(t *testing.T) {
+		lc := newForkedTendermintLightChain(t, genesisVals, big.NewInt(6))
+		checkpoints := []*types.Header{
+			// the checkpoints record the hash of the validator set from the fork on
+			makeTendermintHeader(t, 3, genesisKeys, genesisVals),
+			makeHashedCheckpoint(t, 6, genesisKeys, nextVals),
+		}
+		i, err := lc.InsertTendermintHeaders(checkpoints, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, i)
+		require.Equal(t, checkpoints[1].Hash(), lc.CurrentHeader().Hash())
+
+		lc = newForkedTendermintLightChain(t, genesisVals, big.NewInt(6))
+		_, err = lc.InsertTendermintHeaders([]*types.Header{makeHashedCheckpoint(t, 3, genesisKeys, genesisVals)}, nil)
+		require.Equal(t, tendermint.ErrInvalidValSetHash, err)
+		_, err = lc.InsertTendermintHeaders([]*types.Header{makeTendermintHeader(t, 3, genesisKeys, genesisVals), makeTendermintHeader(t, 6, genesisKeys, nextVals)}, nil)
+		require.Equal(t, tendermint.ErrInvalidValSetHash, err)
+	})
+
+	t.Run("forged validator set", func(t *testing.T) {
+		lc := newForkedTendermintLightChain(t, genesisVals, common.Big0)
+		// the validator set handed off by a checkpoint is covered by its seals
+		checkpoint := makeHashedCheckpoint(t, 3, genesisKeys, genesisVals)
+		require.NoError(t, utils.WriteValSet(checkpoint, nextVals))
+		i, err := lc.InsertTendermintHeaders([]*types.Header{checkpoint}, nil)
+		require.Equal(t, tendermint.ErrInvalidValSetHash, err)
+		require.Equal(t, 0, i)
+
+		require.NoError(t, utils.WriteValSetHash(checkpoint))
+		i, err = lc.InsertTendermintHeaders([]*types.Header{checkpoint}, nil)
+		require.Error(t, err)
+		require.Equal(t, 0, i)
+		require.Equal(t, uint64(0), lc.CurrentHeader().Number.Uint64())
+	})
+
+	t.Run("non consecutive checkpoint", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		_, err := lc.InsertTendermintHeaders([]*types.Header{makeTendermintHeader(t, 6, genesisKeys, genesisVals)}, nil)
+		require.Equal(t, ErrNonConsecutiveCheckpoint, err)
+	})
+
+	t.Run("governed epoch length", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		checkpoints := []*types.Header{
+			// the epoch following the checkpoint 3 lasts 2 blocks, then the configured 3 blocks again
+			makeGovernedCheckpoint(t, 3, genesisKeys, genesisVals, 2),
+			makeTendermintHeader(t, 6, genesisKeys, genesisVals),
+		}
+		i, err := lc.InsertTendermintHeaders(checkpoints, nil)
+		require.Equal(t, ErrNonConsecutiveCheckpoint, err)
+		require.Equal(t, 1, i)
+
+		checkpoint := newTendermintHeader(t, 5, genesisKeys)
+		require.NoError(t, utils.WriteValSet(checkpoint, nextVals))
+		checkpoints = []*types.Header{
+			sealTendermintHeader(t, checkpoint, genesisKeys),
+			makeGovernedCheckpoint(t, 8, nextKeys, nextVals, 3),
+		}
+		_, err = lc.InsertTendermintHeaders(checkpoints, nil)
+		require.NoError(t, err)
+		require.Equal(t, checkpoints[1].Hash(), lc.TendermintCheckpoint().Hash())
+		require.Equal(t, uint64(3), lc.TendermintEpochLength(checkpoints[1]))
+	})
+
+	t.Run("head out of the epoch", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		_, err := lc.InsertTendermintHeaders(nil, makeTendermintHeader(t, 4, genesisKeys, nil))
+		require.Equal(t, ErrInvalidTendermintHead, err)
+	})
+
+	t.Run("conflicting final header", func(t *testing.T) {
+		lc := newTendermintLightChain(t, genesisVals)
+		_, err := lc.InsertTendermintHeaders(nil, makeTendermintHeader(t, 2, genesisKeys, nil))
+		require.NoError(t, err)
+		_, err = lc.InsertTendermintHeaders(nil, makeTendermintHeader(t, 2, genesisKeys[1:], nil))
+		require.Equal(t, ErrConflictingFinalHeader, err)
+	})
+}
@@ -85,6 +85,7 @@ type environment struct {
 	uncles    mapset.Set     // uncle set
 	tcount    int            // tx count in cycle
 	gasPool   *core.GasPool  // available gas used to pack transactions
+	baseFee   *big.Int       // base fee of the block, nil before the fee market
 
 	header   *types.Header
 	txs      []*types.Transaction
@@ -482,7 +483,7 @@ func (w *worker) mainLoop() {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs)
+				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs, w.current.baseFee)
 				w.commitTransactions(txset, coinbase, nil)
 				w.updateSnapshot()
 			} else {
@@ -643,6 +644,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		ancestors: mapset.NewSet(),
 		family:    mapset.NewSet(),
 		uncles:    mapset.NewSet(),
+		baseFee:   core.CalcBaseFee(w.chainConfig, consensus.MinGasPrice(w.engine, w.chain, parent.Header()), parent.Header()),
 		header:    header,
 	}
 
@@ -929,13 +931,13 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		}
 	}
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs, w.current.baseFee)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
 			return
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs, w.current.baseFee)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
 			return
 		}
@@ -212,6 +212,46 @@ func NewTransaction(nonce int64, to *Address, amount *BigInt, gasLimit int64, ga
 	return &Transaction{types.NewTransaction(uint64(nonce), to.address, amount.bigint, uint64(gasLimit), gasPrice.bigint, common.CopyBytes(data))}
 }
 
+// NewTransferOwnershipTransaction creates a new transaction proposing a new owner for
+// an enterprise contract. The new owner becomes the owner once it accepts the ownership.
+func NewTransferOwnershipTransaction(nonce int64, contract *Address, gasLimit int64, gasPrice *BigInt, newOwner *Address) (*Transaction, error) {
+	tx, err := types.NewTransferOwnershipTransaction(uint64(nonce), contract.address, uint64(gasLimit), gasPrice.bigint, newOwner.address)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx}, nil
+}
+
+// NewAcceptOwnershipTransaction creates a new transaction accepting the ownership of an
+// enterprise contract by its pending owner.
+func NewAcceptOwnershipTransaction(nonce int64, contract *Address, gasLimit int64, gasPrice *BigInt) (*Transaction, error) {
+	tx, err := types.NewAcceptOwnershipTransaction(uint64(nonce), contract.address, uint64(gasLimit), gasPrice.bigint)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx}, nil
+}
+
+// NewSetProviderBudgetTransaction creates a new transaction setting the gas budget per epoch
+// of a provider of an enterprise contract. A budget of 0 removes it.
+func NewSetProviderBudgetTransaction(nonce int64, contract *Address, gasLimit int64, gasPrice *BigInt, provider *Address, budget int64) (*Transaction, error) {
+	tx, err := types.NewSetGasLimitTransaction(uint64(nonce), contract.address, uint64(gasLimit), gasPrice.bigint, provider.address, uint64(budget), true)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx}, nil
+}
+
+// NewSetSenderAllowanceTransaction creates a new transaction setting the gas allowance per epoch
+// of a sender of an enterprise contract. An allowance of 0 removes it.
+func NewSetSenderAllowanceTransaction(nonce int64, contract *Address, gasLimit int64, gasPrice *BigInt, sender *Address, allowance int64) (*Transaction, error) {
+	tx, err := types.NewSetGasLimitTransaction(uint64(nonce), contract.address, uint64(gasLimit), gasPrice.bigint, sender.address, uint64(allowance), false)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx}, nil
+}
+
 // NewTransactionFromRLP parses a transaction from an RLP data dump.
 func NewTransactionFromRLP(data []byte) (*Transaction, error) {
 	tx := &Transaction{
@@ -28,6 +28,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/core/bloombits"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/core/vm"
 	"github.com/lvbin2012/NeuralChain/event"
@@ -50,6 +51,11 @@ func (b *NeutAPIBackend) ChainConfig() *params.ChainConfig {
 	return b.neut.blockchain.Config()
 }
 
+// MinGasPrice returns the minimum gas price of the block following the parent header.
+func (b *NeutAPIBackend) MinGasPrice(parent *types.Header) *big.Int {
+	return b.neut.blockchain.MinGasPrice(parent)
+}
+
 func (b *NeutAPIBackend) CurrentBlock() *types.Block {
 	return b.neut.blockchain.CurrentBlock()
 }
@@ -69,6 +75,9 @@ func (b *NeutAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNu
 	if blockNr == rpc.LatestBlockNumber {
 		return b.neut.blockchain.CurrentBlock().Header(), nil
 	}
+	if blockNr == rpc.FinalizedBlockNumber {
+		return b.neut.blockchain.CurrentFinalizedBlock().Header(), nil
+	}
 	return b.neut.blockchain.GetHeaderByNumber(uint64(blockNr)), nil
 }
 
@@ -86,6 +95,9 @@ func (b *NeutAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNum
 	if blockNr == rpc.LatestBlockNumber {
 		return b.neut.blockchain.CurrentBlock(), nil
 	}
+	if blockNr == rpc.FinalizedBlockNumber {
+		return b.neut.blockchain.CurrentFinalizedBlock(), nil
+	}
 	return b.neut.blockchain.GetBlockByNumber(uint64(blockNr)), nil
 }
 
@@ -139,6 +151,15 @@ func (b *NeutAPIBackend) GetEVM(ctx context.Context, msg core.Message, state *st
 	return vm.NewEVM(context, state, b.neut.blockchain.Config(), *b.neut.blockchain.GetVMConfig()), vmError, nil
 }
 
+// StakingCaller returns a caller reading the staking contract at the given state, the same way the Tendermint engine
+// reads it to elect the validators
+func (b *NeutAPIBackend) StakingCaller(state *state.StateDB, header *types.Header) staking.StakingCaller {
+	if b.neut.config.Tendermint.UseEVMCaller {
+		return staking.NewEVMStakingCaller(state, b.neut.BlockChain(), header, b.neut.blockchain.Config(), vm.Config{})
+	}
+	return staking.NewStateDbStakingCaller(state, b.neut.config.Tendermint.IndexStateVariables)
+}
+
 func (b *NeutAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
 	return b.neut.BlockChain().SubscribeRemovedLogsEvent(ch)
 }
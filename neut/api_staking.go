@@ -0,0 +1,179 @@
+// Copyright 2019 The NeuralChain Authors
+// This file is part of the NeuralChain library .
+//
+// The NeuralChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The NeuralChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the NeuralChain library . If not, see <http://www.gnu.org/licenses/>.
+
+package neut
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/core/state"
+	"github.com/lvbin2012/NeuralChain/core/state/staking"
+	"github.com/lvbin2012/NeuralChain/rpc"
+)
+
+var (
+	// errNoStakingContract is returned if the chain does not elect its validators with a staking contract
+	errNoStakingContract = errors.New("no staking contract")
+	// errNotCandidate is returned if the candidate data of an address which is not a candidate is requested
+	errNotCandidate = errors.New("not a candidate")
+)
+
+// RPCCandidateData is the data of a candidate of the staking contract
+type RPCCandidateData struct {
+	Candidate  common.Address   `json:"candidate"`
+	Owner      common.Address   `json:"owner"`
+	TotalStake *hexutil.Big     `json:"totalStake"`
+	Voters     []*RPCVoterStake `json:"voters"`
+}
+
+// RPCVoterStake is the stake a voter put on a candidate
+type RPCVoterStake struct {
+	Voter common.Address `json:"voter"`
+	Stake *hexutil.Big   `json:"stake"`
+}
+
+// RPCStakingParams are the parameters of the staking contract
+type RPCStakingParams struct {
+	Address           common.Address `json:"address"`
+	Admin             common.Address `json:"admin"`
+	StartBlock        *hexutil.Big   `json:"startBlock"`
+	EpochPeriod       *hexutil.Big   `json:"epochPeriod"`
+	MaxValidatorSize  *hexutil.Big   `json:"maxValidatorSize"`
+	MinValidatorStake *hexutil.Big   `json:"minValidatorStake"`
+	MinVoterCap       *hexutil.Big   `json:"minVoterCap"`
+}
+
+// PublicStakingAPI provides an API to read the state of the staking contract at any block.
+type PublicStakingAPI struct {
+	b *NeutAPIBackend
+}
+
+// NewPublicStakingAPI creates a new staking API for full nodes.
+func NewPublicStakingAPI(b *NeutAPIBackend) *PublicStakingAPI {
+	return &PublicStakingAPI{b}
+}
+
+// caller returns a staking caller reading the staking contract at the state of the given block.
+func (api *PublicStakingAPI) caller(ctx context.Context, blockNr rpc.BlockNumber) (staking.StakingCaller, *state.StateDB, common.Address, error) {
+	config := api.b.ChainConfig().Tendermint
+	if config == nil || config.StakingSCAddress == nil {
+		return nil, nil, common.Address{}, errNoStakingContract
+	}
+	stateDB, header, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, nil, common.Address{}, err
+	}
+	return api.b.StakingCaller(stateDB, header), stateDB, *config.StakingSCAddress, nil
+}
+
+// GetCandidates returns the candidates of the staking contract at the given block,
+// including the ones without enough stake to be elected as validators.
+func (api *PublicStakingAPI) GetCandidates(ctx context.Context, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	caller, _, scAddress, err := api.caller(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := caller.GetCandidates(scAddress)
+	if err == staking.ErrEmptyValidatorSet {
+		return []common.Address{}, nil
+	}
+	return candidates, err
+}
+
+// GetCandidateData returns the owner, the total stake and the stake of each voter of
+// a candidate at the given block. The voters are ordered by address.
+func (api *PublicStakingAPI) GetCandidateData(ctx context.Context, candidate common.Address, blockNr rpc.BlockNumber) (*RPCCandidateData, error) {
+	caller, _, scAddress, err := api.caller(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := caller.GetValidatorsData(scAddress, []common.Address{candidate})
+	if err != nil {
+		return nil, err
+	}
+	candidateData, ok := data[candidate]
+	if !ok || candidateData.Owner == (common.Address{}) {
+		return nil, errNotCandidate
+	}
+
+	result := &RPCCandidateData{
+		Candidate:  candidate,
+		Owner:      candidateData.Owner,
+		TotalStake: (*hexutil.Big)(candidateData.TotalStake),
+		Voters:     make([]*RPCVoterStake, 0, len(candidateData.VoterStakes)),
+	}
+	for voter, stake := range candidateData.VoterStakes {
+		result.Voters = append(result.Voters, &RPCVoterStake{
+			Voter: voter,
+			Stake: (*hexutil.Big)(stake),
+		})
+	}
+	sort.Slice(result.Voters, func(i, j int) bool {
+		return bytes.Compare(result.Voters[i].Voter.Bytes(), result.Voters[j].Voter.Bytes()) < 0
+	})
+	return result, nil
+}
+
+// GetParams returns the parameters of the staking contract at the given block.
+func (api *PublicStakingAPI) GetParams(ctx context.Context, blockNr rpc.BlockNumber) (*RPCStakingParams, error) {
+	caller, _, scAddress, err := api.caller(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	contractParams, err := caller.GetContractParams(scAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCStakingParams{
+		Address:           scAddress,
+		Admin:             contractParams.Admin,
+		StartBlock:        (*hexutil.Big)(contractParams.StartBlock),
+		EpochPeriod:       (*hexutil.Big)(contractParams.EpochPeriod),
+		MaxValidatorSize:  (*hexutil.Big)(contractParams.MaxValidatorSize),
+		MinValidatorStake: (*hexutil.Big)(contractParams.MinValidatorStake),
+		MinVoterCap:       (*hexutil.Big)(contractParams.MinVoterCap),
+	}, nil
+}
+
+// GetNextValidators returns the validators which would be active in the next epoch if the state of the given block
+// was the state of the last block of its epoch. The validators are elected by the staking contract the same way the
+// Tendermint engine elects them at the end of an epoch, without the jailed and tombstoned validators.
+func (api *PublicStakingAPI) GetNextValidators(ctx context.Context, blockNr rpc.BlockNumber) ([]common.Address, error) {
+	caller, stateDB, scAddress, err := api.caller(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := caller.GetValidators(scAddress)
+	if err == staking.ErrEmptyValidatorSet {
+		return []common.Address{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	active := make([]common.Address, 0, len(validators))
+	for _, validator := range validators {
+		if staking.IsTombstoned(stateDB, validator) || staking.IsJailed(stateDB, validator) {
+			continue
+		}
+		active = append(active, validator)
+	}
+	return active, nil
+}
@@ -110,6 +110,8 @@ func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.Block
 		from = api.neut.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		from = api.neut.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		from = api.neut.blockchain.CurrentFinalizedBlock()
 	default:
 		from = api.neut.blockchain.GetBlockByNumber(uint64(start))
 	}
@@ -118,6 +120,8 @@ func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.Block
 		to = api.neut.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		to = api.neut.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		to = api.neut.blockchain.CurrentFinalizedBlock()
 	default:
 		to = api.neut.blockchain.GetBlockByNumber(uint64(end))
 	}
@@ -360,6 +364,8 @@ func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.B
 		block = api.neut.miner.PendingBlock()
 	case rpc.LatestBlockNumber:
 		block = api.neut.blockchain.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		block = api.neut.blockchain.CurrentFinalizedBlock()
 	default:
 		block = api.neut.blockchain.GetBlockByNumber(uint64(number))
 	}
@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 
 	"github.com/lvbin2012/NeuralChain/accounts"
+	"github.com/lvbin2012/NeuralChain/accounts/external"
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
 	"github.com/lvbin2012/NeuralChain/consensus"
@@ -33,6 +34,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/consensus/ethash"
 	"github.com/lvbin2012/NeuralChain/consensus/tendermint"
 	tendermintBackend "github.com/lvbin2012/NeuralChain/consensus/tendermint/backend"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/bloombits"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
@@ -53,6 +55,9 @@ import (
 	"github.com/lvbin2012/NeuralChain/rpc"
 )
 
+// tendermintWALPath is the path within the instance directory of the Tendermint consensus write-ahead log
+const tendermintWALPath = "tendermint/wal"
+
 type LesServer interface {
 	Start(srvr *p2p.Server)
 	Stop()
@@ -136,7 +141,6 @@ func New(ctx *node.ServiceContext, config *Config) (*NeuralChain, error) {
 		engine:         CreateConsensusEngine(ctx, chainConfig, config, config.Miner.Notify, config.Miner.Noverify, chainDb),
 		shutdownChan:   make(chan bool),
 		networkID:      config.NetworkId,
-		gasPrice:       chainConfig.GasPrice,
 		etherbase:      config.Miner.Etherbase,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
@@ -182,6 +186,7 @@ func New(ctx *node.ServiceContext, config *Config) (*NeuralChain, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	neut.bloomIndexer.Start(neut.blockchain)
+	neut.gasPrice = neut.blockchain.MinGasPrice(neut.blockchain.CurrentHeader())
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
@@ -233,8 +238,24 @@ func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainCo
 		config.Tendermint.StakingSCAddress = chainConfig.Tendermint.StakingSCAddress
 		config.Tendermint.FixedValidators = chainConfig.Tendermint.FixedValidators
 		config.Tendermint.BlockReward = chainConfig.Tendermint.BlockReward
+		config.Tendermint.WeightedVoting = chainConfig.Tendermint.WeightedVoting
+		// without voting powers, the proportional policy would silently select the proposers in turn
+		if config.Tendermint.ProposerPolicy == tendermint.ProportionalToStake && (!config.Tendermint.WeightedVoting || len(config.Tendermint.FixedValidators) > 0) {
+			log.Crit("The proportional to stake proposer policy requires weighted voting and validators elected by stake")
+		}
 		log.Info("Create Tendermint consensus engine")
-		return tendermintBackend.New(&config.Tendermint, ctx.NodeKey())
+		opts := []tendermintBackend.Option{tendermintBackend.WithDB(db)}
+		if walPath := ctx.ResolvePath(tendermintWALPath); walPath != "" {
+			opts = append(opts, tendermintBackend.WithWAL(walPath))
+		}
+		if config.Tendermint.Validator != (common.Address{}) {
+			signer, err := newTendermintSigner(ctx.AccountManager, config.Tendermint.Validator)
+			if err != nil {
+				log.Crit("Failed to set up the tendermint validator", "address", config.Tendermint.Validator, "err", err)
+			}
+			opts = append(opts, tendermintBackend.WithSigner(signer))
+		}
+		return tendermintBackend.New(&config.Tendermint, ctx.NodeKey(), opts...)
 	}
 
 	// Otherwise assume proof-of-work
@@ -262,6 +283,20 @@ func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainCo
 	}
 }
 
+// newTendermintSigner returns the signer of the validator account, found either in the keystore or
+// in the external signer. External signers receive the position of the votes to refuse double signing.
+func newTendermintSigner(am *accounts.Manager, address common.Address) (privval.Signer, error) {
+	account := accounts.Account{Address: address}
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.URL().Scheme == external.Scheme {
+		return privval.NewExternalSigner(wallet, account), nil
+	}
+	return privval.NewWalletSigner(wallet, account), nil
+}
+
 // APIs return the collection of RPC services the neuralChain package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *NeuralChain) APIs() []rpc.API {
@@ -300,6 +335,12 @@ func (s *NeuralChain) APIs() []rpc.API {
 			Service:   NewPrivateMinerAPI(s),
 			Public:    false,
 		},
+		{
+			Namespace: "staking",
+			Version:   "1.0",
+			Service:   NewPublicStakingAPI(s.APIBackend),
+			Public:    true,
+		},
 		{
 			Namespace: "neut",
 			Version:   "1.0",
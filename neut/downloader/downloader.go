@@ -27,6 +27,7 @@ import (
 
 	neuralChain "github.com/lvbin2012/NeuralChain"
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/event"
@@ -178,6 +179,9 @@ type LightChain interface {
 
 	// Rollback removes a few recently added elements from the local chain.
 	Rollback([]common.Hash)
+
+	// Engine retrieves the consensus engine verifying the local chain.
+	Engine() consensus.Engine
 }
 
 // BlockChain encapsulates functions required to sync a (full or fast) blockchain.
@@ -454,13 +458,10 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	// Ensure our origin point is below any fast sync pivot point
 	pivot := uint64(0)
 	if d.mode == FastSync {
-		if height <= uint64(fsMinFullBlocks) {
+		if pivot = d.fastSyncPivot(latest); pivot == 0 {
 			origin = 0
-		} else {
-			pivot = height - uint64(fsMinFullBlocks)
-			if pivot <= origin {
-				origin = pivot - 1
-			}
+		} else if pivot <= origin {
+			origin = pivot - 1
 		}
 	}
 	d.committed = 1
@@ -1461,9 +1462,10 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 							unknown = append(unknown, header)
 						}
 					}
-					// If we're importing pure headers, verify based on their recentness
+					// If we're importing pure headers, verify based on their recentness. The final headers
+					// are all verified, each checkpoint hands off the validators sealing the next epoch.
 					frequency := fsHeaderCheckFrequency
-					if chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
+					if d.hasFinality() || chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
 						frequency = 1
 					}
 					if n, err := d.lightchain.InsertHeaderChain(chunk, frequency); err != nil {
@@ -1583,10 +1585,7 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 	}()
 	// Figure out the ideal pivot block. Note, that this goalpost may move if the
 	// sync takes long enough for the chain head to move significantly.
-	pivot := uint64(0)
-	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
-		pivot = height - uint64(fsMinFullBlocks)
-	}
+	pivot := d.fastSyncPivot(latest)
 	// To cater for moving pivot points, track the pivot block and subsequently
 	// accumulated download results separately.
 	var (
@@ -1620,8 +1619,8 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 		if atomic.LoadInt32(&d.committed) == 0 {
 			latest = results[len(results)-1].Header
 			if height := latest.Number.Uint64(); height > pivot+2*uint64(fsMinFullBlocks) {
-				log.Warn("Pivot became stale, moving", "old", pivot, "new", height-uint64(fsMinFullBlocks))
-				pivot = height - uint64(fsMinFullBlocks)
+				log.Warn("Pivot became stale, moving", "old", pivot, "new", d.fastSyncPivot(latest))
+				pivot = d.fastSyncPivot(latest)
 			}
 		}
 		P, beforeP, afterP := splitAroundPivot(pivot, results)
@@ -1665,6 +1664,26 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 	}
 }
 
+// hasFinality returns whether the consensus engine of the local chain decides itself when the blocks
+// are final, like Tendermint does by committing them with the seals of the validators.
+func (d *Downloader) hasFinality() bool {
+	_, ok := d.lightchain.Engine().(consensus.Finality)
+	return ok
+}
+
+// fastSyncPivot returns the block to sync the state of for the given head of the remote chain. If the consensus
+// engine decides when the blocks are final, the pivot is the latest final block as it can't be reorged, which is
+// the head itself under Tendermint. Otherwise it trails the head by fsMinFullBlocks, which are imported fully.
+func (d *Downloader) fastSyncPivot(latest *types.Header) uint64 {
+	if finality, ok := d.lightchain.Engine().(consensus.Finality); ok {
+		return finality.FinalizedNumber(latest)
+	}
+	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
+		return height - uint64(fsMinFullBlocks)
+	}
+	return 0
+}
+
 func splitAroundPivot(pivot uint64, results []*fetchResult) (p *fetchResult, before, after []*fetchResult) {
 	for _, result := range results {
 		num := result.Header.Number.Uint64()
@@ -28,6 +28,7 @@ import (
 
 	neuralChain "github.com/lvbin2012/NeuralChain"
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/event"
@@ -62,6 +63,10 @@ type downloadTester struct {
 	ancientReceipts map[common.Hash]types.Receipts // Ancient receipts belonging to the tester
 	ancientChainTd  map[common.Hash]*big.Int       // Ancient total difficulties of the blocks in the local chain
 
+	engine          consensus.Engine // Consensus engine of the local chain, nil unless deciding the finality
+	fastSyncHead    common.Hash      // Pivot block committed as the head by fast sync
+	headerCheckFreq int              // Highest frequency the seals of the inserted headers were checked with
+
 	lock sync.RWMutex
 }
 
@@ -222,6 +227,7 @@ func (dl *downloadTester) FastSyncCommitHead(hash common.Hash) error {
 	// For now only check that the state trie is correct
 	if block := dl.GetBlockByHash(hash); block != nil {
 		_, err := trie.NewSecure(block.Root(), trie.NewDatabase(dl.stateDb))
+		dl.fastSyncHead = hash
 		return err
 	}
 	return fmt.Errorf("non existent block: %x", hash[:4])
@@ -243,6 +249,9 @@ func (dl *downloadTester) InsertHeaderChain(headers []*types.Header, checkFreq i
 	dl.lock.Lock()
 	defer dl.lock.Unlock()
 
+	if checkFreq > dl.headerCheckFreq {
+		dl.headerCheckFreq = checkFreq
+	}
 	// Do a quick check, as the blockchain.InsertHeaderChain doesn't insert anything in case of errors
 	if _, ok := dl.ownHeaders[headers[0].ParentHash]; !ok {
 		return 0, errors.New("unknown parent")
@@ -322,6 +331,11 @@ func (dl *downloadTester) InsertReceiptChain(blocks types.Blocks, receipts []typ
 	return len(blocks), nil
 }
 
+// Engine retrieves the consensus engine of the simulated chain.
+func (dl *downloadTester) Engine() consensus.Engine {
+	return dl.engine
+}
+
 // Rollback removes some recently added elements from the chain.
 func (dl *downloadTester) Rollback(hashes []common.Hash) {
 	dl.lock.Lock()
@@ -505,6 +519,43 @@ func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, chain.len())
 }
 
+// finalityEngine is a consensus engine finalizing the blocks as they are committed, like Tendermint.
+type finalityEngine struct {
+	consensus.Engine
+}
+
+func (finalityEngine) FinalizedNumber(head *types.Header) uint64 {
+	return head.Number.Uint64()
+}
+
+// Tests that fast sync against a chain whose blocks are final as they are committed verifies every
+// header and syncs the state of the head, without importing any trailing block fully.
+func TestFinalityFastSynchronisation63(t *testing.T) { testFinalityFastSynchronisation(t, 63) }
+func TestFinalityFastSynchronisation64(t *testing.T) { testFinalityFastSynchronisation(t, 64) }
+
+func testFinalityFastSynchronisation(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	tester.engine = finalityEngine{}
+	defer tester.terminate()
+
+	// Create a chain long enough for the headers to be sampled and the pivot to trail the head without finality
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	tester.newPeer("peer", protocol, chain)
+
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+	if head := chain.headBlock().Hash(); tester.fastSyncHead != head {
+		t.Errorf("pivot mismatch: have %x, want head %x", tester.fastSyncHead, head)
+	}
+	if tester.headerCheckFreq != 1 {
+		t.Errorf("headers checked with frequency %d, want all of them", tester.headerCheckFreq)
+	}
+}
+
 // Tests that if a large batch of blocks are being downloaded, it is throttled
 // until the cached blocks are retrieved.
 func TestThrottling62(t *testing.T)     { testThrottling(t, 62, FullSync) }
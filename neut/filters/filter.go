@@ -142,6 +142,18 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	if f.begin == rpc.FinalizedBlockNumber.Int64() || f.end == rpc.FinalizedBlockNumber.Int64() {
+		finalized, _ := f.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+		if finalized == nil {
+			return nil, nil
+		}
+		if f.begin == rpc.FinalizedBlockNumber.Int64() {
+			f.begin = finalized.Number.Int64()
+		}
+		if f.end == rpc.FinalizedBlockNumber.Int64() {
+			end = finalized.Number.Uint64()
+		}
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
@@ -53,6 +53,8 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// FinalizedBlocksSubscription queries hashes for blocks that become final
+	FinalizedBlocksSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -72,6 +74,9 @@ const (
 
 var (
 	ErrInvalidSubscriptionID = errors.New("invalid id")
+	// errFinalizedLogsSubscription is returned if a log subscription is bounded by the finalized block,
+	// the logs are delivered as the blocks are imported, before they become final
+	errFinalizedLogsSubscription = errors.New("log subscriptions can not be bounded by the finalized block")
 )
 
 type subscription struct {
@@ -94,6 +99,8 @@ type EventSystem struct {
 	lightMode bool
 	lastHead  *types.Header
 
+	lastFinalized *types.Header // last finalized header sent to the finalized heads subscriptions
+
 	// Subscriptions
 	txsSub        event.Subscription         // Subscription for new transaction event
 	logsSub       event.Subscription         // Subscription for new log event
@@ -208,6 +215,9 @@ func (es *EventSystem) SubscribeLogs(crit neuralChain.FilterQuery, logs chan []*
 		to = rpc.BlockNumber(crit.ToBlock.Int64())
 	}
 
+	if from == rpc.FinalizedBlockNumber || to == rpc.FinalizedBlockNumber {
+		return nil, errFinalizedLogsSubscription
+	}
 	// only interested in pending logs
 	if from == rpc.PendingBlockNumber && to == rpc.PendingBlockNumber {
 		return es.subscribePendingLogs(crit, logs), nil
@@ -298,6 +308,22 @@ func (es *EventSystem) SubscribeNewHeads(headers chan *types.Header) *Subscripti
 	return es.subscribe(sub)
 }
 
+// SubscribeNewFinalizedHeads creates a subscription that writes the header of a block once it
+// can no longer be reverted according to the consensus engine, in the order of the chain.
+func (es *EventSystem) SubscribeNewFinalizedHeads(headers chan *types.Header) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       FinalizedBlocksSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   headers,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 // SubscribePendingTxs creates a subscription that writes transaction hashes for
 // transactions that enter the transaction pool.
 func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscription {
@@ -359,6 +385,13 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		for _, f := range filters[BlocksSubscription] {
 			f.headers <- e.Block.Header()
 		}
+		if len(filters[FinalizedBlocksSubscription]) > 0 {
+			for _, header := range es.newFinalizedHeads() {
+				for _, f := range filters[FinalizedBlocksSubscription] {
+					f.headers <- header
+				}
+			}
+		}
 		if es.lightMode && len(filters[LogsSubscription]) > 0 {
 			es.lightFilterNewHead(e.Block.Header(), func(header *types.Header, remove bool) {
 				for _, f := range filters[LogsSubscription] {
@@ -371,6 +404,39 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 	}
 }
 
+// newFinalizedHeads returns the headers finalized since the last call, oldest first.
+func (es *EventSystem) newFinalizedHeads() []*types.Header {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	finalized, _ := es.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if finalized == nil {
+		return nil
+	}
+	last := es.lastFinalized
+	es.lastFinalized = finalized
+	if last != nil && last.Hash() == finalized.Hash() {
+		return nil
+	}
+	// the first finalized head of the subscriptions or after the chain was rewound
+	if last == nil || finalized.Number.Cmp(last.Number) <= 0 {
+		return []*types.Header{finalized}
+	}
+	headers := []*types.Header{finalized}
+	for number := finalized.Number.Uint64() - 1; number > last.Number.Uint64(); number-- {
+		header, _ := es.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil {
+			break
+		}
+		headers = append(headers, header)
+	}
+	// reverse the headers into the order of the chain
+	for i, j := 0, len(headers)-1; i < j; i, j = i+1, j-1 {
+		headers[i], headers[j] = headers[j], headers[i]
+	}
+	return headers
+}
+
 func (es *EventSystem) lightFilterNewHead(newHeader *types.Header, callBack func(*types.Header, bool)) {
 	oldh := es.lastHead
 	es.lastHead = newHeader
@@ -495,6 +561,10 @@ func (es *EventSystem) eventLoop() {
 			} else {
 				delete(index[f.typ], f.id)
 			}
+			if len(index[FinalizedBlocksSubscription]) == 0 {
+				// don't catch up on the heads finalized without subscriptions
+				es.lastFinalized = nil
+			}
 			close(f.err)
 
 		// System stopped
@@ -27,6 +27,7 @@ import (
 
 	neuralChain "github.com/lvbin2012/NeuralChain"
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/consensus"
 	"github.com/lvbin2012/NeuralChain/consensus/ethash"
 	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/bloombits"
@@ -61,13 +62,17 @@ func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumbe
 		hash common.Hash
 		num  uint64
 	)
-	if blockNr == rpc.LatestBlockNumber {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.FinalizedBlockNumber {
 		hash = rawdb.ReadHeadBlockHash(b.db)
 		number := rawdb.ReadHeaderNumber(b.db, hash)
 		if number == nil {
 			return nil, nil
 		}
 		num = *number
+		if blockNr == rpc.FinalizedBlockNumber {
+			num = consensus.FinalizedNumber(ethash.NewFaker(), rawdb.ReadHeader(b.db, hash, num))
+			hash = rawdb.ReadCanonicalHash(b.db, num)
+		}
 	} else {
 		num = uint64(blockNr)
 		hash = rawdb.ReadCanonicalHash(b.db, num)
@@ -213,6 +218,62 @@ func TestBlockSubscription(t *testing.T) {
 }
 
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
+// TestFinalizedBlockSubscription tests that the finalized heads are sent once, in the order of the chain,
+// even if the event loop falls behind the imported blocks.
+func TestFinalizedBlockSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = rawdb.NewMemoryDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+		genesis    = new(core.Genesis).MustCommit(db)
+		chain, _   = core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, consensus.FinalityDepth+10, func(i int, gen *core.BlockGen) {})
+		finalized  = []*types.Block{genesis}
+	)
+	finalized = append(finalized, chain[:10]...)
+
+	chan0 := make(chan *types.Header)
+	sub0 := api.events.SubscribeNewFinalizedHeads(chan0)
+
+	done := make(chan struct{})
+	go func() { // simulate client
+		defer close(done)
+		for i := 0; i != len(finalized); i++ {
+			header := <-chan0
+			if finalized[i].Hash() != header.Hash() {
+				t.Errorf("sub0 received invalid hash on index %d, want %x, got %x", i, finalized[i].Hash(), header.Hash())
+			}
+		}
+	}()
+
+	for _, blk := range chain {
+		rawdb.WriteBlock(db, blk)
+		rawdb.WriteCanonicalHash(db, blk.Hash(), blk.NumberU64())
+		rawdb.WriteHeadBlockHash(db, blk.Hash())
+		chainFeed.Send(core.ChainEvent{Hash: blk.Hash(), Block: blk})
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the finalized heads")
+	}
+
+	// the heads are not finalized again
+	select {
+	case header := <-chan0:
+		t.Errorf("unexpected finalized head %d", header.Number)
+	case <-time.After(100 * time.Millisecond):
+	}
+	sub0.Unsubscribe()
+	<-sub0.Err()
+}
+
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
 
@@ -31,6 +31,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/event"
 	"github.com/lvbin2012/NeuralChain/params"
+	"github.com/lvbin2012/NeuralChain/rpc"
 )
 
 func makeReceipt(addr common.Address) *types.Receipt {
@@ -208,6 +209,23 @@ func TestFilters(t *testing.T) {
 		t.Errorf("expected log[0].Topics[0] to be %x, got %x", hash3, logs[0].Topics[0])
 	}
 
+	// the blocks with the last two logs are not buried deep enough to be final
+	finalized := rpc.FinalizedBlockNumber.Int64()
+	filter = NewRangeFilter(backend, 0, finalized, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, _ = filter.Logs(context.Background())
+	if len(logs) != 2 {
+		t.Error("expected 2 log, got", len(logs))
+	}
+
+	filter = NewRangeFilter(backend, finalized, -1, []common.Address{addr}, [][]common.Hash{{hash1, hash2, hash3, hash4}})
+	logs, _ = filter.Logs(context.Background())
+	if len(logs) != 2 {
+		t.Error("expected 2 log, got", len(logs))
+	}
+	if len(logs) > 0 && logs[0].Topics[0] != hash3 {
+		t.Errorf("expected log[0].Topics[0] to be %x, got %x", hash3, logs[0].Topics[0])
+	}
+
 	filter = NewRangeFilter(backend, 1, 10, nil, [][]common.Hash{{hash1, hash2}})
 
 	logs, _ = filter.Logs(context.Background())
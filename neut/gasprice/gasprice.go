@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/core"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/internal/neutapi"
 	"github.com/lvbin2012/NeuralChain/params"
@@ -42,6 +43,8 @@ type Oracle struct {
 	backend       neutapi.Backend
 	fixedGasPrice *big.Int
 	lastHead      common.Hash
+	lastTip       *big.Int
+	cacheLock     sync.RWMutex
 	fetchLock     sync.Mutex
 
 	checkBlocks, maxEmpty, maxBlocks int
@@ -64,6 +67,7 @@ func NewOracle(backend neutapi.Backend, params Config) *Oracle {
 	return &Oracle{
 		backend:       backend,
 		fixedGasPrice: new(big.Int).Set(backend.ChainConfig().GasPrice),
+		lastTip:       new(big.Int),
 		checkBlocks:   blocks,
 		maxEmpty:      blocks / 2,
 		maxBlocks:     blocks * 5,
@@ -71,14 +75,103 @@ func NewOracle(backend neutapi.Backend, params Config) *Oracle {
 	}
 }
 
-// SuggestPrice returns the recommended gas price.
+// SuggestPrice returns the recommended gas price. Before the fee market, it is the minimum gas price of the pending block.
+// Afterwards, it is the base fee of the pending block plus the tip at the configured percentile of the lowest tips
+// paid in the recent blocks.
 func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
-	return gpo.fixedGasPrice, nil
+	head, _ := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return gpo.fixedGasPrice, nil
+	}
+	minGasPrice := gpo.backend.MinGasPrice(head)
+	baseFee := core.CalcBaseFee(gpo.backend.ChainConfig(), minGasPrice, head)
+	if baseFee == nil {
+		return minGasPrice, nil
+	}
+	tip, err := gpo.suggestTip(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	price := new(big.Int).Add(baseFee, tip)
+	if price.Cmp(maxPrice) > 0 {
+		price.Set(maxPrice)
+	}
+	return price, nil
+}
+
+// suggestTip returns the tip at the configured percentile of the lowest tips paid in the blocks up to the head.
+func (gpo *Oracle) suggestTip(ctx context.Context, head *types.Header) (*big.Int, error) {
+	headHash := head.Hash()
+
+	// If the latest tip is still available, return it.
+	gpo.cacheLock.RLock()
+	lastHead, lastTip := gpo.lastHead, gpo.lastTip
+	gpo.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return lastTip, nil
+	}
+
+	gpo.fetchLock.Lock()
+	defer gpo.fetchLock.Unlock()
+
+	// Try checking the cache again, maybe the last fetch fetched what we need
+	gpo.cacheLock.RLock()
+	lastHead, lastTip = gpo.lastHead, gpo.lastTip
+	gpo.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return lastTip, nil
+	}
+
+	var (
+		blockNum  = head.Number.Uint64()
+		ch        = make(chan getBlockTipsResult, gpo.checkBlocks)
+		sent, exp int
+		blockTips []*big.Int
+	)
+	for sent < gpo.checkBlocks && blockNum > 0 {
+		go gpo.getBlockTips(ctx, blockNum, ch)
+		sent++
+		exp++
+		blockNum--
+	}
+	maxEmpty := gpo.maxEmpty
+	for exp > 0 {
+		res := <-ch
+		if res.err != nil {
+			return lastTip, res.err
+		}
+		exp--
+		if res.tip != nil {
+			blockTips = append(blockTips, res.tip)
+			continue
+		}
+		if maxEmpty > 0 {
+			maxEmpty--
+			continue
+		}
+		if blockNum > 0 && sent < gpo.maxBlocks {
+			go gpo.getBlockTips(ctx, blockNum, ch)
+			sent++
+			exp++
+			blockNum--
+		}
+	}
+	tip := lastTip
+	if len(blockTips) > 0 {
+		sort.Sort(bigIntArray(blockTips))
+		tip = blockTips[(len(blockTips)-1)*gpo.percentile/100]
+	}
+
+	gpo.cacheLock.Lock()
+	gpo.lastHead = headHash
+	gpo.lastTip = tip
+	gpo.cacheLock.Unlock()
+	return tip, nil
 }
 
-type getBlockPricesResult struct {
-	price *big.Int
-	err   error
+type getBlockTipsResult struct {
+	tip *big.Int
+	err error
 }
 
 type transactionsByGasPrice []*types.Transaction
@@ -87,12 +180,24 @@ func (t transactionsByGasPrice) Len() int           { return len(t) }
 func (t transactionsByGasPrice) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
 func (t transactionsByGasPrice) Less(i, j int) bool { return t[i].GasPrice().Cmp(t[j].GasPrice()) < 0 }
 
-// getBlockPrices calculates the lowest transaction gas price in a given block
-// and sends it to the result channel. If the block is empty, price is nil.
-func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, blockNum uint64, ch chan getBlockPricesResult) {
+// getBlockTips calculates the lowest tip above the base fee paid by a transaction in a given
+// block and sends it to the result channel. If the block is empty or precedes the fee market,
+// tip is nil.
+func (gpo *Oracle) getBlockTips(ctx context.Context, blockNum uint64, ch chan getBlockTipsResult) {
 	block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
 	if block == nil {
-		ch <- getBlockPricesResult{nil, err}
+		ch <- getBlockTipsResult{nil, err}
+		return
+	}
+	parent, err := gpo.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNum-1))
+	if parent == nil {
+		ch <- getBlockTipsResult{nil, err}
+		return
+	}
+	config := gpo.backend.ChainConfig()
+	baseFee := core.CalcBaseFee(config, gpo.backend.MinGasPrice(parent), parent)
+	if baseFee == nil {
+		ch <- getBlockTipsResult{nil, nil}
 		return
 	}
 
@@ -101,14 +206,15 @@ func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, bloc
 	copy(txs, blockTxs)
 	sort.Sort(transactionsByGasPrice(txs))
 
+	signer := types.MakeSigner(config, block.Number())
 	for _, tx := range txs {
 		sender, err := types.Sender(signer, tx)
 		if err == nil && sender != block.Coinbase() {
-			ch <- getBlockPricesResult{tx.GasPrice(), nil}
+			ch <- getBlockTipsResult{new(big.Int).Sub(tx.GasPrice(), baseFee), nil}
 			return
 		}
 	}
-	ch <- getBlockPricesResult{nil, nil}
+	ch <- getBlockTipsResult{nil, nil}
 }
 
 type bigIntArray []*big.Int
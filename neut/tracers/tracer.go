@@ -277,7 +277,8 @@ func (cw *contractWrapper) pushObject(vm *duktape.Context) {
 // Tracer provides an implementation of Tracer that evaluates a Javascript
 // function for each VM execution step.
 type Tracer struct {
-	inited bool // Flag whether the context was already inited from the EVM
+	inited bool    // Flag whether the context was already inited from the EVM
+	env    *vm.EVM // EVM the context was inited from, deciding the pre-compiled contracts
 
 	vm *duktape.Context // Javascript VM instance
 
@@ -397,7 +398,12 @@ func New(code string) (*Tracer, error) {
 		return 1
 	})
 	tracer.vm.PushGlobalGoFunction("isPrecompiled", func(ctx *duktape.Context) int {
-		_, ok := vm.PrecompiledContractsOmaha[common.BytesToAddress(popSlice(ctx))]
+		addr := common.BytesToAddress(popSlice(ctx))
+		if tracer.env != nil {
+			ctx.PushBoolean(tracer.env.IsPrecompiled(addr))
+			return 1
+		}
+		_, ok := vm.PrecompiledContractsOmaha[addr]
 		ctx.PushBoolean(ok)
 		return 1
 	})
@@ -544,6 +550,9 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 		// Initialize the context if it wasn't done yet
 		if !jst.inited {
 			jst.ctx["block"] = env.BlockNumber.Uint64()
+			jst.ctx["gasPayer"] = env.GasPayer
+			jst.ctx["sponsored"] = env.Sponsored
+			jst.env = env
 			jst.inited = true
 		}
 		// If tracing was interrupted, set the error and stop
@@ -617,6 +626,9 @@ func (jst *Tracer) GetResult() (json.RawMessage, error) {
 		case string:
 			jst.vm.PushString(val)
 
+		case bool:
+			jst.vm.PushBoolean(val)
+
 		case []byte:
 			ptr := jst.vm.PushFixedBuffer(len(val))
 			copy(makeSlice(ptr, uint(len(val))), val)
@@ -108,6 +108,30 @@ func TestOpcodes(t *testing.T) {
 	}
 }
 
+func TestGasPayer(t *testing.T) {
+	tracer, err := New("{step: function() {}, fault: function() {}, result: function(ctx) { return [toHex(ctx.gasPayer), ctx.sponsored, isPrecompiled(toAddress('0x000000000000000000000000000000000000000a'))]; }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := *params.TestChainConfig
+	config.GasPayerBlock = big.NewInt(0)
+	ctx := vm.Context{BlockNumber: big.NewInt(1), GasPayer: common.HexToAddress("0x00000000000000000000000000000000000000aa"), Sponsored: true}
+	env := vm.NewEVM(ctx, &dummyStatedb{}, &config, vm.Config{Debug: true, Tracer: tracer})
+
+	contract := vm.NewContract(account{}, account{}, big.NewInt(0), 10000)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, 0x0}
+	if _, err := env.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["0x00000000000000000000000000000000000000aa",true,true]`; string(ret) != want {
+		t.Errorf("Expected return value to be %s, got %s", want, string(ret))
+	}
+}
+
 func TestHalt(t *testing.T) {
 	t.Skip("duktape doesn't support abortion")
 
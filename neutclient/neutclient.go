@@ -32,6 +32,10 @@ import (
 	"github.com/lvbin2012/NeuralChain/rpc"
 )
 
+// FinalizedBlockNumber can be passed as the block number to query the latest block which
+// can no longer be reverted according to the consensus engine of the node.
+var FinalizedBlockNumber = big.NewInt(rpc.FinalizedBlockNumber.Int64())
+
 // Client defines typed wrappers for the NeuralChain RPC API.
 type Client struct {
 	c *rpc.Client
@@ -324,6 +328,9 @@ func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
 	}
+	if number.Cmp(FinalizedBlockNumber) == 0 {
+		return "finalized"
+	}
 	return hexutil.EncodeBig(number)
 }
 
@@ -366,6 +373,12 @@ func (ec *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header)
 	return ec.c.EthSubscribe(ctx, ch, "newHeads")
 }
 
+// SubscribeNewFinalizedHead subscribes to notifications about the blocks which can no longer be reverted
+// according to the consensus engine of the node.
+func (ec *Client) SubscribeNewFinalizedHead(ctx context.Context, ch chan<- *types.Header) (neuralChain.Subscription, error) {
+	return ec.c.EthSubscribe(ctx, ch, "newFinalizedHeads")
+}
+
 // State Access
 
 // NetworkID returns the network ID (also known as the chain ID) for this chain.
@@ -413,7 +426,29 @@ func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumb
 	return uint64(result), err
 }
 
-//TODO: add an api for enterprise contract (NeuralChain)
+// OwnerAt returns the owner of the given enterprise contract, or nil if the account is not an enterprise contract.
+// The block number can be nil, in which case the owner is taken from the latest known block.
+func (ec *Client) OwnerAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (*common.Address, error) {
+	var result *common.Address
+	err := ec.c.CallContext(ctx, &result, "neut_getOwner", contract, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// ProvidersAt returns the providers of the given enterprise contract.
+// The block number can be nil, in which case the providers are taken from the latest known block.
+func (ec *Client) ProvidersAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]common.Address, error) {
+	var result []common.Address
+	err := ec.c.CallContext(ctx, &result, "neut_getProviders", contract, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// IsEnterpriseAt returns whether the given account is an enterprise contract, whose transactions may be paid by its providers.
+// The block number can be nil, in which case the account is taken from the latest known block.
+func (ec *Client) IsEnterpriseAt(ctx context.Context, account common.Address, blockNumber *big.Int) (bool, error) {
+	var result bool
+	err := ec.c.CallContext(ctx, &result, "neut_isEnterprise", account, toBlockNumArg(blockNumber))
+	return result, err
+}
 
 // Filters
 
@@ -612,6 +647,13 @@ func toCallArg(msg neuralChain.CallMsg) interface{} {
 	return arg
 }
 
+func toGasLimitArg(limit *types.GasLimitMsg) interface{} {
+	return map[string]interface{}{
+		"address": limit.Address,
+		"limit":   hexutil.Uint64(limit.Limit),
+	}
+}
+
 func toSendTxArgs(args neuralChain.SendTxArgs) interface{} {
 	arg := map[string]interface{}{
 		"from": args.From,
@@ -640,6 +682,18 @@ func toSendTxArgs(args neuralChain.SendTxArgs) interface{} {
 	if args.Provider != nil {
 		arg["provider"] = args.Provider
 	}
+	if args.NewOwner != nil {
+		arg["newOwner"] = args.NewOwner
+	}
+	if args.AcceptOwnership {
+		arg["acceptOwnership"] = true
+	}
+	if args.ProviderBudget != nil {
+		arg["providerBudget"] = toGasLimitArg(args.ProviderBudget)
+	}
+	if args.SenderAllowance != nil {
+		arg["senderAllowance"] = toGasLimitArg(args.SenderAllowance)
+	}
 
 	return arg
 }
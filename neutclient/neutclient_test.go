@@ -18,6 +18,8 @@ package neutclient
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -35,6 +37,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/core/rawdb"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
+	"github.com/lvbin2012/NeuralChain/internal/neutapi"
 	"github.com/lvbin2012/NeuralChain/neut"
 	"github.com/lvbin2012/NeuralChain/node"
 	"github.com/lvbin2012/NeuralChain/params"
@@ -204,9 +207,11 @@ func newTestBackend(t *testing.T, txs types.Transactions) (*node.Node, []*types.
 
 func generateTestChain(txs types.Transactions) (*core.Genesis, []*types.Block) {
 	db := rawdb.NewMemoryDatabase()
-	config := params.AllEthashProtocolChanges
+	config := *params.AllEthashProtocolChanges
+	config.OwnershipTransferBlock = big.NewInt(0)
+	config.GasBudgetBlock = big.NewInt(0)
 	genesis := &core.Genesis{
-		Config:    config,
+		Config:    &config,
 		Alloc:     core.GenesisAlloc{testAddr: {Balance: testBalance}, testAddr2: {Balance: testBalance2}},
 		ExtraData: []byte("test genesis"),
 		Timestamp: 9000,
@@ -220,7 +225,7 @@ func generateTestChain(txs types.Transactions) (*core.Genesis, []*types.Block) {
 	}
 	gblock := genesis.ToBlock(db)
 	engine := ethash.NewFaker()
-	blocks, _ := core.GenerateChain(config, gblock, engine, db, 1, generate)
+	blocks, _ := core.GenerateChain(&config, gblock, engine, db, 1, generate)
 	blocks = append([]*types.Block{gblock}, blocks...)
 	return genesis, blocks
 }
@@ -248,6 +253,11 @@ func TestHeader(t *testing.T) {
 			block: big.NewInt(1000000000),
 			want:  nil,
 		},
+		"finalized_block": {
+			// the first block is not buried deep enough to be final under ethash
+			block: FinalizedBlockNumber,
+			want:  chain[0].Header(),
+		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -315,6 +325,124 @@ func TestBalanceAt(t *testing.T) {
 	}
 }
 
+func TestEnterpriseContract(t *testing.T) {
+	var (
+		signer   = types.NewOmahaSigner(params.AllEthashProtocolChanges.ChainID)
+		contract = crypto.CreateAddress(testAddr, 0)
+		opts     = types.CreateAccountOption{OwnerAddress: &testAddr, ProviderAddress: &testAddr2}
+	)
+	tx := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(params.GasPriceConfig), nil, opts)
+	tx, err := types.SignTx(tx, signer, testKey)
+	require.NoError(t, err)
+	backend, _ := newTestBackend(t, types.Transactions{tx})
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+
+	ec := NewClient(client)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	owner, err := ec.OwnerAt(ctx, contract, nil)
+	require.NoError(t, err)
+	require.Equal(t, &testAddr, owner)
+	providers, err := ec.ProvidersAt(ctx, contract, nil)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{testAddr2}, providers)
+	isEnterprise, err := ec.IsEnterpriseAt(ctx, contract, nil)
+	require.NoError(t, err)
+	require.True(t, isEnterprise)
+
+	// the contract does not exist in the genesis block
+	owner, err = ec.OwnerAt(ctx, contract, big.NewInt(0))
+	require.NoError(t, err)
+	require.Nil(t, owner)
+	providers, err = ec.ProvidersAt(ctx, contract, big.NewInt(0))
+	require.NoError(t, err)
+	require.Empty(t, providers)
+	isEnterprise, err = ec.IsEnterpriseAt(ctx, testAddr, nil)
+	require.NoError(t, err)
+	require.False(t, isEnterprise)
+}
+
+func TestStakingWithoutContract(t *testing.T) {
+	backend, _ := newTestBackend(t, nil)
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+
+	ec := NewClient(client)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// the validators of the test chain are not elected by a staking contract
+	_, err := ec.StakingCandidatesAt(ctx, nil)
+	require.EqualError(t, err, "no staking contract")
+	_, err = ec.StakingCandidateDataAt(ctx, testAddr, big.NewInt(0))
+	require.EqualError(t, err, "no staking contract")
+	_, err = ec.StakingParamsAt(ctx, nil)
+	require.EqualError(t, err, "no staking contract")
+	_, err = ec.StakingNextValidatorsAt(ctx, nil)
+	require.EqualError(t, err, "no staking contract")
+}
+
+func TestOwnershipTransfer(t *testing.T) {
+	var (
+		signer   = types.NewOmahaSigner(params.AllEthashProtocolChanges.ChainID)
+		gasPrice = big.NewInt(params.GasPriceConfig)
+		contract = crypto.CreateAddress(testAddr, 0)
+	)
+	sign := func(tx *types.Transaction, key *ecdsa.PrivateKey) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		require.NoError(t, err)
+		return signed
+	}
+	transferTx, err := types.NewTransferOwnershipTransaction(1, contract, params.TxGas, gasPrice, testAddr2)
+	require.NoError(t, err)
+	acceptTx, err := types.NewAcceptOwnershipTransaction(0, contract, params.TxGas, gasPrice)
+	require.NoError(t, err)
+	txs := types.Transactions{
+		sign(types.NewContractCreation(0, big.NewInt(0), 100000, gasPrice, nil, types.CreateAccountOption{OwnerAddress: &testAddr}), testKey),
+		sign(transferTx, testKey),
+		sign(acceptTx, testKey2),
+	}
+	backend, _ := newTestBackend(t, txs)
+	client, _ := backend.Attach()
+	defer backend.Stop()
+	defer client.Close()
+
+	ec := NewClient(client)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	owner, err := ec.OwnerAt(ctx, contract, nil)
+	require.NoError(t, err)
+	require.Equal(t, &testAddr2, owner)
+	for _, tx := range txs {
+		receipt, err := ec.TransactionReceipt(ctx, tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	}
+}
+
+func TestToSendTxArgsGasLimits(t *testing.T) {
+	contract := common.Address{0xe}
+	args := neuralChain.SendTxArgs{
+		From:            testAddr,
+		To:              &contract,
+		ProviderBudget:  &types.GasLimitMsg{Address: testAddr2, Limit: 50000},
+		SenderAllowance: &types.GasLimitMsg{Address: testAddr, Limit: 30000},
+	}
+	data, err := json.Marshal(toSendTxArgs(args))
+	require.NoError(t, err)
+
+	// the node decodes the gas limits set by the client
+	var decoded neutapi.SendTxArgs
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, &neutapi.GasLimitArgs{Address: testAddr2, Limit: 50000}, decoded.ProviderBudget)
+	require.Equal(t, &neutapi.GasLimitArgs{Address: testAddr, Limit: 30000}, decoded.SenderAllowance)
+}
+
 func TestTransactionInBlockInterrupted(t *testing.T) {
 	backend, _ := newTestBackend(t, nil)
 	client, _ := backend.Attach()
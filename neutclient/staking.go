@@ -0,0 +1,63 @@
+package neutclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+)
+
+// StakingCandidateData is the data of a candidate of the staking contract
+type StakingCandidateData struct {
+	Candidate  common.Address `json:"candidate"`
+	Owner      common.Address `json:"owner"`
+	TotalStake *hexutil.Big   `json:"totalStake"`
+	Voters     []*struct {
+		Voter common.Address `json:"voter"`
+		Stake *hexutil.Big   `json:"stake"`
+	} `json:"voters"`
+}
+
+// StakingParams are the parameters of the staking contract
+type StakingParams struct {
+	Address           common.Address `json:"address"`
+	Admin             common.Address `json:"admin"`
+	StartBlock        *hexutil.Big   `json:"startBlock"`
+	EpochPeriod       *hexutil.Big   `json:"epochPeriod"`
+	MaxValidatorSize  *hexutil.Big   `json:"maxValidatorSize"`
+	MinValidatorStake *hexutil.Big   `json:"minValidatorStake"`
+	MinVoterCap       *hexutil.Big   `json:"minVoterCap"`
+}
+
+// StakingCandidatesAt returns the candidates of the staking contract, including the ones not elected as validators.
+// The block number can be nil, in which case the candidates are taken from the latest known block.
+func (ec *Client) StakingCandidatesAt(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	var candidates []common.Address
+	err := ec.c.CallContext(ctx, &candidates, "staking_getCandidates", toBlockNumArg(blockNumber))
+	return candidates, err
+}
+
+// StakingCandidateDataAt returns the owner, the total stake and the voters' stakes of a candidate.
+// The block number can be nil, in which case the data is taken from the latest known block.
+func (ec *Client) StakingCandidateDataAt(ctx context.Context, candidate common.Address, blockNumber *big.Int) (*StakingCandidateData, error) {
+	var data *StakingCandidateData
+	err := ec.c.CallContext(ctx, &data, "staking_getCandidateData", candidate, toBlockNumArg(blockNumber))
+	return data, err
+}
+
+// StakingParamsAt returns the parameters of the staking contract.
+// The block number can be nil, in which case the parameters are taken from the latest known block.
+func (ec *Client) StakingParamsAt(ctx context.Context, blockNumber *big.Int) (*StakingParams, error) {
+	var params *StakingParams
+	err := ec.c.CallContext(ctx, &params, "staking_getParams", toBlockNumArg(blockNumber))
+	return params, err
+}
+
+// StakingNextValidatorsAt returns the validators the staking contract would elect for the next epoch at the state of
+// the given block. The block number can be nil, in which case the latest known block is used.
+func (ec *Client) StakingNextValidatorsAt(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	var validators []common.Address
+	err := ec.c.CallContext(ctx, &validators, "staking_getNextValidators", toBlockNumArg(blockNumber))
+	return validators, err
+}
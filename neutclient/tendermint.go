@@ -0,0 +1,121 @@
+package neutclient
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/lvbin2012/NeuralChain/common"
+	"github.com/lvbin2012/NeuralChain/common/hexutil"
+)
+
+// TendermintConsensusState is a snapshot of the Tendermint consensus state of a validator
+type TendermintConsensusState struct {
+	BlockNumber *hexutil.Big            `json:"blockNumber"`
+	Round       int64                   `json:"round"`
+	Step        string                  `json:"step"`
+	StartTime   time.Time               `json:"startTime"`
+	Proposer    common.Address          `json:"proposer"`
+	Validators  []common.Address        `json:"validators"`
+	LockedRound int64                   `json:"lockedRound"`
+	LockedBlock *common.Hash            `json:"lockedBlock"`
+	ValidRound  int64                   `json:"validRound"`
+	ValidBlock  *common.Hash            `json:"validBlock"`
+	Votes       []*TendermintRoundVotes `json:"votes"`
+	CatchUp     *struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+		Round       int64        `json:"round"`
+		Step        string       `json:"step"`
+		Retry       uint64       `json:"retry"`
+		Time        time.Time    `json:"time"`
+	} `json:"catchUp"`
+	Rebroadcast *struct {
+		Enabled   bool             `json:"enabled"`
+		Neighbors []common.Address `json:"neighbors"`
+		SentMsgs  int              `json:"sentMsgs"`
+	} `json:"rebroadcast"`
+}
+
+// TendermintRoundVotes is the votes received in a round.
+// The bit-arrays are ordered as the validators, 'x' marks a received vote and '_' a missing one.
+type TendermintRoundVotes struct {
+	Round           int64        `json:"round"`
+	Prevotes        string       `json:"prevotes"`
+	PrevotesMaj23   *common.Hash `json:"prevotesMaj23"`
+	Precommits      string       `json:"precommits"`
+	PrecommitsMaj23 *common.Hash `json:"precommitsMaj23"`
+}
+
+// TendermintEpochReward is the distribution of the rewards credited at the last block of an epoch
+type TendermintEpochReward struct {
+	Epoch       uint64         `json:"epoch"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Validators  []*struct {
+		Validator   common.Address `json:"validator"`
+		BlockReward *hexutil.Big   `json:"blockReward"`
+		TxFee       *hexutil.Big   `json:"txFee"`
+		Owner       common.Address `json:"owner"`
+		OwnerReward *hexutil.Big   `json:"ownerReward"`
+		Voters      []*struct {
+			Voter  common.Address `json:"voter"`
+			Reward *hexutil.Big   `json:"reward"`
+		} `json:"voters"`
+		Jailed bool `json:"jailed"`
+	} `json:"validators"`
+}
+
+// TendermintAddressReward is the reward an address received in an epoch as owner or voter of validators
+type TendermintAddressReward struct {
+	Epoch       uint64         `json:"epoch"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Total       *hexutil.Big   `json:"total"`
+	Shares      []*struct {
+		Validator common.Address `json:"validator"`
+		Role      string         `json:"role"`
+		Amount    *hexutil.Big   `json:"amount"`
+	} `json:"shares"`
+}
+
+// TendermintValidators returns the validators of the given block, the current block is used if number is nil
+func (ec *Client) TendermintValidators(ctx context.Context, number *big.Int) ([]common.Address, error) {
+	var validators []common.Address
+	err := ec.c.CallContext(ctx, &validators, "tendermint_getValidators", toUint64Arg(number))
+	return validators, err
+}
+
+// TendermintConsensusState returns the consensus state of the node, which must be running as a validator
+func (ec *Client) TendermintConsensusState(ctx context.Context) (*TendermintConsensusState, error) {
+	var state *TendermintConsensusState
+	err := ec.c.CallContext(ctx, &state, "tendermint_getConsensusState")
+	return state, err
+}
+
+// TendermintProposer returns the proposer of the given round of the block, the current block is used if number is nil
+func (ec *Client) TendermintProposer(ctx context.Context, number *big.Int, round int64) (common.Address, error) {
+	var proposer common.Address
+	err := ec.c.CallContext(ctx, &proposer, "tendermint_getProposer", toUint64Arg(number), round)
+	return proposer, err
+}
+
+// TendermintEpochReward returns the distribution of the rewards of the given epoch
+func (ec *Client) TendermintEpochReward(ctx context.Context, epoch uint64) (*TendermintEpochReward, error) {
+	var reward *TendermintEpochReward
+	err := ec.c.CallContext(ctx, &reward, "tendermint_getEpochReward", epoch)
+	return reward, err
+}
+
+// TendermintRewardsByAddress returns the rewards the address received in the epochs from fromEpoch to toEpoch,
+// the latest epochs are looked up if they are nil
+func (ec *Client) TendermintRewardsByAddress(ctx context.Context, address common.Address, fromEpoch, toEpoch *big.Int) ([]*TendermintAddressReward, error) {
+	var rewards []*TendermintAddressReward
+	err := ec.c.CallContext(ctx, &rewards, "tendermint_getRewardsByAddress", address, toUint64Arg(fromEpoch), toUint64Arg(toEpoch))
+	return rewards, err
+}
+
+func toUint64Arg(number *big.Int) *uint64 {
+	if number == nil {
+		return nil
+	}
+	n := number.Uint64()
+	return &n
+}
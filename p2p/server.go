@@ -516,11 +516,10 @@ func (srv *Server) GetValSetAddresses(header *types.Header) ([]common.Address, e
 
 	var (
 		validators       []common.Address
-		epoch            = srv.ChainReader.Config().Tendermint.Epoch
-		transitionHeader = srv.ChainReader.GetHeaderByNumber((header.Number.Uint64() / epoch) * epoch)
+		transitionHeader = srv.ChainReader.LatestCheckpoint(header)
 	)
 	if transitionHeader == nil {
-		return nil, errors.Errorf("Can not get transition header at number %v with epoch %v", header.Number.Uint64(), epoch)
+		return nil, errors.Errorf("Can not get transition header of number %v", header.Number.Uint64())
 	}
 
 	tdmExtra, err := types.ExtractTendermintExtra(transitionHeader)
@@ -118,17 +118,17 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, new(EthashConfig), nil, nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil, nil, new(EthashConfig), nil, nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the NeuralChain core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil}
 
-	TestChainConfig           = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, new(EthashConfig), nil, nil}
-	TendermintTestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, new(TendermintConfig)}
+	TestChainConfig           = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil, nil, new(EthashConfig), nil, nil}
+	TendermintTestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(GasPriceConfig), nil, nil, nil, nil, nil, nil, nil, nil, 0, nil, nil, nil, nil, nil, new(TendermintConfig)}
 	TestRules                 = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -156,6 +156,19 @@ type ChainConfig struct {
 
 	ViervilleBlock *big.Int `json:"viervilleBlock,omitempty"` // ViervilleBlock switch block(nil = no fork, 0 = already activated)
 	EWASMBlock     *big.Int `json:"ewasmBlock,omitempty"`     // EWASM switch block (nil = no fork, 0 = already activated)
+	FeeMarketBlock *big.Int `json:"feeMarketBlock,omitempty"` // FeeMarket switch block, gasPrice becomes the minimal base fee (nil = no fork, 0 = already activated)
+
+	EnterpriseLogsBlock *big.Int `json:"enterpriseLogsBlock,omitempty"` // EnterpriseLogs switch block, provider and owner changes emit logs (nil = no fork, 0 = already activated)
+	ValidatorSetBlock   *big.Int `json:"validatorSetBlock,omitempty"`   // ValidatorSet switch block, the validator set contract is pre-compiled (nil = no fork, 0 = already activated)
+	GasPayerBlock       *big.Int `json:"gasPayerBlock,omitempty"`       // GasPayer switch block, the gas payer contract is pre-compiled (nil = no fork, 0 = already activated)
+
+	OwnershipTransferBlock *big.Int `json:"ownershipTransferBlock,omitempty"` // OwnershipTransfer switch block, the owners of enterprise contracts can transfer them (nil = no fork, 0 = already activated)
+	GasBudgetBlock         *big.Int `json:"gasBudgetBlock,omitempty"`         // GasBudget switch block, the owners of enterprise contracts can limit the gas paid by the providers (nil = no fork, 0 = already activated)
+	GasBudgetEpoch         uint64   `json:"gasBudgetEpoch,omitempty"`         // Number of blocks of the epochs of the gas budgets (0 = the Tendermint epoch, or GasBudgetEpochLength without one)
+
+	ValidatorSetHashBlock *big.Int `json:"validatorSetHashBlock,omitempty"` // ValidatorSetHash switch block, the Tendermint block hash covers the handed off validator set (nil = no fork, 0 = already activated)
+	EvidenceBlock         *big.Int `json:"evidenceBlock,omitempty"`         // Evidence switch block, the Tendermint blocks include the evidences of double signing and slash the offenders (nil = no fork, 0 = already activated)
+	LivenessBlock         *big.Int `json:"livenessBlock,omitempty"`         // Liveness switch block, the Tendermint blocks track the liveness of the validators and jail the ones missing too many blocks (nil = no fork, 0 = already activated)
 
 	// Various consensus engines
 	Ethash     *EthashConfig     `json:"ethash,omitempty"`
@@ -189,6 +202,13 @@ type TendermintConfig struct {
 	BlockReward      *big.Int         `json:"blockReward"`      // TendermintBlockReward for accumulating reward
 	StakingSCAddress *common.Address  `json:"stakingSCAddress"` // The staking SC address for validating when deploy SC
 	FixedValidators  []common.Address `json:"fixedValidators"`
+
+	DoubleSignSlashPercentage uint64 `json:"doubleSignSlashPercentage,omitempty"` // The percentage of stake slashed from a validator signing conflicting votes
+	WeightedVoting            bool   `json:"weightedVoting,omitempty"`            // Weight the validators' votes by their stake
+	DowntimeWindow            uint64 `json:"downtimeWindow,omitempty"`            // The number of latest blocks in which the validators' liveness is tracked, 0 disables the tracking
+	DowntimeJailPercentage    uint64 `json:"downtimeJailPercentage,omitempty"`    // The percentage of blocks in the downtime window a validator can miss before being jailed, 0 disables the jailing
+
+	GovernanceSCAddress *common.Address `json:"governanceSCAddress,omitempty"` // The governance SC address where validators vote on the chain parameters, nil disables the governance
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -209,10 +229,19 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v GasPrice: %v Vierville: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v GasPrice: %v Vierville: %v FeeMarket: %v EnterpriseLogs: %v ValidatorSet: %v GasPayer: %v OwnershipTransfer: %v GasBudget: %v ValidatorSetHash: %v Evidence: %v Liveness: %v Engine: %v}",
 		c.ChainID,
 		c.GasPrice,
 		c.ViervilleBlock,
+		c.FeeMarketBlock,
+		c.EnterpriseLogsBlock,
+		c.ValidatorSetBlock,
+		c.GasPayerBlock,
+		c.OwnershipTransferBlock,
+		c.GasBudgetBlock,
+		c.ValidatorSetHashBlock,
+		c.EvidenceBlock,
+		c.LivenessBlock,
 		engine,
 	)
 }
@@ -227,6 +256,75 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsFeeMarket returns whether num is either equal to the FeeMarket fork block or greater.
+// From the fork on, the gas price of a transaction covers the base fee of its block and a tip.
+func (c *ChainConfig) IsFeeMarket(num *big.Int) bool {
+	return isForked(c.FeeMarketBlock, num)
+}
+
+// IsEnterpriseLogs returns whether num is either equal to the EnterpriseLogs fork block or greater.
+// From the fork on, the changes of the providers and owner of enterprise contracts emit logs.
+func (c *ChainConfig) IsEnterpriseLogs(num *big.Int) bool {
+	return isForked(c.EnterpriseLogsBlock, num)
+}
+
+// IsValidatorSet returns whether num is either equal to the ValidatorSet fork block or greater.
+// From the fork on, the contracts can read the validators of the block through a pre-compiled contract.
+func (c *ChainConfig) IsValidatorSet(num *big.Int) bool {
+	return isForked(c.ValidatorSetBlock, num)
+}
+
+// IsGasPayer returns whether num is either equal to the GasPayer fork block or greater.
+// From the fork on, the contracts can read the gas payer of the transaction through a pre-compiled contract.
+func (c *ChainConfig) IsGasPayer(num *big.Int) bool {
+	return isForked(c.GasPayerBlock, num)
+}
+
+// IsOwnershipTransfer returns whether num is either equal to the OwnershipTransfer fork block or greater.
+// From the fork on, the owner of an enterprise contract can propose a new owner, which accepts the ownership.
+func (c *ChainConfig) IsOwnershipTransfer(num *big.Int) bool {
+	return isForked(c.OwnershipTransferBlock, num)
+}
+
+// IsGasBudget returns whether num is either equal to the GasBudget fork block or greater.
+// From the fork on, the owner of an enterprise contract can limit the gas paid per epoch by each provider and for each sender.
+func (c *ChainConfig) IsGasBudget(num *big.Int) bool {
+	return isForked(c.GasBudgetBlock, num)
+}
+
+// IsValidatorSetHash returns whether num is either equal to the ValidatorSetHash fork block or greater.
+// From the fork on, the Tendermint checkpoint headers record the hash of the validator set they hand off, which is
+// covered by the block hash, so the committed seals authenticate the validators of the next epoch.
+func (c *ChainConfig) IsValidatorSetHash(num *big.Int) bool {
+	return isForked(c.ValidatorSetHashBlock, num)
+}
+
+// IsEvidence returns whether num is either equal to the Evidence fork block or greater.
+// From the fork on, the Tendermint blocks may include evidences of double signing, whose offenders are slashed.
+func (c *ChainConfig) IsEvidence(num *big.Int) bool {
+	return isForked(c.EvidenceBlock, num)
+}
+
+// IsLiveness returns whether num is either equal to the Liveness fork block or greater.
+// From the fork on, the Tendermint blocks keep every precommit seal and record the committed seals of their parent,
+// from which the validators missing too many blocks are jailed.
+func (c *ChainConfig) IsLiveness(num *big.Int) bool {
+	return isForked(c.LivenessBlock, num)
+}
+
+// GasBudgetEpochLength returns the number of blocks of the epochs of the gas budgets of enterprise contracts.
+// Unless the chain config sets it, the epochs are the Tendermint epochs if the chain has them.
+func (c *ChainConfig) GasBudgetEpochLength() uint64 {
+	switch {
+	case c.GasBudgetEpoch != 0:
+		return c.GasBudgetEpoch
+	case c.Tendermint != nil && c.Tendermint.Epoch != 0:
+		return c.Tendermint.Epoch
+	default:
+		return GasBudgetEpochLength
+	}
+}
+
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
 func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	return GasTableOmaha
@@ -257,6 +355,33 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.FeeMarketBlock, newcfg.FeeMarketBlock, head) {
+		return newCompatError("fee market fork block", c.FeeMarketBlock, newcfg.FeeMarketBlock)
+	}
+	if isForkIncompatible(c.EnterpriseLogsBlock, newcfg.EnterpriseLogsBlock, head) {
+		return newCompatError("enterprise logs fork block", c.EnterpriseLogsBlock, newcfg.EnterpriseLogsBlock)
+	}
+	if isForkIncompatible(c.ValidatorSetBlock, newcfg.ValidatorSetBlock, head) {
+		return newCompatError("validator set fork block", c.ValidatorSetBlock, newcfg.ValidatorSetBlock)
+	}
+	if isForkIncompatible(c.GasPayerBlock, newcfg.GasPayerBlock, head) {
+		return newCompatError("gas payer fork block", c.GasPayerBlock, newcfg.GasPayerBlock)
+	}
+	if isForkIncompatible(c.OwnershipTransferBlock, newcfg.OwnershipTransferBlock, head) {
+		return newCompatError("ownership transfer fork block", c.OwnershipTransferBlock, newcfg.OwnershipTransferBlock)
+	}
+	if isForkIncompatible(c.GasBudgetBlock, newcfg.GasBudgetBlock, head) {
+		return newCompatError("gas budget fork block", c.GasBudgetBlock, newcfg.GasBudgetBlock)
+	}
+	if isForkIncompatible(c.ValidatorSetHashBlock, newcfg.ValidatorSetHashBlock, head) {
+		return newCompatError("validator set hash fork block", c.ValidatorSetHashBlock, newcfg.ValidatorSetHashBlock)
+	}
+	if isForkIncompatible(c.EvidenceBlock, newcfg.EvidenceBlock, head) {
+		return newCompatError("evidence fork block", c.EvidenceBlock, newcfg.EvidenceBlock)
+	}
+	if isForkIncompatible(c.LivenessBlock, newcfg.LivenessBlock, head) {
+		return newCompatError("liveness fork block", c.LivenessBlock, newcfg.LivenessBlock)
+	}
 	return nil
 }
 
@@ -321,8 +446,10 @@ func (err *ConfigCompatError) Error() string {
 // Rules is a one time interface meaning that it shouldn't be used in between transition
 // phases.
 type Rules struct {
-	ChainID     *big.Int
-	IsVierville bool
+	ChainID        *big.Int
+	IsVierville    bool
+	IsValidatorSet bool
+	IsGasPayer     bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -332,7 +459,9 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		chainID = new(big.Int)
 	}
 	return Rules{
-		ChainID:     new(big.Int).Set(chainID),
-		IsVierville: c.IsVierville(num),
+		ChainID:        new(big.Int).Set(chainID),
+		IsVierville:    c.IsVierville(num),
+		IsValidatorSet: c.IsValidatorSet(num),
+		IsGasPayer:     c.IsGasPayer(num),
 	}
 }
@@ -85,9 +85,17 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+	ValidatorSetGas         uint64 = 2000   // Base gas needed to read the validators, proposer and parent seals of the block
+	ValidatorSetPerValGas   uint64 = 200    // Per-validator gas needed to read and return the validators of the block
+	GasPayerGas             uint64 = 100    // Gas needed to read the gas payer of the transaction
+
+	BaseFeeElasticityMultiplier uint64 = 2 // Bounds the gas target of a block to its gas limit divided by this multiplier
+	BaseFeeMaxMultiplier        uint64 = 8 // Bounds the base fee of a block to the minimal base fee times this multiplier
 
 	// TODO: change this to chainConfig
 	MaxProvider = 16 // Maximum of provider size for an enterprise contract
+
+	GasBudgetEpochLength uint64 = 30000 // Number of blocks of the epochs of the provider gas budgets, unless the chain config sets one or has Tendermint epochs
 )
 
 var (
@@ -31,6 +31,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/accounts/usbwallet"
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/internal/neutapi"
 	"github.com/lvbin2012/NeuralChain/log"
@@ -118,6 +119,7 @@ type SignerAPI struct {
 	validator   Validator
 	rejectMode  bool
 	credentials storage.Storage
+	votes       *privval.Guard // votes is the double-sign protection of the Tendermint validators
 }
 
 // Metadata about a request
@@ -240,6 +242,8 @@ type (
 		Message     []*NameValueType        `json:"message"`
 		Hash        hexutil.Bytes           `json:"hash"`
 		Meta        Metadata                `json:"meta"`
+
+		vote *privval.VoteRequest // vote is the Tendermint vote signed under the double-sign protection
 	}
 	SignDataResponse struct {
 		Approved bool `json:"approved"`
@@ -280,11 +284,15 @@ var ErrRequestDenied = errors.New("Request denied")
 // key that is generated when a new Account is created.
 // noUSB disables USB support that is required to support hardware devices such as
 // ledger and trezor.
-func NewSignerAPI(am *accounts.Manager, chainID int64, noUSB bool, ui UIClientAPI, validator Validator, advancedMode bool, credentials storage.Storage) *SignerAPI {
+// The votes guard refuses to sign conflicting Tendermint votes, nil keeps the records of the votes in memory.
+func NewSignerAPI(am *accounts.Manager, chainID int64, noUSB bool, ui UIClientAPI, validator Validator, advancedMode bool, credentials storage.Storage, votes *privval.Guard) *SignerAPI {
 	if advancedMode {
 		log.Info("Clef is in advanced mode: will warn instead of reject")
 	}
-	signer := &SignerAPI{big.NewInt(chainID), am, ui, validator, !advancedMode, credentials}
+	if votes == nil {
+		votes, _ = privval.NewGuard("")
+	}
+	signer := &SignerAPI{big.NewInt(chainID), am, ui, validator, !advancedMode, credentials, votes}
 	if !noUSB {
 		signer.startUSBListener()
 	}
@@ -36,6 +36,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
 	"github.com/lvbin2012/NeuralChain/common/math"
 	"github.com/lvbin2012/NeuralChain/consensus/clique"
+	"github.com/lvbin2012/NeuralChain/consensus/tendermint/privval"
 	"github.com/lvbin2012/NeuralChain/core/types"
 	"github.com/lvbin2012/NeuralChain/crypto"
 	"github.com/lvbin2012/NeuralChain/rlp"
@@ -171,7 +172,15 @@ func (api *SignerAPI) SignData(ctx context.Context, contentType string, addr com
 		return nil, err
 	}
 
-	signature, err := api.sign(addr, req, transformV)
+	var signature hexutil.Bytes
+	if req.vote != nil {
+		// Tendermint votes are only signed if they don't conflict with the last vote of the validator
+		signature, err = api.votes.SignVote(addr.Address(), req.vote, func() ([]byte, error) {
+			return api.sign(addr, req, transformV)
+		})
+	} else {
+		signature, err = api.sign(addr, req, transformV)
+	}
 	if err != nil {
 		api.UI.ShowError(err.Error())
 		return nil, err
@@ -262,6 +271,49 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useNeuralChainV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Message: message, Hash: sighash}
+	case accounts.MimetypeTendermint, accounts.MimetypeTendermintVote:
+		// Tendermint validators sign the consensus data as is
+		stringData, ok := data.(string)
+		if !ok {
+			return nil, useNeuralChainV, fmt.Errorf("input for %v must be an hex-encoded string", mediaType)
+		}
+		tendermintData, err := hexutil.Decode(stringData)
+		if err != nil {
+			return nil, useNeuralChainV, err
+		}
+		message := []*NameValueType{
+			{
+				Name:  "Tendermint data",
+				Typ:   "tendermint",
+				Value: fmt.Sprintf("0x%x", tendermintData),
+			},
+		}
+		var vote *privval.VoteRequest
+		if mediaType == accounts.MimetypeTendermint && privval.IsVoteData(tendermintData) {
+			// votes signed without their position would bypass the double-sign protection
+			return nil, useNeuralChainV, fmt.Errorf("votes must be signed as %v", accounts.MimetypeTendermintVote)
+		}
+		if mediaType == accounts.MimetypeTendermintVote {
+			// Votes come with their position in the consensus for the double-sign protection
+			vote = new(privval.VoteRequest)
+			if err := rlp.DecodeBytes(tendermintData, vote); err != nil {
+				return nil, useNeuralChainV, err
+			}
+			if err := vote.VerifyData(); err != nil {
+				return nil, useNeuralChainV, err
+			}
+			tendermintData = vote.Data
+			message = []*NameValueType{
+				{
+					Name:  "Tendermint vote",
+					Typ:   "tendermint",
+					Value: fmt.Sprintf("step %d of round %d at height %d for block [0x%x]", vote.Step, vote.Round, vote.Height, vote.BlockHash),
+				},
+			}
+		}
+		// Tendermint uses V on the form 0 or 1
+		useNeuralChainV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: tendermintData, Message: message, Hash: crypto.Keccak256(tendermintData), vote: vote}
 	default: // also case TextPlain.Mime:
 		// Calculates an NeuralChain ECDSA signature for:
 		// hash = keccak256("\x19${byteVersion}NeuralChainNode Signed Message:\n${message length}${message}")
@@ -25,6 +25,7 @@ import (
 	"github.com/lvbin2012/NeuralChain/common"
 	"github.com/lvbin2012/NeuralChain/common/hexutil"
 	"github.com/lvbin2012/NeuralChain/core/types"
+	"github.com/lvbin2012/NeuralChain/internal/neutapi"
 )
 
 type ValidationInfo struct {
@@ -76,6 +77,14 @@ type SendTxArgs struct {
 	Data     *hexutil.Bytes           `json:"data"`            // We accept "data" and "input" for backwards-compatibility reasons.
 	Input    *hexutil.Bytes           `json:"input,omitempty"` // We accept "data" and "input" for backwards-compatibility reasons.
 	Provider *common.Address          `json:"provider" rlp:"nil"`
+	// NewOwner proposes a new owner for the enterprise contract To, AcceptOwnership accepts
+	// the ownership of To by its pending owner.
+	NewOwner        *common.Address `json:"newOwner,omitempty" rlp:"nil"`
+	AcceptOwnership bool            `json:"acceptOwnership,omitempty"`
+	// ProviderBudget sets the gas budget per epoch of a provider of the enterprise contract To,
+	// SenderAllowance sets the gas allowance per epoch of a sender.
+	ProviderBudget  *neutapi.GasLimitArgs `json:"providerBudget,omitempty" rlp:"nil"`
+	SenderAllowance *neutapi.GasLimitArgs `json:"senderAllowance,omitempty" rlp:"nil"`
 }
 
 func (args SendTxArgs) String() string {
@@ -96,5 +105,22 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	if args.To == nil {
 		return types.NewContractCreation(uint64(args.Nonce), (*big.Int)(&args.Value), uint64(args.Gas), (*big.Int)(&args.GasPrice), input)
 	}
+	// encoding the fixed size messages of the enterprise contract transactions never fails
+	if args.NewOwner != nil {
+		tx, _ := types.NewTransferOwnershipTransaction(uint64(args.Nonce), args.To.Address(), uint64(args.Gas), (*big.Int)(&args.GasPrice), *args.NewOwner)
+		return tx
+	}
+	if args.AcceptOwnership {
+		tx, _ := types.NewAcceptOwnershipTransaction(uint64(args.Nonce), args.To.Address(), uint64(args.Gas), (*big.Int)(&args.GasPrice))
+		return tx
+	}
+	if args.ProviderBudget != nil {
+		tx, _ := types.NewSetGasLimitTransaction(uint64(args.Nonce), args.To.Address(), uint64(args.Gas), (*big.Int)(&args.GasPrice), args.ProviderBudget.Address, uint64(args.ProviderBudget.Limit), true)
+		return tx
+	}
+	if args.SenderAllowance != nil {
+		tx, _ := types.NewSetGasLimitTransaction(uint64(args.Nonce), args.To.Address(), uint64(args.Gas), (*big.Int)(&args.GasPrice), args.SenderAllowance.Address, uint64(args.SenderAllowance.Limit), false)
+		return tx
+	}
 	return types.NewTransaction(uint64(args.Nonce), args.To.Address(), (*big.Int)(&args.Value), (uint64)(args.Gas), (*big.Int)(&args.GasPrice), input)
 }
@@ -46,6 +46,12 @@ func (c *MockChainReader) GetHeaderByNumber(blockNumber uint64) *types.Header {
 	return c.GenesisHeader
 }
 
+//LatestCheckpoint implement a mock version of chainReader.LatestCheckpoint
+//It returns genesis Header, which is the only checkpoint of the mock chain
+func (c *MockChainReader) LatestCheckpoint(header *types.Header) *types.Header {
+	return c.GenesisHeader
+}
+
 func (c *MockChainReader) GetHeaderByHash(hash common.Hash) *types.Header {
 	panic("implement me")
 }